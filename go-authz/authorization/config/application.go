@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
@@ -31,6 +32,44 @@ type ApplicationConfiguration struct {
 	AppEnv          string `mapstructure:"APP_ENV"`
 	AppName         string `mapstructure:"APP_NAME"`
 
+	// Number of proxies in front of Envoy appending to x-forwarded-for, the client IP is read that many entries
+	// from the right, 0 uses the address of the downstream peer
+	AppExtAuthzTrustedHops int `mapstructure:"APP_EXT_AUTHZ_TRUSTED_HOPS"`
+
+	// Emails of the operators allowed to use the admin endpoints, comma separated
+	AppAdminEmails []string `mapstructure:"APP_ADMIN_EMAILS"`
+
+	// How long an archived team can be restored before it is deleted for good
+	TeamDeletionGracePeriod time.Duration `mapstructure:"TEAM_DELETION_GRACE_PERIOD"`
+
+	// Plan of the teams without a plan of their own, one of the plans of plans.yml
+	TeamDefaultPlan string `mapstructure:"TEAM_DEFAULT_PLAN"`
+
+	// Page size of the paginated lists when the request does not set one, and the largest one a request can ask for
+	PaginationDefaultLimit int `mapstructure:"PAGINATION_DEFAULT_LIMIT"`
+	PaginationMaxLimit     int `mapstructure:"PAGINATION_MAX_LIMIT"`
+
+	// Bulk invitations up to the sync limit are processed within the request, the larger ones in the background
+	InvitationImportSyncLimit int `mapstructure:"INVITATION_IMPORT_SYNC_LIMIT"`
+	InvitationImportMaxRows   int `mapstructure:"INVITATION_IMPORT_MAX_ROWS"`
+
+	// How long an invitation is valid and when its reminders are sent before it expires, unless the team sets its own
+	InvitationExpiry          time.Duration   `mapstructure:"INVITATION_EXPIRY"`
+	InvitationReminderOffsets []time.Duration `mapstructure:"INVITATION_REMINDER_OFFSETS"`
+
+	// How often the invitations past their expiry are marked expired
+	InvitationExpirySweepInterval time.Duration `mapstructure:"INVITATION_EXPIRY_SWEEP_INTERVAL"`
+
+	// How often the memberships past their expiry are removed
+	MembershipExpirySweepInterval time.Duration `mapstructure:"MEMBERSHIP_EXPIRY_SWEEP_INTERVAL"`
+
+	// How long a member can hold an elevated role
+	ElevationMaxDuration time.Duration `mapstructure:"ELEVATION_MAX_DURATION"`
+
+	// How long before its deadline the reviewers of an access review are reminded, and how often the reviews are swept
+	AccessReviewReminderOffsets []time.Duration `mapstructure:"ACCESS_REVIEW_REMINDER_OFFSETS"`
+	AccessReviewSweepInterval   time.Duration   `mapstructure:"ACCESS_REVIEW_SWEEP_INTERVAL"`
+
 	// JWT
 	AccessTokenKID         string        `mapstructure:"ACCESS_TOKEN_KID"`
 	AccessTokenPrivateKey  string        `mapstructure:"ACCESS_TOKEN_PRIVATE_KEY"`
@@ -48,14 +87,39 @@ type ApplicationConfiguration struct {
 	GoogleOAuthRedirectUrl string `mapstructure:"GOOGLE_OAUTH_REDIRECT_URL"`
 }
 
+// IsAdmin tells whether the email is one of the operators listed in APP_ADMIN_EMAILS.
+func (c ApplicationConfiguration) IsAdmin(email string) bool {
+	for _, admin := range c.AppAdminEmails {
+		if strings.EqualFold(strings.TrimSpace(admin), email) {
+			return true
+		}
+	}
+	return false
+}
+
 func loadAppConfig(path string) (config ApplicationConfiguration, err error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigType("env")
 	viper.SetConfigName("app")
 	viper.SetDefault("APP_PORT", "8888")
 	viper.SetDefault("APP_EXT_AUTHZ_PORT", "8889")
+	viper.SetDefault("APP_EXT_AUTHZ_TRUSTED_HOPS", 0)
 	viper.SetDefault("APP_ENV", "development")
 	viper.SetDefault("APP_NAME", "svc-authorization")
+	viper.SetDefault("APP_ADMIN_EMAILS", "")
+	viper.SetDefault("TEAM_DELETION_GRACE_PERIOD", "720h")
+	viper.SetDefault("TEAM_DEFAULT_PLAN", "standard")
+	viper.SetDefault("PAGINATION_DEFAULT_LIMIT", 20)
+	viper.SetDefault("PAGINATION_MAX_LIMIT", 100)
+	viper.SetDefault("INVITATION_IMPORT_SYNC_LIMIT", 50)
+	viper.SetDefault("INVITATION_IMPORT_MAX_ROWS", 5000)
+	viper.SetDefault("INVITATION_EXPIRY", "168h")
+	viper.SetDefault("INVITATION_REMINDER_OFFSETS", "72h,24h")
+	viper.SetDefault("INVITATION_EXPIRY_SWEEP_INTERVAL", "1h")
+	viper.SetDefault("MEMBERSHIP_EXPIRY_SWEEP_INTERVAL", "15m")
+	viper.SetDefault("ELEVATION_MAX_DURATION", "24h")
+	viper.SetDefault("ACCESS_REVIEW_REMINDER_OFFSETS", "72h,24h")
+	viper.SetDefault("ACCESS_REVIEW_SWEEP_INTERVAL", "1h")
 	viper.AutomaticEnv()
 
 	err = viper.ReadInConfig()
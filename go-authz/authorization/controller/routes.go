@@ -21,6 +21,16 @@ func CreateRouter() {
 	authControllerV1 := v1.NewAuthController()
 	teamControllerV1 := v1.NewTeamController()
 	invitationControllerV1 := v1.NewInvitationController()
+	relationControllerV1 := v1.NewRelationController()
+	policyControllerV1 := v1.NewPolicyController()
+	authzControllerV1 := v1.NewAuthzController()
+	organizationControllerV1 := v1.NewOrganizationController()
+	ownershipControllerV1 := v1.NewOwnershipController()
+	joinLinkControllerV1 := v1.NewJoinLinkController()
+	joinRequestControllerV1 := v1.NewJoinRequestController()
+	elevationControllerV1 := v1.NewElevationController()
+	accessReviewControllerV1 := v1.NewAccessReviewController()
+	teamDomainControllerV1 := v1.NewTeamDomainController()
 
 	docs.SwaggerInfo.BasePath = "/api/v1"
 
@@ -40,9 +50,39 @@ func CreateRouter() {
 	//invitation routes
 	invitationControllerV1.Routes(routerV1)
 
+	//relation routes
+	relationControllerV1.Routes(routerV1)
+
+	//organization routes
+	organizationControllerV1.Routes(routerV1)
+
 	//team routes
 	teamControllerV1.Routes(routerV1)
 
+	//ownership transfer routes
+	ownershipControllerV1.Routes(routerV1)
+
+	//join link routes
+	joinLinkControllerV1.Routes(routerV1)
+
+	//join request routes
+	joinRequestControllerV1.Routes(routerV1)
+
+	//elevation request routes
+	elevationControllerV1.Routes(routerV1)
+
+	//access review routes
+	accessReviewControllerV1.Routes(routerV1)
+
+	//team domain routes
+	teamDomainControllerV1.Routes(routerV1)
+
+	//policy routes
+	policyControllerV1.Routes(routerV1)
+
+	//authz routes
+	authzControllerV1.Routes(routerV1)
+
 	//user routes
 	userControllerV1.Routes(routerV1)
 
@@ -0,0 +1,283 @@
+package v1
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/middleware"
+	"authorization/service/handlers"
+	"authorization/view"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+type AccessReviewController interface {
+	Routes(*gin.RouterGroup)
+}
+
+type accessReviewController struct{}
+
+// NewAccessReviewController -> returns new access review controller
+func NewAccessReviewController() AccessReviewController {
+	return &accessReviewController{}
+}
+
+func (ctrl *accessReviewController) Routes(route *gin.RouterGroup) {
+	review := route.Group("/access-reviews")
+	review.POST("", middleware.DeserializeUser(), ctrl.StartAccessReview)
+	review.GET("", middleware.DeserializeUser(), middleware.RequireAdmin(), ctrl.GetAccessReviews)
+	review.GET("/:review_id", middleware.DeserializeUser(), ctrl.GetAccessReview)
+	review.GET("/:review_id/export", middleware.DeserializeUser(), ctrl.ExportAccessReview)
+	review.POST("/:review_id/items/:item_id", middleware.DeserializeUser(), ctrl.ReviewAccess)
+
+	team := route.Group("/teams/:id")
+	team.GET("/access-reviews", middleware.DeserializeUser(), ctrl.GetTeamAccessReviews)
+}
+
+// @Summary Start access review
+// @Schemes
+// @Description Start an access review of the memberships of one or more teams, only the owners of every team and the administrators can
+// @Tags Access Review
+// @Accept json
+// @Produce json
+// @Param request body command.StartAccessReview true "Name, teams, reviewed roles, deadline and auto-revocation"
+// @Success 201 {string} string "OK"
+// @Router /access-reviews [post]
+func (ctrl *accessReviewController) StartAccessReview(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+	log.Debug().Caller().Msg("Start access review")
+
+	var cmd command.StartAccessReview
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.User = currentUser
+
+	err := handlers.StartAccessReview(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to start access review")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "message": "OK", "data": gin.H{"review_id": cmd.ReviewID}})
+}
+
+// @Summary Get access reviews
+// @Schemes
+// @Description Get every access review, only the administrators can
+// @Tags Access Review
+// @Accept json
+// @Produce json
+// @Param status query string false "Statuses of the reviews, comma separated"
+// @Param order query string false "asc or desc"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the page"
+// @Success 200 {object} dto.CursorPagination
+// @Router /access-reviews [get]
+func (ctrl *accessReviewController) GetAccessReviews(ctx *gin.Context) {
+	log.Debug().Caller().Msg("Get access reviews")
+	ctrl.getAccessReviews(ctx, uuid.Nil)
+}
+
+// @Summary Get team access reviews
+// @Schemes
+// @Description Get the access reviews covering the team
+// @Tags Access Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param status query string false "Statuses of the reviews, comma separated"
+// @Param order query string false "asc or desc"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the page"
+// @Success 200 {object} dto.CursorPagination
+// @Router /teams/{id}/access-reviews [get]
+func (ctrl *accessReviewController) GetTeamAccessReviews(ctx *gin.Context) {
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get team access reviews")
+
+	teamID := uuid.FromStringOrNil(id)
+	if teamID == uuid.Nil {
+		_ = ctx.Error(exception.NewBadRequestException(fmt.Sprintf("invalid team ID %s", id)))
+		return
+	}
+
+	ctrl.getAccessReviews(ctx, teamID)
+}
+
+func (ctrl *accessReviewController) getAccessReviews(ctx *gin.Context, teamID uuid.UUID) {
+	limit, cursor, err := pageQuery(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	opts := domain.AccessReviewOptions{
+		TeamID:     teamID,
+		Descending: ctx.Query("order") == "desc",
+		Limit:      limit,
+		Cursor:     cursor,
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		for _, value := range strings.Split(status, ",") {
+			opts.Statuses = append(opts.Statuses, domain.AccessReviewStatus(strings.TrimSpace(value)))
+		}
+	}
+
+	reviews, err := view.AccessReviews(ctx.Request.Context(), opts)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get access reviews")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": reviews})
+}
+
+// @Summary Get access review
+// @Schemes
+// @Description Get an access review with the memberships you can review, the creator of the review and the administrators see every membership
+// @Tags Access Review
+// @Accept json
+// @Produce json
+// @Param review_id path string true "Access review ID"
+// @Success 200 {object} dto.AccessReviewSchema
+// @Router /access-reviews/{review_id} [get]
+func (ctrl *accessReviewController) GetAccessReview(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get access review ID from request parameter
+	reviewIDString := ctx.Param("review_id")
+	log.Debug().Caller().Str("review_id", reviewIDString).Msg("Get access review")
+
+	reviewID, err := ulid.Parse(reviewIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	review, err := view.AccessReview(ctx.Request.Context(), reviewID, currentUser)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get access review")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": review})
+}
+
+// @Summary Export access review
+// @Schemes
+// @Description Download the decisions of an access review as CSV or JSON, with the memberships you can review
+// @Tags Access Review
+// @Produce json,text/csv
+// @Param review_id path string true "Access review ID"
+// @Param format query string false "csv or json, csv by default"
+// @Success 200 {file} file
+// @Router /access-reviews/{review_id}/export [get]
+func (ctrl *accessReviewController) ExportAccessReview(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get access review ID from request parameter
+	reviewIDString := ctx.Param("review_id")
+	format := ctx.DefaultQuery("format", "csv")
+	log.Debug().Caller().Str("review_id", reviewIDString).Str("format", format).Msg("Export access review")
+
+	reviewID, err := ulid.Parse(reviewIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	filename := fmt.Sprintf("access-review-%s.%s", reviewID, format)
+	switch format {
+	case "csv":
+		data, err := view.AccessReviewCSV(ctx.Request.Context(), reviewID, currentUser)
+		if err != nil {
+			log.Error().Caller().Err(err).Msg("Failed to export access review")
+			_ = ctx.Error(err)
+			return
+		}
+
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		ctx.Data(http.StatusOK, "text/csv", data)
+	case "json":
+		review, err := view.AccessReview(ctx.Request.Context(), reviewID, currentUser)
+		if err != nil {
+			log.Error().Caller().Err(err).Msg("Failed to export access review")
+			_ = ctx.Error(err)
+			return
+		}
+
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		ctx.JSON(http.StatusOK, review)
+	default:
+		_ = ctx.Error(exception.NewBadRequestException(fmt.Sprintf("unsupported export format %s", format)))
+	}
+}
+
+// @Summary Review access
+// @Schemes
+// @Description Confirm or revoke a membership under review, a revoked membership is removed from its team right away
+// @Tags Access Review
+// @Accept json
+// @Produce json
+// @Param review_id path string true "Access review ID"
+// @Param item_id path string true "Access review item ID"
+// @Param request body command.ReviewAccess true "Decision and note"
+// @Success 200 {string} string "OK"
+// @Router /access-reviews/{review_id}/items/{item_id} [post]
+func (ctrl *accessReviewController) ReviewAccess(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get access review ID and item ID from request parameter
+	reviewIDString := ctx.Param("review_id")
+	itemIDString := ctx.Param("item_id")
+	log.Debug().Caller().Str("review_id", reviewIDString).Str("item_id", itemIDString).Msg("Review access")
+
+	reviewID, err := ulid.Parse(reviewIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	itemID, err := ulid.Parse(itemIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	var cmd command.ReviewAccess
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.ReviewID = reviewID
+	cmd.ItemID = itemID
+	cmd.User = currentUser
+
+	err = handlers.ReviewAccess(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to review access")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
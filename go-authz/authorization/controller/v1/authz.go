@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/middleware"
+	"authorization/view"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// AuthzController : represent the authorization debugging controller contract
+type AuthzController interface {
+	Explain(*gin.Context)
+	Routes(*gin.RouterGroup)
+}
+
+type authzController struct{}
+
+// NewAuthzController -> returns new authz controller
+func NewAuthzController() AuthzController {
+	return &authzController{}
+}
+
+func (ctrl *authzController) Routes(route *gin.RouterGroup) {
+	authz := route.Group("/authz")
+	authz.POST("/explain", middleware.DeserializeUser(), middleware.RequireAdmin(), ctrl.Explain)
+}
+
+// @Summary Explain authorization decision
+// @Schemes
+// @Description Replay the ext-authz decision for a request and return the trace: matched endpoint, team, membership, role, relation and policies.
+// @Description The path is the gateway path, e.g. /auth/v1/teams/{id}. The user defaults to the current user.
+// @Tags Authorization
+// @Accept json
+// @Produce json
+// @Param body body command.ExplainAuthorization true "Request to explain"
+// @Success 200 {object} dto.DecisionSchema
+// @Router /authz/explain [post]
+func (ctrl *authzController) Explain(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	var cmd command.ExplainAuthorization
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if cmd.UserID == "" {
+		cmd.UserID = currentUser.ID.String()
+	}
+
+	log.Debug().Caller().Str("user_id", cmd.UserID).Str("method", cmd.Method).Str("path", cmd.Path).Msg("Explain authorization decision")
+
+	decision, err := view.Explain(ctx.Request.Context(), domain.AccessRequest{
+		UserID: cmd.UserID,
+		Method: strings.ToUpper(cmd.Method),
+		Path:   strings.Split(cmd.Path, "?")[0],
+		IP:     cmd.IP,
+	})
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to explain authorization decision")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": decision})
+}
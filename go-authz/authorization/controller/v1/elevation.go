@@ -0,0 +1,281 @@
+package v1
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/middleware"
+	"authorization/service/handlers"
+	"authorization/view"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+type ElevationController interface {
+	Routes(*gin.RouterGroup)
+}
+
+type elevationController struct{}
+
+// NewElevationController -> returns new elevation controller
+func NewElevationController() ElevationController {
+	return &elevationController{}
+}
+
+func (ctrl *elevationController) Routes(route *gin.RouterGroup) {
+	team := route.Group("/teams/:id")
+	team.POST("/elevation-requests", middleware.DeserializeUser(), ctrl.RequestElevation)
+	team.GET("/elevation-requests", middleware.DeserializeUser(), ctrl.GetElevations)
+	team.GET("/elevation-requests/:request_id", middleware.DeserializeUser(), ctrl.GetElevation)
+	team.POST("/elevation-requests/:request_id/approve", middleware.DeserializeUser(), ctrl.ApproveElevation)
+	team.POST("/elevation-requests/:request_id/deny", middleware.DeserializeUser(), ctrl.DenyElevation)
+	team.DELETE("/elevation-requests/:request_id", middleware.DeserializeUser(), ctrl.CancelElevation)
+}
+
+// @Summary Request elevation
+// @Schemes
+// @Description Request a higher role in the team for a few hours with a justification, the request is approved by the auto-approval policies of the team or by an owner
+// @Tags Elevation
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body command.RequestElevation true "Role, hours and justification"
+// @Success 201 {string} string "OK"
+// @Router /teams/{id}/elevation-requests [post]
+func (ctrl *elevationController) RequestElevation(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Request elevation")
+
+	var cmd command.RequestElevation
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.RequestElevation(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to request elevation")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "message": "OK", "data": gin.H{"request_id": cmd.RequestID}})
+}
+
+// @Summary Get elevation requests
+// @Schemes
+// @Description Get the elevation requests of the team, the pending and active ones unless a status is given
+// @Tags Elevation
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param status query string false "Statuses of the requests, comma separated"
+// @Param order query string false "asc or desc"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the page"
+// @Success 200 {object} dto.CursorPagination
+// @Router /teams/{id}/elevation-requests [get]
+func (ctrl *elevationController) GetElevations(ctx *gin.Context) {
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get elevation requests")
+
+	limit, cursor, err := pageQuery(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	opts := domain.ElevationOptions{
+		TeamID:     uuid.FromStringOrNil(id),
+		Descending: ctx.Query("order") == "desc",
+		Limit:      limit,
+		Cursor:     cursor,
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		for _, value := range strings.Split(status, ",") {
+			opts.Statuses = append(opts.Statuses, domain.ElevationStatus(strings.TrimSpace(value)))
+		}
+	}
+
+	requests, err := view.TeamElevations(ctx.Request.Context(), opts)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get elevation requests")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": requests})
+}
+
+// @Summary Get elevation request
+// @Schemes
+// @Description Get an elevation request with every step recorded for it, only the requester and the members who can review the requests of the team can
+// @Tags Elevation
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request_id path string true "Elevation request ID"
+// @Success 200 {object} dto.ElevationRequestSchema
+// @Router /teams/{id}/elevation-requests/{request_id} [get]
+func (ctrl *elevationController) GetElevation(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID and elevation request ID from request parameter
+	id := ctx.Param("id")
+	requestIDString := ctx.Param("request_id")
+	log.Debug().Caller().Str("id", id).Str("request_id", requestIDString).Msg("Get elevation request")
+
+	requestID, err := ulid.Parse(requestIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	request, err := view.Elevation(ctx.Request.Context(), uuid.FromStringOrNil(id), requestID, currentUser)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get elevation request")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": request})
+}
+
+// @Summary Approve elevation request
+// @Schemes
+// @Description Give the requested role to the member until the elevation ends, when the member goes back to its role
+// @Tags Elevation
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request_id path string true "Elevation request ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/elevation-requests/{request_id}/approve [post]
+func (ctrl *elevationController) ApproveElevation(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID and elevation request ID from request parameter
+	id := ctx.Param("id")
+	requestIDString := ctx.Param("request_id")
+	log.Debug().Caller().Str("id", id).Str("request_id", requestIDString).Msg("Approve elevation request")
+
+	requestID, err := ulid.Parse(requestIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	cmd := command.ApproveElevation{
+		TeamID:    uuid.FromStringOrNil(id),
+		RequestID: requestID,
+		User:      currentUser,
+	}
+
+	err = handlers.ApproveElevation(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to approve elevation request")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Deny elevation request
+// @Schemes
+// @Description Deny an elevation request, the member is notified and can request again
+// @Tags Elevation
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request_id path string true "Elevation request ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/elevation-requests/{request_id}/deny [post]
+func (ctrl *elevationController) DenyElevation(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID and elevation request ID from request parameter
+	id := ctx.Param("id")
+	requestIDString := ctx.Param("request_id")
+	log.Debug().Caller().Str("id", id).Str("request_id", requestIDString).Msg("Deny elevation request")
+
+	requestID, err := ulid.Parse(requestIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	cmd := command.DenyElevation{
+		TeamID:    uuid.FromStringOrNil(id),
+		RequestID: requestID,
+		User:      currentUser,
+	}
+
+	err = handlers.DenyElevation(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to deny elevation request")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Cancel elevation request
+// @Schemes
+// @Description Withdraw your pending elevation request, or give up your active elevation and go back to your role
+// @Tags Elevation
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request_id path string true "Elevation request ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/elevation-requests/{request_id} [delete]
+func (ctrl *elevationController) CancelElevation(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID and elevation request ID from request parameter
+	id := ctx.Param("id")
+	requestIDString := ctx.Param("request_id")
+	log.Debug().Caller().Str("id", id).Str("request_id", requestIDString).Msg("Cancel elevation request")
+
+	requestID, err := ulid.Parse(requestIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	cmd := command.CancelElevation{
+		TeamID:    uuid.FromStringOrNil(id),
+		RequestID: requestID,
+		User:      currentUser,
+	}
+
+	err = handlers.CancelElevation(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to cancel elevation request")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
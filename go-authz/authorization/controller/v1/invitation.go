@@ -18,6 +18,7 @@ import (
 // InvitationController : represent the invitation's controller contract
 type InvitationController interface {
 	VerifyInvitation(*gin.Context)
+	ClaimInvitation(*gin.Context)
 	GetInvitationByID(*gin.Context)
 	DeleteInvitation(*gin.Context)
 	Routes(*gin.RouterGroup)
@@ -33,6 +34,7 @@ func NewInvitationController() InvitationController {
 func (ctrl *invitationController) Routes(route *gin.RouterGroup) {
 	invitation := route.Group("/invitations")
 	invitation.POST("/verify", middleware.DeserializeUser(), ctrl.VerifyInvitation)
+	invitation.POST("/claim", middleware.DeserializeUser(), ctrl.ClaimInvitation)
 	invitation.GET("/:id/check", ctrl.GetInvitationByID)
 	invitation.DELETE("/:id", middleware.DeserializeUser(), ctrl.DeleteInvitation)
 }
@@ -69,6 +71,36 @@ func (ctrl *invitationController) VerifyInvitation(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
 }
 
+// @Summary Claim invitation
+// @Schemes
+// @Description Accept the invitation of the link token with the account of the current user, whatever its email address. The owners and admins of the team are told when the address differs from the invited one.
+// @Tags Membership
+// @Accept json
+// @Produce json
+// @Param request body command.ClaimInvitation true "Invitation token"
+// @Success 200 {string} string "OK"
+// @Router /invitations/claim [post]
+func (ctrl *invitationController) ClaimInvitation(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	var cmd command.ClaimInvitation
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.User = currentUser
+
+	err := handlers.ClaimInvitation(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("could not claim invitation")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK", "data": gin.H{"team_id": cmd.TeamID}})
+}
+
 // @Summary Get invitation by ID
 // @Schemes
 // @Description Get invitation data by ID
@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/middleware"
+	"authorization/service/handlers"
+	"authorization/view"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+// JoinLinkController : represent the join link's controller contract
+type JoinLinkController interface {
+	CreateJoinLink(*gin.Context)
+	GetJoinLinks(*gin.Context)
+	RevokeJoinLink(*gin.Context)
+	RedeemJoinLink(*gin.Context)
+	Routes(*gin.RouterGroup)
+}
+
+type joinLinkController struct{}
+
+// NewJoinLinkController -> returns new join link controller
+func NewJoinLinkController() JoinLinkController {
+	return &joinLinkController{}
+}
+
+func (ctrl *joinLinkController) Routes(route *gin.RouterGroup) {
+	team := route.Group("/teams/:id")
+	team.POST("/join-links", middleware.DeserializeUser(), ctrl.CreateJoinLink)
+	team.GET("/join-links", middleware.DeserializeUser(), ctrl.GetJoinLinks)
+	team.DELETE("/join-links/:link_id", middleware.DeserializeUser(), ctrl.RevokeJoinLink)
+
+	route.POST("/join/:token", middleware.DeserializeUser(), ctrl.RedeemJoinLink)
+}
+
+// @Summary Create join link
+// @Schemes
+// @Description Create a shareable link to join the team with a role, a maximum number of uses, an expiry and an optional email domain
+// @Tags Join Link
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body command.CreateJoinLink true "Join link"
+// @Success 201 {string} string "OK"
+// @Router /teams/{id}/join-links [post]
+func (ctrl *joinLinkController) CreateJoinLink(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Create join link")
+
+	var cmd command.CreateJoinLink
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.CreateJoinLink(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to create join link")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "message": "OK", "data": gin.H{"link_id": cmd.LinkID}})
+}
+
+// @Summary Get join links
+// @Schemes
+// @Description Get the join links of the team, including the revoked and expired ones
+// @Tags Join Link
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {array} dto.JoinLinkRetrievalSchema
+// @Router /teams/{id}/join-links [get]
+func (ctrl *joinLinkController) GetJoinLinks(ctx *gin.Context) {
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get join links")
+
+	links, err := view.JoinLinks(ctx.Request.Context(), uuid.FromStringOrNil(id))
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get join links")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"join_links": links}})
+}
+
+// @Summary Revoke join link
+// @Schemes
+// @Description Revoke a join link of the team, the members who already joined through it stay
+// @Tags Join Link
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param link_id path string true "Join link ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/join-links/{link_id} [delete]
+func (ctrl *joinLinkController) RevokeJoinLink(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	linkIDString := ctx.Param("link_id")
+	log.Debug().Caller().Str("id", id).Str("link_id", linkIDString).Msg("Revoke join link")
+
+	linkID, err := ulid.Parse(linkIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	cmd := command.RevokeJoinLink{
+		TeamID: uuid.FromStringOrNil(id),
+		LinkID: linkID,
+		User:   currentUser,
+	}
+
+	err = handlers.RevokeJoinLink(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to revoke join link")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Redeem join link
+// @Schemes
+// @Description Join the team of the link with the role of the link
+// @Tags Join Link
+// @Accept json
+// @Produce json
+// @Param token path string true "Join link token"
+// @Success 200 {string} string "OK"
+// @Router /join/{token} [post]
+func (ctrl *joinLinkController) RedeemJoinLink(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+	log.Debug().Caller().Msg("Redeem join link")
+
+	cmd := command.RedeemJoinLink{
+		Token: ctx.Param("token"),
+		User:  currentUser,
+	}
+
+	err := handlers.RedeemJoinLink(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to redeem join link")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK", "data": gin.H{"team_id": cmd.TeamID}})
+}
@@ -0,0 +1,254 @@
+package v1
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/middleware"
+	"authorization/service/handlers"
+	"authorization/view"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+type JoinRequestController interface {
+	Routes(*gin.RouterGroup)
+}
+
+type joinRequestController struct{}
+
+// NewJoinRequestController -> returns new join request controller
+func NewJoinRequestController() JoinRequestController {
+	return &joinRequestController{}
+}
+
+func (ctrl *joinRequestController) Routes(route *gin.RouterGroup) {
+	team := route.Group("/teams/:id")
+	team.POST("/join-requests", middleware.DeserializeUser(), ctrl.RequestToJoin)
+	team.GET("/join-requests", middleware.DeserializeUser(), ctrl.GetJoinRequests)
+	team.POST("/join-requests/:request_id/approve", middleware.DeserializeUser(), ctrl.ApproveJoinRequest)
+	team.POST("/join-requests/:request_id/deny", middleware.DeserializeUser(), ctrl.DenyJoinRequest)
+	team.DELETE("/join-requests/:request_id", middleware.DeserializeUser(), ctrl.CancelJoinRequest)
+}
+
+// @Summary Request to join team
+// @Schemes
+// @Description Ask to join a discoverable team with an optional message, the owners and admins of the team are notified
+// @Tags Join Request
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body command.RequestToJoin false "Message"
+// @Success 201 {string} string "OK"
+// @Router /teams/{id}/join-requests [post]
+func (ctrl *joinRequestController) RequestToJoin(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Request to join team")
+
+	var cmd command.RequestToJoin
+	// the body is optional
+	if err := ctx.ShouldBindJSON(&cmd); err != nil && !errors.Is(err, io.EOF) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.RequestToJoin(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to request to join team")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "message": "OK", "data": gin.H{"request_id": cmd.RequestID}})
+}
+
+// @Summary Get join requests
+// @Schemes
+// @Description Get the requests to join the team, the pending ones unless a status is given
+// @Tags Join Request
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param status query string false "Statuses of the requests, comma separated"
+// @Param order query string false "asc or desc"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the page"
+// @Success 200 {object} dto.CursorPagination
+// @Router /teams/{id}/join-requests [get]
+func (ctrl *joinRequestController) GetJoinRequests(ctx *gin.Context) {
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get join requests")
+
+	limit, cursor, err := pageQuery(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	opts := domain.JoinRequestOptions{
+		TeamID:     uuid.FromStringOrNil(id),
+		Descending: ctx.Query("order") == "desc",
+		Limit:      limit,
+		Cursor:     cursor,
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		for _, value := range strings.Split(status, ",") {
+			opts.Statuses = append(opts.Statuses, domain.JoinRequestStatus(strings.TrimSpace(value)))
+		}
+	}
+
+	requests, err := view.TeamJoinRequests(ctx.Request.Context(), opts)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get join requests")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": requests})
+}
+
+// @Summary Approve join request
+// @Schemes
+// @Description Approve a request to join the team, the user joins as member unless another role is given
+// @Tags Join Request
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request_id path string true "Join request ID"
+// @Param request body command.ApproveJoinRequest false "Role"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/join-requests/{request_id}/approve [post]
+func (ctrl *joinRequestController) ApproveJoinRequest(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID and join request ID from request parameter
+	id := ctx.Param("id")
+	requestIDString := ctx.Param("request_id")
+	log.Debug().Caller().Str("id", id).Str("request_id", requestIDString).Msg("Approve join request")
+
+	requestID, err := ulid.Parse(requestIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	var cmd command.ApproveJoinRequest
+	// the body is optional
+	if err := ctx.ShouldBindJSON(&cmd); err != nil && !errors.Is(err, io.EOF) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.RequestID = requestID
+	cmd.User = currentUser
+
+	err = handlers.ApproveJoinRequest(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to approve join request")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Deny join request
+// @Schemes
+// @Description Deny a request to join the team, the user is notified and can request again
+// @Tags Join Request
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request_id path string true "Join request ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/join-requests/{request_id}/deny [post]
+func (ctrl *joinRequestController) DenyJoinRequest(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID and join request ID from request parameter
+	id := ctx.Param("id")
+	requestIDString := ctx.Param("request_id")
+	log.Debug().Caller().Str("id", id).Str("request_id", requestIDString).Msg("Deny join request")
+
+	requestID, err := ulid.Parse(requestIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	cmd := command.DenyJoinRequest{
+		TeamID:    uuid.FromStringOrNil(id),
+		RequestID: requestID,
+		User:      currentUser,
+	}
+
+	err = handlers.DenyJoinRequest(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to deny join request")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Cancel join request
+// @Schemes
+// @Description Withdraw your pending request to join the team
+// @Tags Join Request
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request_id path string true "Join request ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/join-requests/{request_id} [delete]
+func (ctrl *joinRequestController) CancelJoinRequest(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID and join request ID from request parameter
+	id := ctx.Param("id")
+	requestIDString := ctx.Param("request_id")
+	log.Debug().Caller().Str("id", id).Str("request_id", requestIDString).Msg("Cancel join request")
+
+	requestID, err := ulid.Parse(requestIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	cmd := command.CancelJoinRequest{
+		TeamID:    uuid.FromStringOrNil(id),
+		RequestID: requestID,
+		User:      currentUser,
+	}
+
+	err = handlers.CancelJoinRequest(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to cancel join request")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
@@ -0,0 +1,331 @@
+package v1
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/middleware"
+	"authorization/service/handlers"
+	"authorization/view"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+// OrganizationController : represent the organization's controller contract
+type OrganizationController interface {
+	GetOrganizations(*gin.Context)
+	GetOrganizationById(*gin.Context)
+	CreateOrganization(*gin.Context)
+	UpdateOrganization(*gin.Context)
+	AddOrganizationMember(*gin.Context)
+	ChangeOrganizationMemberRole(*gin.Context)
+	DeleteOrganizationMember(*gin.Context)
+	AttachOrganizationTeam(*gin.Context)
+	DetachOrganizationTeam(*gin.Context)
+	Routes(*gin.RouterGroup)
+}
+
+type organizationController struct{}
+
+// NewOrganizationController -> returns new organization controller
+func NewOrganizationController() OrganizationController {
+	return &organizationController{}
+}
+
+func (ctrl *organizationController) Routes(route *gin.RouterGroup) {
+	organization := route.Group("/organizations")
+	organization.GET("", middleware.DeserializeUser(), ctrl.GetOrganizations)
+	organization.POST("", middleware.DeserializeUser(), ctrl.CreateOrganization)
+	organization.GET("/:id", middleware.DeserializeUser(), ctrl.GetOrganizationById)
+	organization.PUT("/:id", middleware.DeserializeUser(), ctrl.UpdateOrganization)
+	organization.POST("/:id/members", middleware.DeserializeUser(), ctrl.AddOrganizationMember)
+	organization.PUT("/:id/members/:membership_id", middleware.DeserializeUser(), ctrl.ChangeOrganizationMemberRole)
+	organization.DELETE("/:id/members/:membership_id", middleware.DeserializeUser(), ctrl.DeleteOrganizationMember)
+	organization.PUT("/:id/teams/:team_id", middleware.DeserializeUser(), ctrl.AttachOrganizationTeam)
+	organization.DELETE("/:id/teams/:team_id", middleware.DeserializeUser(), ctrl.DetachOrganizationTeam)
+}
+
+// @Summary Get all organizations
+// @Schemes
+// @Description Get the organizations of the current user
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.OrganizationRetrievalSchema
+// @Router /organizations [get]
+func (ctrl *organizationController) GetOrganizations(ctx *gin.Context) {
+	log.Debug().Caller().Msg("Get all organization data")
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	organizations, err := view.Organizations(ctx.Request.Context(), currentUser)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get all organization data")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": organizations})
+}
+
+// @Summary Get organization by ID
+// @Schemes
+// @Description Get organization data by ID with its members and teams
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 {object} dto.OrganizationRetrievalSchema
+// @Router /organizations/{id} [get]
+func (ctrl *organizationController) GetOrganizationById(ctx *gin.Context) {
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get organization data by ID")
+
+	organization, err := view.Organization(ctx.Request.Context(), uuid.FromStringOrNil(id))
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get organization data by ID")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"organization": organization}})
+}
+
+// @Summary Create organization
+// @Schemes
+// @Description Create organization, the current user becomes its owner
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param body body command.CreateOrganization true "Organization"
+// @Success 201 {string} string "OK"
+// @Router /organizations [post]
+func (ctrl *organizationController) CreateOrganization(ctx *gin.Context) {
+	log.Debug().Caller().Msg("Create organization data")
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	var cmd command.CreateOrganization
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.User = currentUser
+
+	err := handlers.CreateOrganization(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to create organization data")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "message": "OK", "data": gin.H{"organization_id": cmd.OrganizationID}})
+}
+
+// @Summary Update organization
+// @Schemes
+// @Description Update organization data
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param body body command.UpdateOrganization true "Organization"
+// @Success 200 {string} string "OK"
+// @Router /organizations/{id} [put]
+func (ctrl *organizationController) UpdateOrganization(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Update organization data")
+
+	var cmd command.UpdateOrganization
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.OrganizationID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.UpdateOrganization(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to update organization data")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Add organization member
+// @Schemes
+// @Description Add an existing user to the organization, organization owners and admins are admins of every team of the organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param body body command.AddOrganizationMember true "Member"
+// @Success 201 {string} string "OK"
+// @Router /organizations/{id}/members [post]
+func (ctrl *organizationController) AddOrganizationMember(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Add organization member")
+
+	var cmd command.AddOrganizationMember
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.OrganizationID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.AddOrganizationMember(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to add organization member")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Change organization member role
+// @Schemes
+// @Description Change the role of an organization member
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param membership_id path string true "Membership ID"
+// @Param body body command.ChangeOrganizationMemberRole true "Role"
+// @Success 200 {string} string "OK"
+// @Router /organizations/{id}/members/{membership_id} [put]
+func (ctrl *organizationController) ChangeOrganizationMemberRole(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	membershipID := ctx.Param("membership_id")
+	log.Debug().Caller().Str("id", id).Str("membership_id", membershipID).Msg("Change organization member role")
+
+	var cmd command.ChangeOrganizationMemberRole
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.OrganizationID = uuid.FromStringOrNil(id)
+	cmd.MembershipID = uuid.FromStringOrNil(membershipID)
+	cmd.User = currentUser
+
+	err := handlers.ChangeOrganizationMemberRole(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to change organization member role")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Delete organization member
+// @Schemes
+// @Description Remove a member from the organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param membership_id path string true "Membership ID"
+// @Success 200 {string} string "OK"
+// @Router /organizations/{id}/members/{membership_id} [delete]
+func (ctrl *organizationController) DeleteOrganizationMember(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	membershipID := ctx.Param("membership_id")
+	log.Debug().Caller().Str("id", id).Str("membership_id", membershipID).Msg("Delete organization member")
+
+	cmd := command.DeleteOrganizationMember{
+		OrganizationID: uuid.FromStringOrNil(id),
+		MembershipID:   uuid.FromStringOrNil(membershipID),
+		User:           currentUser,
+	}
+
+	err := handlers.DeleteOrganizationMember(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to delete organization member")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Attach team to organization
+// @Schemes
+// @Description Move a team owned by the current user under the organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param team_id path string true "Team ID"
+// @Success 200 {string} string "OK"
+// @Router /organizations/{id}/teams/{team_id} [put]
+func (ctrl *organizationController) AttachOrganizationTeam(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	teamID := ctx.Param("team_id")
+	log.Debug().Caller().Str("id", id).Str("team_id", teamID).Msg("Attach team to organization")
+
+	cmd := command.AttachOrganizationTeam{
+		OrganizationID: uuid.FromStringOrNil(id),
+		TeamID:         uuid.FromStringOrNil(teamID),
+		User:           currentUser,
+	}
+
+	err := handlers.AttachOrganizationTeam(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to attach team to organization")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Detach team from organization
+// @Schemes
+// @Description Remove a team from the organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param team_id path string true "Team ID"
+// @Success 200 {string} string "OK"
+// @Router /organizations/{id}/teams/{team_id} [delete]
+func (ctrl *organizationController) DetachOrganizationTeam(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	teamID := ctx.Param("team_id")
+	log.Debug().Caller().Str("id", id).Str("team_id", teamID).Msg("Detach team from organization")
+
+	cmd := command.DetachOrganizationTeam{
+		OrganizationID: uuid.FromStringOrNil(id),
+		TeamID:         uuid.FromStringOrNil(teamID),
+		User:           currentUser,
+	}
+
+	err := handlers.DetachOrganizationTeam(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to detach team from organization")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
@@ -0,0 +1,228 @@
+package v1
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/middleware"
+	"authorization/service/handlers"
+	"authorization/view"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+// OwnershipController : represent the ownership transfer's controller contract
+type OwnershipController interface {
+	ProposeOwnershipTransfer(*gin.Context)
+	CancelOwnershipTransfer(*gin.Context)
+	RelinquishOwnership(*gin.Context)
+	GetOwnershipTransferById(*gin.Context)
+	RespondOwnershipTransfer(*gin.Context)
+	Routes(*gin.RouterGroup)
+}
+
+type ownershipController struct{}
+
+// NewOwnershipController -> returns new ownership transfer controller
+func NewOwnershipController() OwnershipController {
+	return &ownershipController{}
+}
+
+func (ctrl *ownershipController) Routes(route *gin.RouterGroup) {
+	team := route.Group("/teams/:id")
+	team.POST("/ownership-transfers", middleware.DeserializeUser(), ctrl.ProposeOwnershipTransfer)
+	team.DELETE("/ownership-transfers/:transfer_id", middleware.DeserializeUser(), ctrl.CancelOwnershipTransfer)
+	team.DELETE("/ownership", middleware.DeserializeUser(), ctrl.RelinquishOwnership)
+
+	transfer := route.Group("/ownership-transfers")
+	transfer.GET("/:id", middleware.DeserializeUser(), ctrl.GetOwnershipTransferById)
+	transfer.PUT("/:id", middleware.DeserializeUser(), ctrl.RespondOwnershipTransfer)
+}
+
+// @Summary Propose ownership transfer
+// @Schemes
+// @Description Propose a member to become an owner of the team, the member confirms it from the emailed link
+// @Tags Ownership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body command.ProposeOwnershipTransfer true "Proposed member"
+// @Success 201 {string} string "OK"
+// @Router /teams/{id}/ownership-transfers [post]
+func (ctrl *ownershipController) ProposeOwnershipTransfer(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Propose ownership transfer")
+
+	var cmd command.ProposeOwnershipTransfer
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.ProposeOwnershipTransfer(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to propose ownership transfer")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "message": "OK", "data": gin.H{"transfer_id": cmd.TransferID}})
+}
+
+// @Summary Cancel ownership transfer
+// @Schemes
+// @Description Cancel the pending ownership transfer of the team
+// @Tags Ownership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param transfer_id path string true "Ownership transfer ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/ownership-transfers/{transfer_id} [delete]
+func (ctrl *ownershipController) CancelOwnershipTransfer(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	transferIDString := ctx.Param("transfer_id")
+	log.Debug().Caller().Str("id", id).Str("transfer_id", transferIDString).Msg("Cancel ownership transfer")
+
+	transferID, err := ulid.Parse(transferIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	cmd := command.CancelOwnershipTransfer{
+		TeamID:     uuid.FromStringOrNil(id),
+		TransferID: transferID,
+		User:       currentUser,
+	}
+
+	err = handlers.CancelOwnershipTransfer(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to cancel ownership transfer")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Relinquish ownership
+// @Schemes
+// @Description Step down from owner to admin, the team must keep another owner
+// @Tags Ownership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/ownership [delete]
+func (ctrl *ownershipController) RelinquishOwnership(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Relinquish ownership")
+
+	cmd := command.RelinquishOwnership{
+		TeamID: uuid.FromStringOrNil(id),
+		User:   currentUser,
+	}
+
+	err := handlers.RelinquishOwnership(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to relinquish ownership")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Get ownership transfer by ID
+// @Schemes
+// @Description Get the ownership transfer proposed by or addressed to the current user
+// @Tags Ownership
+// @Accept json
+// @Produce json
+// @Param id path string true "Ownership transfer ID"
+// @Success 200 {object} dto.OwnershipTransferRetrievalSchema
+// @Router /ownership-transfers/{id} [get]
+func (ctrl *ownershipController) GetOwnershipTransferById(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get ownership transfer by ID")
+
+	transferID, err := ulid.Parse(id)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	transfer, err := view.OwnershipTransfer(ctx.Request.Context(), transferID, currentUser)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get ownership transfer")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"ownership_transfer": transfer}})
+}
+
+// @Summary Respond ownership transfer
+// @Schemes
+// @Description Accept or decline the ownership transfer addressed to the current user
+// @Tags Ownership
+// @Accept json
+// @Produce json
+// @Param id path string true "Ownership transfer ID"
+// @Param request body command.RespondOwnershipTransfer true "Answer"
+// @Success 200 {string} string "OK"
+// @Router /ownership-transfers/{id} [put]
+func (ctrl *ownershipController) RespondOwnershipTransfer(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Respond ownership transfer")
+
+	var cmd command.RespondOwnershipTransfer
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transferID, err := ulid.Parse(id)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	cmd.TransferID = transferID
+	cmd.User = currentUser
+
+	err = handlers.RespondOwnershipTransfer(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to respond ownership transfer")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
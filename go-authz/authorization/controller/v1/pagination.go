@@ -0,0 +1,29 @@
+package v1
+
+import (
+	"authorization/config"
+	"authorization/domain"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pageQuery reads the limit and the cursor of a paginated list from the query string,
+// the limit falls back to the configured default and cannot exceed the configured maximum.
+func pageQuery(ctx *gin.Context) (int, *domain.Cursor, error) {
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = config.AppConfig.PaginationDefaultLimit
+	}
+
+	if limit > config.AppConfig.PaginationMaxLimit {
+		limit = config.AppConfig.PaginationMaxLimit
+	}
+
+	cursor, err := domain.DecodeCursor(ctx.Query("cursor"))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return limit, cursor, nil
+}
@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/middleware"
+	"authorization/service/handlers"
+	"authorization/view"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+// PolicyController : represent the policy's controller contract
+type PolicyController interface {
+	GetPolicies(*gin.Context)
+	GetPolicyById(*gin.Context)
+	CreatePolicy(*gin.Context)
+	UpdatePolicy(*gin.Context)
+	DeletePolicy(*gin.Context)
+	Routes(*gin.RouterGroup)
+}
+
+type policyController struct{}
+
+// NewPolicyController -> returns new policy controller
+func NewPolicyController() PolicyController {
+	return &policyController{}
+}
+
+func (ctrl *policyController) Routes(route *gin.RouterGroup) {
+	policy := route.Group("/teams/:id/policies")
+	policy.GET("", middleware.DeserializeUser(), ctrl.GetPolicies)
+	policy.GET("/:policy_id", middleware.DeserializeUser(), ctrl.GetPolicyById)
+	policy.POST("", middleware.DeserializeUser(), ctrl.CreatePolicy)
+	policy.PUT("/:policy_id", middleware.DeserializeUser(), ctrl.UpdatePolicy)
+	policy.DELETE("/:policy_id", middleware.DeserializeUser(), ctrl.DeletePolicy)
+}
+
+// @Summary Get team policies
+// @Schemes
+// @Description Get all conditional policies of a team
+// @Tags Policy
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {array} dto.PolicyRetrievalSchema
+// @Router /teams/{id}/policies [get]
+func (ctrl *policyController) GetPolicies(ctx *gin.Context) {
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get team policies")
+
+	policies, err := view.Policies(ctx.Request.Context(), uuid.FromStringOrNil(id))
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get team policies")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": policies})
+}
+
+// @Summary Get team policy by ID
+// @Schemes
+// @Description Get a conditional policy of a team
+// @Tags Policy
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param policy_id path string true "Policy ID"
+// @Success 200 {object} dto.PolicyRetrievalSchema
+// @Router /teams/{id}/policies/{policy_id} [get]
+func (ctrl *policyController) GetPolicyById(ctx *gin.Context) {
+	id := ctx.Param("id")
+	policyID := ctx.Param("policy_id")
+	log.Debug().Caller().Str("id", id).Str("policy_id", policyID).Msg("Get team policy by ID")
+
+	policy, err := view.Policy(ctx.Request.Context(), uuid.FromStringOrNil(id), uuid.FromStringOrNil(policyID))
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get team policy by ID")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": policy})
+}
+
+// @Summary Create team policy
+// @Schemes
+// @Description Create a conditional policy evaluated for the permission, the expression is validated on save.
+// @Description Expressions can use request (method, path, ip, time, permission), user, team and role attributes
+// @Description and the inCidr(ip, cidr) function, e.g. role.name != "finance" || inCidr(request.ip, "10.0.0.0/8")
+// @Tags Policy
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param body body command.CreatePolicy true "Policy"
+// @Success 201 {string} string "OK"
+// @Router /teams/{id}/policies [post]
+func (ctrl *policyController) CreatePolicy(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Create team policy")
+
+	var cmd command.CreatePolicy
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.CreatePolicy(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to create team policy")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "message": "OK", "data": gin.H{"policy_id": cmd.PolicyID}})
+}
+
+// @Summary Update team policy
+// @Schemes
+// @Description Update a conditional policy of a team, the expression is validated on save
+// @Tags Policy
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param policy_id path string true "Policy ID"
+// @Param body body command.UpdatePolicy true "Policy"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/policies/{policy_id} [put]
+func (ctrl *policyController) UpdatePolicy(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	policyID := ctx.Param("policy_id")
+	log.Debug().Caller().Str("id", id).Str("policy_id", policyID).Msg("Update team policy")
+
+	var cmd command.UpdatePolicy
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.PolicyID = uuid.FromStringOrNil(policyID)
+	cmd.User = currentUser
+
+	err := handlers.UpdatePolicy(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to update team policy")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Delete team policy
+// @Schemes
+// @Description Delete a conditional policy of a team
+// @Tags Policy
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param policy_id path string true "Policy ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/policies/{policy_id} [delete]
+func (ctrl *policyController) DeletePolicy(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	policyID := ctx.Param("policy_id")
+	log.Debug().Caller().Str("id", id).Str("policy_id", policyID).Msg("Delete team policy")
+
+	cmd := command.DeletePolicy{
+		TeamID:   uuid.FromStringOrNil(id),
+		PolicyID: uuid.FromStringOrNil(policyID),
+		User:     currentUser,
+	}
+
+	err := handlers.DeletePolicy(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to delete team policy")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/middleware"
+	"authorization/service/handlers"
+	"authorization/view"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// RelationController : represent the relation's controller contract
+type RelationController interface {
+	Check(*gin.Context)
+	Expand(*gin.Context)
+	ListObjects(*gin.Context)
+	WriteRelation(*gin.Context)
+	DeleteRelation(*gin.Context)
+	Routes(*gin.RouterGroup)
+}
+
+type relationController struct{}
+
+// NewRelationController -> returns new relation controller
+func NewRelationController() RelationController {
+	return &relationController{}
+}
+
+func (ctrl *relationController) Routes(route *gin.RouterGroup) {
+	relation := route.Group("/relations")
+	relation.GET("/check", middleware.DeserializeUser(), ctrl.Check)
+	relation.GET("/expand", middleware.DeserializeUser(), ctrl.Expand)
+	relation.GET("/objects", middleware.DeserializeUser(), ctrl.ListObjects)
+	relation.POST("", middleware.DeserializeUser(), ctrl.WriteRelation)
+	relation.DELETE("", middleware.DeserializeUser(), ctrl.DeleteRelation)
+}
+
+// @Summary Check relation
+// @Schemes
+// @Description Check whether a subject has a relation on an object
+// @Tags Relation
+// @Accept json
+// @Produce json
+// @Param object query string true "Object, e.g. application:42"
+// @Param relation query string true "Relation, e.g. viewer"
+// @Param subject query string false "Subject, defaults to the current user"
+// @Success 200 {object} dto.RelationCheckSchema
+// @Router /relations/check [get]
+func (ctrl *relationController) Check(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	object := ctx.Query("object")
+	relation := ctx.Query("relation")
+	subject := ctx.Query("subject")
+	log.Debug().Caller().Str("object", object).Str("relation", relation).Str("subject", subject).Msg("Check relation")
+
+	result, err := view.CheckRelation(ctx.Request.Context(), currentUser, object, relation, subject)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to check relation")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": result})
+}
+
+// @Summary Expand relation
+// @Schemes
+// @Description Expand the subjects that have a relation on an object
+// @Tags Relation
+// @Accept json
+// @Produce json
+// @Param object query string true "Object, e.g. application:42"
+// @Param relation query string true "Relation, e.g. viewer"
+// @Success 200 {object} dto.RelationTreeSchema
+// @Router /relations/expand [get]
+func (ctrl *relationController) Expand(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	object := ctx.Query("object")
+	relation := ctx.Query("relation")
+	log.Debug().Caller().Str("object", object).Str("relation", relation).Msg("Expand relation")
+
+	tree, err := view.ExpandRelation(ctx.Request.Context(), currentUser, object, relation)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to expand relation")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": tree})
+}
+
+// @Summary List objects
+// @Schemes
+// @Description List objects on which the current user has a relation
+// @Tags Relation
+// @Accept json
+// @Produce json
+// @Param type query string true "Object type, e.g. application"
+// @Param relation query string true "Relation, e.g. viewer"
+// @Success 200 {object} dto.RelationObjectsSchema
+// @Router /relations/objects [get]
+func (ctrl *relationController) ListObjects(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	objectType := ctx.Query("type")
+	relation := ctx.Query("relation")
+	log.Debug().Caller().Str("type", objectType).Str("relation", relation).Msg("List objects by relation")
+
+	objects, err := view.RelationObjects(ctx.Request.Context(), currentUser, objectType, relation)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to list objects by relation")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": objects})
+}
+
+// @Summary Write relation
+// @Schemes
+// @Description Write a relation tuple in the form object#relation@subject
+// @Tags Relation
+// @Accept json
+// @Produce json
+// @Param body body command.WriteRelation true "Relation tuple"
+// @Success 201 {string} string "OK"
+// @Router /relations [post]
+func (ctrl *relationController) WriteRelation(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	var cmd command.WriteRelation
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.User = currentUser
+
+	err := handlers.WriteRelation(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to write relation")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Delete relation
+// @Schemes
+// @Description Delete a relation tuple in the form object#relation@subject
+// @Tags Relation
+// @Accept json
+// @Produce json
+// @Param body body command.DeleteRelation true "Relation tuple"
+// @Success 200 {string} string "OK"
+// @Router /relations [delete]
+func (ctrl *relationController) DeleteRelation(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	var cmd command.DeleteRelation
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.User = currentUser
+
+	err := handlers.DeleteRelation(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to delete relation")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"authorization/config"
 	"authorization/controller/exception"
 	"authorization/domain"
 	"authorization/domain/command"
@@ -8,7 +9,7 @@ import (
 	"authorization/service/handlers"
 	"authorization/view"
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/rs/zerolog/log"
@@ -23,6 +24,8 @@ type TeamController interface {
 	GetTeams(*gin.Context)
 	CreateTeam(*gin.Context)
 	UpdateTeam(*gin.Context)
+	ArchiveTeam(*gin.Context)
+	RestoreTeam(*gin.Context)
 	Routes(*gin.RouterGroup)
 }
 
@@ -39,13 +42,29 @@ func (ctrl *teamController) Routes(route *gin.RouterGroup) {
 	team.GET("/:id", middleware.DeserializeUser(), ctrl.GetTeamById)
 	team.POST("", middleware.DeserializeUser(), ctrl.CreateTeam)
 	team.PUT("/:id", middleware.DeserializeUser(), ctrl.UpdateTeam)
+	team.DELETE("/:id", middleware.DeserializeUser(), ctrl.ArchiveTeam)
+	team.POST("/:id/restore", middleware.DeserializeUser(), ctrl.RestoreTeam)
 	team.PUT("/:id/last-active", middleware.DeserializeUser(), ctrl.UpdateLastActiveTeam)
+	team.GET("/:id/members", middleware.DeserializeUser(), ctrl.GetTeamMembers)
+	team.DELETE("/:id/members/me", middleware.DeserializeUser(), ctrl.LeaveTeam)
 	team.DELETE("/:id/members/:membership_id", middleware.DeserializeUser(), ctrl.DeleteTeamMember)
 	team.PUT("/:id/members/:membership_id", middleware.DeserializeUser(), ctrl.ChangeMemberRole)
+	team.PUT("/:id/members/:membership_id/expiry", middleware.DeserializeUser(), ctrl.SetMembershipExpiry)
 	team.POST("/:id/invitation", middleware.DeserializeUser(), ctrl.SendInvitation)
+	team.POST("/:id/invitation/import", middleware.DeserializeUser(), ctrl.ImportInvitations)
+	team.GET("/:id/invitation/import/:import_id", middleware.DeserializeUser(), ctrl.GetInvitationImport)
 	team.POST("/:id/invitation/:invitation_id", middleware.DeserializeUser(), ctrl.ResendInvitation)
+	team.GET("/:id/invitations", middleware.DeserializeUser(), ctrl.GetTeamInvitations)
+	team.POST("/:id/invitations/revoke", middleware.DeserializeUser(), ctrl.RevokeInvitations)
+	team.PUT("/:id/invitations/:invitation_id", middleware.DeserializeUser(), ctrl.ChangeInvitationRole)
+	team.GET("/:id/invitation-settings", middleware.DeserializeUser(), ctrl.GetInvitationSettings)
+	team.PUT("/:id/invitation-settings", middleware.DeserializeUser(), ctrl.UpdateInvitationSettings)
+	team.GET("/:id/usage", middleware.DeserializeUser(), ctrl.GetTeamUsage)
+	team.PUT("/:id/plan", middleware.DeserializeUser(), ctrl.ChangeTeamPlan)
 	team.PUT("/:id/avatar", middleware.DeserializeUser(), ctrl.UpdateTeamAvatar)
 	team.DELETE("/:id/avatar", middleware.DeserializeUser(), ctrl.DeleteTeamAvatar)
+	team.PUT("/:id/parent", middleware.DeserializeUser(), ctrl.SetTeamParent)
+	team.DELETE("/:id/parent", middleware.DeserializeUser(), ctrl.RemoveTeamParent)
 }
 
 // @Summary Get team by ID
@@ -82,47 +101,92 @@ func (ctrl *teamController) GetTeamById(ctx *gin.Context) {
 // @Tags Team
 // @Accept json
 // @Produce json
-// @Param page query int false "Page number"
-// @Param pageSize query int false "Page size"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the page"
 // @Param name query string false "Team name"
-// @Success 200 {object} dto.Pagination
+// @Param archived query bool false "List the archived teams instead"
+// @Success 200 {object} dto.CursorPagination
 // @Router /teams [get]
 func (ctrl *teamController) GetTeams(ctx *gin.Context) {
 	log.Debug().Caller().Msg("Get all team data")
 	currentUser := ctx.MustGet("currentUser").(domain.User)
 
-	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, cursor, err := pageQuery(ctx)
 	if err != nil {
-		log.Error().Caller().Err(err).Msg("failed to parse page query")
 		_ = ctx.Error(err)
+		return
 	}
 
-	pageSize, err := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	name := ctx.DefaultQuery("name", "")
+
+	listTeams := view.Teams
+	if ctx.DefaultQuery("archived", "false") == "true" {
+		listTeams = view.ArchivedTeams
+	}
+
+	// Get team data from database
+	teams, err := listTeams(ctx.Request.Context(), currentUser, name, limit, cursor)
 	if err != nil {
-		log.Error().Caller().Err(err).Msg("failed to parse page size query")
+		log.Error().Caller().Err(err).Msg("failed to get all team data")
 		_ = ctx.Error(err)
+		return
 	}
 
-	name := ctx.DefaultQuery("name", "")
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": teams})
+}
+
+// @Summary Get team members
+// @Schemes
+// @Description List the members of the team page by page, pass the next cursor of a page to get the following one
+// @Tags Membership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param q query string false "Search by name, email or username"
+// @Param role query string false "Role of the members"
+// @Param sort query string false "Sort by role, joined_at or last_active_at"
+// @Param order query string false "asc or desc"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the page"
+// @Success 200 {object} dto.CursorPagination
+// @Router /teams/{id}/members [get]
+func (ctrl *teamController) GetTeamMembers(ctx *gin.Context) {
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get team members")
 
-	if page < 1 {
-		page = 1
+	limit, cursor, err := pageQuery(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
 	}
 
-	if pageSize < 10 {
-		pageSize = 10
+	sortBy := domain.MembershipSort(ctx.DefaultQuery("sort", string(domain.SortByJoinedAt)))
+	if err := sortBy.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
 	}
 
-	// Get team data from database
-	teams, err := view.Teams(ctx.Request.Context(), currentUser, name, page, pageSize)
+	opts := domain.MembershipOptions{
+		TeamID:     uuid.FromStringOrNil(id),
+		Name:       ctx.Query("q"),
+		Role:       domain.RoleType(ctx.Query("role")),
+		SortBy:     sortBy,
+		Descending: ctx.Query("order") == "desc",
+		Limit:      limit,
+		Cursor:     cursor,
+	}
+
+	members, err := view.Members(ctx.Request.Context(), opts)
 	if err != nil {
-		log.Error().Caller().Err(err).Msg("failed to get all team data")
+		log.Error().Caller().Err(err).Msg("Failed to get team members")
 		_ = ctx.Error(err)
 		return
 	}
 
 	// Return success response
-	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": teams})
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": members})
 }
 
 // @Summary Create team
@@ -272,6 +336,38 @@ func (ctrl *teamController) DeleteTeamMember(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
 }
 
+// @Summary Leave team
+// @Schemes
+// @Description Remove the current user from the team, the last owner has to transfer the ownership first
+// @Tags Membership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/members/me [delete]
+func (ctrl *teamController) LeaveTeam(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Leave team")
+
+	cmd := command.LeaveTeam{
+		TeamID: uuid.FromStringOrNil(id),
+		User:   currentUser,
+	}
+
+	err := handlers.LeaveTeam(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to leave team")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
 // @Summary Change team member role
 // @Schemes
 // @Description Change team member role
@@ -315,6 +411,301 @@ func (ctrl *teamController) ChangeMemberRole(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
 }
 
+// @Summary Set team member expiry
+// @Schemes
+// @Description Make the membership time-bound, the member loses access to the team once it expires. A null expires_at makes the membership permanent again
+// @Tags Membership
+// @Accept json
+// @Produce json
+// @Param team_id path string true "Team ID"
+// @Param membership_id path string true "Membership ID"
+// @Param body body command.SetMembershipExpiry true "Expiry of the membership"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/members/{membership_id}/expiry [put]
+func (ctrl *teamController) SetMembershipExpiry(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	id := ctx.Param("id")
+	membershipID := ctx.Param("membership_id")
+	log.Debug().Caller().Str("id", id).Str("membership_id", membershipID).Msg("Set team member expiry")
+
+	var cmd command.SetMembershipExpiry
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.MembershipID = uuid.FromStringOrNil(membershipID)
+	cmd.User = currentUser
+
+	err := handlers.SetMembershipExpiry(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to set team member expiry")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Get team invitations
+// @Schemes
+// @Description List the invitations of the team page by page, the newest first, pass the next cursor of a page to get the following one
+// @Tags Membership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param status query string false "Statuses of the invitations, comma separated"
+// @Param email query string false "Search by email"
+// @Param order query string false "asc or desc"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the page"
+// @Success 200 {object} dto.CursorPagination
+// @Router /teams/{id}/invitations [get]
+func (ctrl *teamController) GetTeamInvitations(ctx *gin.Context) {
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get team invitations")
+
+	limit, cursor, err := pageQuery(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	opts := domain.InvitationOptions{
+		TeamID:     uuid.FromStringOrNil(id),
+		Query:      ctx.Query("email"),
+		Descending: ctx.Query("order") != "asc",
+		Limit:      limit,
+		Cursor:     cursor,
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		for _, value := range strings.Split(status, ",") {
+			opts.Statuses = append(opts.Statuses, domain.InvitationStatus(strings.TrimSpace(value)))
+		}
+	}
+
+	invitations, err := view.TeamInvitations(ctx.Request.Context(), opts)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get team invitations")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": invitations})
+}
+
+// @Summary Revoke invitations
+// @Schemes
+// @Description Revoke invitations of the team that are not answered yet, none is revoked when one of them can't be
+// @Tags Membership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body command.RevokeInvitations true "Invitation IDs"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/invitations/revoke [post]
+func (ctrl *teamController) RevokeInvitations(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Revoke team invitations")
+
+	var cmd command.RevokeInvitations
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.RevokeInvitations(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to revoke team invitations")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK", "data": gin.H{"revoked": cmd.Revoked}})
+}
+
+// @Summary Change invitation role
+// @Schemes
+// @Description Change the role granted by an invitation that is not answered yet
+// @Tags Membership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param invitation_id path string true "Invitation ID"
+// @Param request body command.ChangeInvitationRole true "Role"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/invitations/{invitation_id} [put]
+func (ctrl *teamController) ChangeInvitationRole(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID and invitation ID from request parameter
+	id := ctx.Param("id")
+	invitationIDString := ctx.Param("invitation_id")
+	log.Debug().Caller().Str("id", id).Str("invitation_id", invitationIDString).Msg("Change invitation role")
+
+	invitationID, err := ulid.Parse(invitationIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	var cmd command.ChangeInvitationRole
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.InvitationID = invitationID
+	cmd.User = currentUser
+
+	err = handlers.ChangeInvitationRole(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to change invitation role")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Get invitation settings
+// @Schemes
+// @Description Get how long the invitations of the team are valid and how long before their expiry the invitees are reminded of them
+// @Tags Membership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.InvitationSettingsRetrievalSchema
+// @Router /teams/{id}/invitation-settings [get]
+func (ctrl *teamController) GetInvitationSettings(ctx *gin.Context) {
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get invitation settings")
+
+	settings, err := view.InvitationSettings(ctx.Request.Context(), uuid.FromStringOrNil(id))
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get invitation settings")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"settings": settings}})
+}
+
+// @Summary Update invitation settings
+// @Schemes
+// @Description Set how long the invitations of the team are valid and how many hours before their expiry the invitees are reminded of them, the invitations already sent are left as they are
+// @Tags Membership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body command.UpdateInvitationSettings true "Invitation settings"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/invitation-settings [put]
+func (ctrl *teamController) UpdateInvitationSettings(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Update invitation settings")
+
+	var cmd command.UpdateInvitationSettings
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.UpdateInvitationSettings(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to update invitation settings")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Get team usage
+// @Schemes
+// @Description Get the plan of the team along with its members and pending invitations against the quotas of the plan, a limit of 0 is unlimited
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.TeamUsageSchema
+// @Router /teams/{id}/usage [get]
+func (ctrl *teamController) GetTeamUsage(ctx *gin.Context) {
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get team usage")
+
+	usage, err := view.TeamUsage(ctx.Request.Context(), uuid.FromStringOrNil(id))
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get team usage")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"usage": usage}})
+}
+
+// @Summary Change team plan
+// @Schemes
+// @Description Move the team to another plan, only the administrators can
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body command.ChangeTeamPlan true "Name of the plan"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/plan [put]
+func (ctrl *teamController) ChangeTeamPlan(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Change team plan")
+
+	var cmd command.ChangeTeamPlan
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.ChangeTeamPlan(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to change team plan")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
 // @Summary Send invitation
 // @Schemes
 // @Description Send invitation to join team
@@ -351,7 +742,104 @@ func (ctrl *teamController) SendInvitation(ctx *gin.Context) {
 	}
 
 	// Return success response
-	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK", "data": gin.H{"results": cmd.Results}})
+}
+
+// @Summary Import invitations
+// @Schemes
+// @Description Invite the rows of a CSV file (email and role columns) or of a JSON list, the report tells for each row whether it was invited, skipped or invalid. Large imports are processed in the background, their report is read from the import.
+// @Tags Membership
+// @Accept json,mpfd
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param file formData file false "CSV file"
+// @Param request body command.ImportInvitations false "Invitees"
+// @Success 200 {object} dto.InvitationImportRetrievalSchema
+// @Success 202 {string} string "Accepted"
+// @Router /teams/{id}/invitation/import [post]
+func (ctrl *teamController) ImportInvitations(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Import invitations to join team")
+
+	var cmd command.ImportInvitations
+	if file, err := ctx.FormFile("file"); err == nil {
+		content, err := file.Open()
+		if err != nil {
+			_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+			return
+		}
+		defer content.Close()
+
+		cmd.Invitees, err = domain.ParseInvitationCSV(content, config.AppConfig.InvitationImportMaxRows)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	} else if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.Sender = currentUser
+
+	err := handlers.ImportInvitations(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to import invitations to join team")
+		_ = ctx.Error(err)
+		return
+	}
+
+	if cmd.Async {
+		ctx.JSON(http.StatusAccepted, gin.H{"status": "success", "message": "Accepted", "data": gin.H{"import_id": cmd.ImportID}})
+		return
+	}
+
+	report, err := view.InvitationImport(ctx.Request.Context(), cmd.TeamID, cmd.ImportID)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get invitation import")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"import": report}})
+}
+
+// @Summary Get invitation import
+// @Schemes
+// @Description Get the report of a bulk invitation of the team, its results are empty while it is pending
+// @Tags Membership
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param import_id path string true "Invitation import ID"
+// @Success 200 {object} dto.InvitationImportRetrievalSchema
+// @Router /teams/{id}/invitation/import/{import_id} [get]
+func (ctrl *teamController) GetInvitationImport(ctx *gin.Context) {
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	importIDString := ctx.Param("import_id")
+	log.Debug().Caller().Str("id", id).Str("import_id", importIDString).Msg("Get invitation import")
+
+	importID, err := ulid.Parse(importIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	report, err := view.InvitationImport(ctx.Request.Context(), uuid.FromStringOrNil(id), importID)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get invitation import")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"import": report}})
 }
 
 // @Summary Resend Invitation
@@ -470,3 +958,136 @@ func (ctrl *teamController) DeleteTeamAvatar(ctx *gin.Context) {
 	// Return success response
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
 }
+
+// @Summary Set parent team
+// @Schemes
+// @Description Nest the team under a parent team, the inheritance decides which parent members are members of the team
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body command.SetTeamParent true "Parent team"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/parent [put]
+func (ctrl *teamController) SetTeamParent(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Set parent team")
+
+	var cmd command.SetTeamParent
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.SetTeamParent(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to set parent team")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Remove parent team
+// @Schemes
+// @Description Detach the team from its parent team
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/parent [delete]
+func (ctrl *teamController) RemoveTeamParent(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Remove parent team")
+
+	cmd := command.RemoveTeamParent{
+		TeamID: uuid.FromStringOrNil(id),
+		User:   currentUser,
+	}
+
+	err := handlers.RemoveTeamParent(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to remove parent team")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Archive team
+// @Schemes
+// @Description Archive the team, it becomes read-only and is deleted once the grace period is over
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id} [delete]
+func (ctrl *teamController) ArchiveTeam(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Archive team")
+
+	cmd := command.ArchiveTeam{
+		TeamID: uuid.FromStringOrNil(id),
+		User:   currentUser,
+	}
+
+	err := handlers.ArchiveTeam(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to archive team")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Restore team
+// @Schemes
+// @Description Restore an archived team during its grace period
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/restore [post]
+func (ctrl *teamController) RestoreTeam(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Restore team")
+
+	cmd := command.RestoreTeam{
+		TeamID: uuid.FromStringOrNil(id),
+		User:   currentUser,
+	}
+
+	err := handlers.RestoreTeam(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to restore team")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/middleware"
+	"authorization/service/handlers"
+	"authorization/view"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+// TeamDomainController : represent the team domain's controller contract
+type TeamDomainController interface {
+	ClaimTeamDomain(*gin.Context)
+	GetTeamDomains(*gin.Context)
+	VerifyTeamDomain(*gin.Context)
+	RemoveTeamDomain(*gin.Context)
+	Routes(*gin.RouterGroup)
+}
+
+type teamDomainController struct{}
+
+// NewTeamDomainController -> returns new team domain controller
+func NewTeamDomainController() TeamDomainController {
+	return &teamDomainController{}
+}
+
+func (ctrl *teamDomainController) Routes(route *gin.RouterGroup) {
+	team := route.Group("/teams/:id")
+	team.POST("/domains", middleware.DeserializeUser(), ctrl.ClaimTeamDomain)
+	team.GET("/domains", middleware.DeserializeUser(), ctrl.GetTeamDomains)
+	team.POST("/domains/:domain_id/verify", middleware.DeserializeUser(), ctrl.VerifyTeamDomain)
+	team.DELETE("/domains/:domain_id", middleware.DeserializeUser(), ctrl.RemoveTeamDomain)
+}
+
+// @Summary Claim team domain
+// @Schemes
+// @Description Claim an email domain for the team, the users of the verified domain join the team with the role or are invited to it
+// @Tags Team Domain
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param request body command.ClaimTeamDomain true "Domain"
+// @Success 201 {string} string "OK"
+// @Router /teams/{id}/domains [post]
+func (ctrl *teamDomainController) ClaimTeamDomain(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Claim team domain")
+
+	var cmd command.ClaimTeamDomain
+	if err := ctx.ShouldBindJSON(&cmd); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd.TeamID = uuid.FromStringOrNil(id)
+	cmd.User = currentUser
+
+	err := handlers.ClaimTeamDomain(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to claim team domain")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "message": "OK", "data": gin.H{"domain_id": cmd.DomainID}})
+}
+
+// @Summary Get team domains
+// @Schemes
+// @Description Get the email domains claimed by the team and the TXT records verifying them
+// @Tags Team Domain
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {array} dto.TeamDomainRetrievalSchema
+// @Router /teams/{id}/domains [get]
+func (ctrl *teamDomainController) GetTeamDomains(ctx *gin.Context) {
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	log.Debug().Caller().Str("id", id).Msg("Get team domains")
+
+	teamDomains, err := view.TeamDomains(ctx.Request.Context(), uuid.FromStringOrNil(id))
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get team domains")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"domains": teamDomains}})
+}
+
+// @Summary Verify team domain
+// @Schemes
+// @Description Look up the TXT record of the domain, the users of the verified domain join the team or are invited to it
+// @Tags Team Domain
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param domain_id path string true "Team domain ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/domains/{domain_id}/verify [post]
+func (ctrl *teamDomainController) VerifyTeamDomain(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	domainIDString := ctx.Param("domain_id")
+	log.Debug().Caller().Str("id", id).Str("domain_id", domainIDString).Msg("Verify team domain")
+
+	domainID, err := ulid.Parse(domainIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	cmd := command.VerifyTeamDomain{
+		TeamID:   uuid.FromStringOrNil(id),
+		DomainID: domainID,
+		User:     currentUser,
+	}
+
+	err = handlers.VerifyTeamDomain(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to verify team domain")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
+
+// @Summary Remove team domain
+// @Schemes
+// @Description Drop the claim of the team on the domain, the members who joined through it stay
+// @Tags Team Domain
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param domain_id path string true "Team domain ID"
+// @Success 200 {string} string "OK"
+// @Router /teams/{id}/domains/{domain_id} [delete]
+func (ctrl *teamDomainController) RemoveTeamDomain(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	// Get team ID from request parameter
+	id := ctx.Param("id")
+	domainIDString := ctx.Param("domain_id")
+	log.Debug().Caller().Str("id", id).Str("domain_id", domainIDString).Msg("Remove team domain")
+
+	domainID, err := ulid.Parse(domainIDString)
+	if err != nil {
+		_ = ctx.Error(exception.NewBadRequestException(err.Error()))
+		return
+	}
+
+	cmd := command.RemoveTeamDomain{
+		TeamID:   uuid.FromStringOrNil(id),
+		DomainID: domainID,
+		User:     currentUser,
+	}
+
+	err = handlers.RemoveTeamDomain(ctx.Request.Context(), &cmd)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to remove team domain")
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Return success response
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "OK"})
+}
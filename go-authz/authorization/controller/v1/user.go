@@ -11,7 +11,6 @@ import (
 	"authorization/view"
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/rs/zerolog/log"
 
@@ -37,6 +36,7 @@ func NewUserController() UserController {
 func (ctrl *userController) Routes(route *gin.RouterGroup) {
 	user := route.Group("/users")
 	user.GET("/me", middleware.DeserializeUser(), ctrl.GetMe)
+	user.GET("/me/invitations", middleware.DeserializeUser(), ctrl.GetMyInvitations)
 	user.GET("/:id", ctrl.GetUserById)
 	user.GET("", ctrl.GetUsers)
 	user.PUT("", middleware.DeserializeUser(), ctrl.UpdateUser)
@@ -58,6 +58,35 @@ func (ctrl *userController) GetMe(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"user": currentUser.ProfileUser()}})
 }
 
+// @Summary Get my invitations
+// @Schemes
+// @Description List the invitations of the current user waiting for an answer, the newest first
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the page"
+// @Success 200 {object} dto.CursorPagination
+// @Router /users/me/invitations [get]
+func (ctrl *userController) GetMyInvitations(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(domain.User)
+
+	limit, cursor, err := pageQuery(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	invitations, err := view.UserInvitations(ctx.Request.Context(), currentUser, limit, cursor)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to get invitations of the current user")
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": invitations})
+}
+
 // @Summary Get user by ID
 // @Schemes
 // @Description Get user data by ID
@@ -102,33 +131,19 @@ func (ctrl *userController) GetUserById(ctx *gin.Context) {
 // @Tags User
 // @Accept json
 // @Produce json
-// @Param page query int false "Page number"
-// @Param pageSize query int false "Page size"
-// @Success 200 {object} dto.PublicUser
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the page"
+// @Success 200 {object} dto.CursorPagination
 // @Router /users [get]
 func (ctrl *userController) GetUsers(ctx *gin.Context) {
-	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
-	if err != nil {
-		log.Error().Caller().Err(err).Msg("Failed to get page number")
-		_ = ctx.Error(err)
-	}
-
-	pageSize, err := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	limit, cursor, err := pageQuery(ctx)
 	if err != nil {
-		log.Error().Caller().Err(err).Msg("Failed to get page size")
 		_ = ctx.Error(err)
-	}
-
-	if page < 1 {
-		page = 1
-	}
-
-	if pageSize < 10 {
-		pageSize = 10
+		return
 	}
 
 	// Get user data from database
-	users, err := view.Users(ctx.Request.Context(), page, pageSize)
+	users, err := view.Users(ctx.Request.Context(), domain.UserOptions{Limit: limit, Cursor: cursor})
 	if err != nil {
 		log.Error().Caller().Err(err).Msg("Failed to get users data")
 		_ = ctx.Error(err)
@@ -1,12 +1,12 @@
 package domain
 
 import (
+	"authorization/domain/dto"
 	"authorization/util"
-	"encoding/json"
 	"time"
 
 	"github.com/oklog/ulid/v2"
-	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
 )
 
 type RoleType string
@@ -16,87 +16,185 @@ var (
 	Admin   RoleType = "admin"
 	Member  RoleType = "member"
 	Finance RoleType = "finance"
+	Guest   RoleType = "guest"
+)
+
+// Reasons recorded in the authorization decision trace
+const (
+	DecisionEndpointNotMatched = "endpoint_not_matched"
+	DecisionEndpointNotScoped  = "endpoint_not_scoped"
+	DecisionRelationGranted    = "relation_granted"
+	DecisionRelationMissing    = "relation_missing"
+	DecisionNoMembership       = "no_membership"
+	DecisionPermissionMissing  = "permission_missing"
+	DecisionPolicyDenied       = "policy_denied"
+	DecisionPermissionGranted  = "permission_granted"
+	DecisionTeamArchived       = "team_archived"
+	DecisionGuestRestricted    = "guest_restricted"
 )
 
 type EndpointYAML struct {
 	Endpoints []struct {
-		Name   string `yaml:"name"`
-		Path   string `yaml:"path"`
-		Method string `yaml:"method"`
+		Name       string `yaml:"name"`
+		Path       string `yaml:"path"`
+		Method     string `yaml:"method"`
+		Permission string `yaml:"permission"`
+		Object     string `yaml:"object"`
+		Relation   string `yaml:"relation"`
+		// AllowArchived keeps a write endpoint usable while the team is archived, e.g. to restore it
+		AllowArchived bool `yaml:"allow_archived"`
+		// AllowGuests opens the endpoint to the roles of the guest class, they can use no other endpoint
+		AllowGuests bool `yaml:"allow_guests"`
 	} `yaml:"endpoints"`
 }
 
+type PermissionYAML struct {
+	Permissions []struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+	} `yaml:"permissions"`
+}
+
 type RoleYAML struct {
-	Name      RoleType `yaml:"name"`
-	Endpoints []struct {
+	Name        RoleType `yaml:"name"`
+	Permissions []struct {
 		Name string `yaml:"name"`
-	} `yaml:"endpoints"`
+	} `yaml:"permissions"`
 }
 
+// Endpoint maps a gateway route to the permission required to access it.
+// Endpoints are loaded from endpoints.yml and are never persisted, so a path
+// can be changed without touching the roles stored in the database.
+// An endpoint with a Relation is authorized by checking the relation on the
+// Object identified in the path instead of the team permission.
+// Archived teams are read-only, only the GET endpoints and the ones with AllowArchived can be used on them.
+// The guests of a team can only use the endpoints with AllowGuests, whatever the permissions of their role.
 type Endpoint struct {
-	Name   string
-	Path   string
-	Method string
+	Name          string
+	Path          string
+	Method        string
+	Permission    string
+	Object        string
+	Relation      string
+	AllowArchived bool
+	AllowGuests   bool
 }
 
-func (e Endpoint) Equals(endpoint Endpoint) bool {
-	return e.Name == endpoint.Name && e.Path == endpoint.Path && e.Method == endpoint.Method
+// Key returns the lookup key used by the ext-authz server to match a request.
+func (e Endpoint) Key() string {
+	return EndpointKey(e.Path, e.Method)
 }
 
-type Endpoints []Endpoint
-
-type Role struct {
-	ID        ulid.ULID
-	Name      RoleType
-	Endpoints Endpoints
-	CreatedAt time.Time
-	UpdatedAt time.Time
+func EndpointKey(path, method string) string {
+	return path + "_" + method
 }
 
-func (endpoints Endpoints) Find(name string) Endpoint {
-	for _, e := range endpoints {
-		if e.Name == name {
-			return e
-		}
+func (e Endpoint) Parse() dto.EndpointSchema {
+	return dto.EndpointSchema{
+		Name:          e.Name,
+		Path:          e.Path,
+		Method:        e.Method,
+		Permission:    e.Permission,
+		Object:        e.Object,
+		Relation:      e.Relation,
+		AllowArchived: e.AllowArchived,
+		AllowGuests:   e.AllowGuests,
 	}
-	return Endpoint{}
 }
 
-func (endpoints Endpoints) Contains(endpoint Endpoint) bool {
-	for _, e := range endpoints {
-		if e.Equals(endpoint) {
+// IsReadOnly tells whether the endpoint can be used on an archived team.
+func (e Endpoint) IsReadOnly() bool {
+	return e.Method == "GET" || e.AllowArchived
+}
+
+type Permission struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Permissions []Permission
+
+func (permissions Permissions) Contains(name string) bool {
+	for _, p := range permissions {
+		if p.Name == name {
 			return true
 		}
 	}
 	return false
 }
 
-func (endpoints *Endpoints) Remove(endpoint Endpoint) {
-	endpointsValue := *endpoints
-	for i, e := range endpointsValue {
-		if e.Name == endpoint.Name {
-			*endpoints = append(endpointsValue[:i], endpointsValue[i+1:]...)
+func (permissions Permissions) Names() []string {
+	names := make([]string, len(permissions))
+	for i, p := range permissions {
+		names[i] = p.Name
+	}
+	return names
+}
+
+func (permissions *Permissions) Add(permission Permission) {
+	if permissions.Contains(permission.Name) {
+		return
+	}
+	*permissions = append(*permissions, permission)
+}
+
+func (permissions *Permissions) Remove(name string) {
+	permissionsValue := *permissions
+	for i, p := range permissionsValue {
+		if p.Name == name {
+			*permissions = append(permissionsValue[:i], permissionsValue[i+1:]...)
+			return
 		}
 	}
 }
 
-func (endpoints *Endpoints) Add(endpoint Endpoint) {
-	*endpoints = append(*endpoints, endpoint)
+type Role struct {
+	ID          ulid.ULID
+	Name        RoleType
+	Permissions Permissions
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
-func (endpoints Endpoints) ToJSON() ([]byte, error) {
-	json, err := json.Marshal(endpoints)
-	if err != nil {
-		log.Error().Err(err).Msg("error marshaling endpoints")
-		return nil, err
+// RolePrecedence orders the roles from the most to the least privileged,
+// it decides the effective role when a user holds several roles for the same team.
+var RolePrecedence = RoleTypes{Owner, Admin, Member, Finance, Guest}
+
+type RoleTypes []RoleType
+
+// Rank returns the 1-based position of the role, as array_position does in the queries.
+func (roles RoleTypes) Rank(role RoleType) int {
+	for i, r := range roles {
+		if r == role {
+			return i + 1
+		}
 	}
-	return json, nil
+	return 0
 }
 
+func (roles RoleTypes) Names() []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return names
+}
+
+// Where the effective role of an Access comes from
+const (
+	AccessSourceTeam         = "team"
+	AccessSourceOrganization = "organization"
+	AccessSourceParentTeam   = "parent_team"
+)
+
 type Access struct {
-	RoleName  RoleType
-	IsAllowed bool
-	Endpoint  Endpoint
+	RoleName   RoleType
+	IsAllowed  bool
+	Permission string
+	Source     string
 }
 
 func NewRole(name RoleType) Role {
@@ -104,6 +202,15 @@ func NewRole(name RoleType) Role {
 	return Role{ID: ulid.Make(), Name: name, CreatedAt: now, UpdatedAt: now}
 }
 
-func NewEndpoint(name, path, method string) Endpoint {
-	return Endpoint{Name: name, Path: path, Method: method}
+func NewPermission(name, description string) Permission {
+	now := util.GetTimestampUTC()
+	return Permission{ID: uuid.NewV4(), Name: name, Description: description, CreatedAt: now, UpdatedAt: now}
+}
+
+func NewEndpoint(name, path, method, permission string) Endpoint {
+	return Endpoint{Name: name, Path: path, Method: method, Permission: permission}
+}
+
+func NewRelationEndpoint(name, path, method, object, relation string) Endpoint {
+	return Endpoint{Name: name, Path: path, Method: method, Object: object, Relation: relation}
 }
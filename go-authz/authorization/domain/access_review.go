@@ -0,0 +1,307 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type AccessReviewStatus string
+
+const (
+	AccessReviewOpen      AccessReviewStatus = "open"
+	AccessReviewCompleted AccessReviewStatus = "completed"
+)
+
+type AccessReviewDecision string
+
+const (
+	AccessReviewPending     AccessReviewDecision = "pending"
+	AccessReviewConfirmed   AccessReviewDecision = "confirmed"
+	AccessReviewRevoked     AccessReviewDecision = "revoked"
+	AccessReviewAutoRevoked AccessReviewDecision = "auto_revoked"
+	AccessReviewUnreviewed  AccessReviewDecision = "unreviewed"
+)
+
+const (
+	// MaxAccessReviewTeams bounds the teams a single review covers.
+	MaxAccessReviewTeams = 50
+
+	MaxAccessReviewNameLength = 100
+	MaxAccessReviewNoteLength = 500
+)
+
+// AccessReview is a campaign asking the reviewers of one or more teams to confirm or revoke each membership
+// before the deadline. The memberships are taken as items when the review starts, the ones left unreviewed
+// at the deadline are revoked when AutoRevoke is set.
+type AccessReview struct {
+	ID            ulid.ULID
+	Name          string
+	CreatorID     uuid.NullUUID
+	Creator       User
+	Roles         RoleTypes
+	Deadline      time.Time
+	AutoRevoke    bool
+	Status        AccessReviewStatus
+	RemindersSent int
+	TotalItems    int
+	PendingItems  int
+	Items         []AccessReviewItem
+	CompletedAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// AccessReviewItem is the membership of a user in a team as it was when the review started, along with its decision.
+type AccessReviewItem struct {
+	ID           ulid.ULID
+	ReviewID     ulid.ULID
+	TeamID       uuid.UUID
+	Team         Team
+	MembershipID uuid.UUID
+	UserID       uuid.UUID
+	User         User
+	RoleID       ulid.ULID
+	Role         Role
+	Decision     AccessReviewDecision
+	ReviewerID   uuid.NullUUID
+	Reviewer     User
+	Note         string
+	ReviewedAt   *time.Time
+	CreatedAt    time.Time
+}
+
+// NewAccessReview checks the review started by the creator, roles limits the memberships taken to the ones
+// holding one of them and every role is taken when it is empty.
+func NewAccessReview(name string, creatorID uuid.UUID, roles RoleTypes, deadline time.Time, autoRevoke bool) (AccessReview, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return AccessReview{}, exception.NewBadRequestException("review name is required")
+	} else if len(name) > MaxAccessReviewNameLength {
+		return AccessReview{}, exception.NewBadRequestException(fmt.Sprintf("review name must be at most %d characters", MaxAccessReviewNameLength))
+	}
+
+	for _, role := range roles {
+		if RolePrecedence.Rank(role) == 0 {
+			return AccessReview{}, exception.NewBadRequestException(fmt.Sprintf("role %s is not a team role", role))
+		}
+	}
+
+	now := util.GetTimestampUTC()
+	if !deadline.After(now) {
+		return AccessReview{}, exception.NewBadRequestException("review deadline must be in the future")
+	}
+
+	return AccessReview{
+		ID:         ulid.Make(),
+		Name:       name,
+		CreatorID:  uuid.NullUUID{UUID: creatorID, Valid: true},
+		Roles:      roles,
+		Deadline:   deadline.UTC(),
+		AutoRevoke: autoRevoke,
+		Status:     AccessReviewOpen,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// ReviewedRoles are the roles of the memberships the review takes.
+func (r AccessReview) ReviewedRoles() RoleTypes {
+	if len(r.Roles) == 0 {
+		return RolePrecedence
+	}
+	return r.Roles
+}
+
+// AddItem takes the membership for review.
+func (r *AccessReview) AddItem(membership Membership) {
+	r.Items = append(r.Items, AccessReviewItem{
+		ID:           ulid.Make(),
+		ReviewID:     r.ID,
+		TeamID:       membership.TeamID,
+		Team:         membership.Team,
+		MembershipID: membership.ID,
+		UserID:       membership.UserID,
+		User:         membership.User,
+		RoleID:       membership.RoleID,
+		Role:         membership.Role,
+		Decision:     AccessReviewPending,
+		CreatedAt:    r.CreatedAt,
+	})
+	r.TotalItems++
+	r.PendingItems++
+}
+
+func (r AccessReview) IsOpen() bool {
+	return r.Status == AccessReviewOpen
+}
+
+// IsOverdue tells whether the deadline of the open review has passed.
+func (r AccessReview) IsOverdue(now time.Time) bool {
+	return r.IsOpen() && !now.Before(r.Deadline)
+}
+
+// Complete closes the review, the items still pending are left unreviewed by the caller.
+func (r *AccessReview) Complete() {
+	now := util.GetTimestampUTC()
+	r.Status = AccessReviewCompleted
+	r.CompletedAt = &now
+	r.UpdatedAt = now
+}
+
+// DueReminders is the number of reminders due by now, offsets are the durations before the deadline
+// the reminders are sent at. The reminders missed while the sweep was not running are sent as one.
+func (r AccessReview) DueReminders(now time.Time, offsets []time.Duration) int {
+	due := 0
+	for _, offset := range offsets {
+		if !now.Before(r.Deadline.Add(-offset)) {
+			due++
+		}
+	}
+	return due
+}
+
+// Review records the decision of the reviewer on the pending item.
+func (i *AccessReviewItem) Review(decision AccessReviewDecision, reviewerID uuid.UUID, note string) error {
+	if decision != AccessReviewConfirmed && decision != AccessReviewRevoked {
+		return exception.NewBadRequestException(fmt.Sprintf("decision must be %s or %s", AccessReviewConfirmed, AccessReviewRevoked))
+	}
+
+	note = strings.TrimSpace(note)
+	if len(note) > MaxAccessReviewNoteLength {
+		return exception.NewBadRequestException(fmt.Sprintf("note must be at most %d characters", MaxAccessReviewNoteLength))
+	}
+
+	if i.Decision != AccessReviewPending {
+		return exception.NewBadRequestException(fmt.Sprintf("access of %s to the team is already %s", i.User.Email, i.Decision))
+	}
+
+	now := util.GetTimestampUTC()
+	i.Decision = decision
+	i.ReviewerID = uuid.NullUUID{UUID: reviewerID, Valid: true}
+	i.Note = note
+	i.ReviewedAt = &now
+	return nil
+}
+
+// Close records the decision taken on the item left pending at the deadline.
+func (i *AccessReviewItem) Close(decision AccessReviewDecision, note string) {
+	now := util.GetTimestampUTC()
+	i.Decision = decision
+	i.Note = note
+	i.ReviewedAt = &now
+}
+
+// AccessReviewOptions filters the access reviews, the unset fields are ignored.
+// The reviews are ordered by creation, a Cursor taken from the last review of a page lists the next one.
+type AccessReviewOptions struct {
+	TeamID     uuid.UUID
+	Statuses   []AccessReviewStatus
+	Limit      int
+	Descending bool
+	Cursor     *Cursor
+}
+
+func (r AccessReview) Cursor() Cursor {
+	return NewTimeCursor(r.CreatedAt, r.ID.String())
+}
+
+func (r AccessReview) Parse() dto.AccessReviewSchema {
+	roles := make([]string, 0, len(r.Roles))
+	for _, role := range r.Roles {
+		roles = append(roles, string(role))
+	}
+
+	items := make([]dto.AccessReviewItemSchema, 0, len(r.Items))
+	for _, item := range r.Items {
+		items = append(items, item.Parse())
+	}
+
+	schema := dto.AccessReviewSchema{
+		ID:           r.ID,
+		Name:         r.Name,
+		Roles:        roles,
+		Deadline:     r.Deadline,
+		AutoRevoke:   r.AutoRevoke,
+		Status:       string(r.Status),
+		TotalItems:   r.TotalItems,
+		PendingItems: r.PendingItems,
+		Items:        items,
+		CompletedAt:  r.CompletedAt,
+		CreatedAt:    r.CreatedAt,
+	}
+	if r.CreatorID.Valid {
+		schema.Creator = r.Creator.PublicUser()
+	}
+	return schema
+}
+
+func (i AccessReviewItem) Parse() dto.AccessReviewItemSchema {
+	schema := dto.AccessReviewItemSchema{
+		ID:           i.ID,
+		TeamID:       i.TeamID,
+		TeamName:     i.Team.Name,
+		MembershipID: i.MembershipID,
+		User:         i.User.PublicUser(),
+		Role:         string(i.Role.Name),
+		Decision:     string(i.Decision),
+		Note:         i.Note,
+		ReviewedAt:   i.ReviewedAt,
+	}
+	if i.ReviewerID.Valid {
+		schema.Reviewer = i.Reviewer.PublicUser()
+	}
+	return schema
+}
+
+// CSV renders the items of the review, one row per membership ordered by team and user.
+func (r AccessReview) CSV() ([]byte, error) {
+	items := append([]AccessReviewItem(nil), r.Items...)
+	sort.SliceStable(items, func(a, b int) bool {
+		if items[a].Team.Name != items[b].Team.Name {
+			return items[a].Team.Name < items[b].Team.Name
+		}
+		return items[a].User.Email < items[b].User.Email
+	})
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	err := writer.Write([]string{"team_id", "team_name", "user_email", "user_name", "role", "decision", "reviewer_email", "note", "reviewed_at"})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		var reviewedAt string
+		if item.ReviewedAt != nil {
+			reviewedAt = item.ReviewedAt.Format(time.RFC3339)
+		}
+
+		err := writer.Write([]string{
+			item.TeamID.String(),
+			item.Team.Name,
+			item.User.Email,
+			item.User.FullName(),
+			string(item.Role.Name),
+			string(item.Decision),
+			item.Reviewer.Email,
+			item.Note,
+			reviewedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
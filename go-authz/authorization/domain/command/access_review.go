@@ -0,0 +1,35 @@
+package command
+
+import (
+	"authorization/domain"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type StartAccessReview struct {
+	ReviewID   ulid.ULID         `json:"-"`
+	Name       string            `json:"name" binding:"required"`
+	TeamIDs    []uuid.UUID       `json:"team_ids" binding:"required"`
+	Roles      []domain.RoleType `json:"roles"`
+	Deadline   time.Time         `json:"deadline" binding:"required"`
+	AutoRevoke bool              `json:"auto_revoke"`
+	User       domain.User
+	Command
+}
+
+type ReviewAccess struct {
+	ReviewID ulid.ULID                   `json:"-"`
+	ItemID   ulid.ULID                   `json:"-"`
+	Decision domain.AccessReviewDecision `json:"decision" binding:"required"`
+	Note     string                      `json:"note"`
+	User     domain.User
+	Command
+}
+
+type SweepAccessReviews struct {
+	Reminded int
+	Closed   int
+	Command
+}
@@ -8,3 +8,10 @@ type LoginByGoogle struct {
 	RefreshToken string
 	Command
 }
+
+type ExplainAuthorization struct {
+	UserID string `json:"user_id"`
+	Method string `json:"method" binding:"required"`
+	Path   string `json:"path" binding:"required"`
+	IP     string `json:"ip"`
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"authorization/domain"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type RequestElevation struct {
+	TeamID        uuid.UUID       `json:"-"`
+	RequestID     ulid.ULID       `json:"-"`
+	Role          domain.RoleType `json:"role" binding:"required"`
+	Hours         int             `json:"hours" binding:"required"`
+	Justification string          `json:"justification" binding:"required"`
+	User          domain.User
+	Command
+}
+
+type ApproveElevation struct {
+	TeamID    uuid.UUID
+	RequestID ulid.ULID
+	User      domain.User
+	Command
+}
+
+type DenyElevation struct {
+	TeamID    uuid.UUID
+	RequestID ulid.ULID
+	User      domain.User
+	Command
+}
+
+type CancelElevation struct {
+	TeamID    uuid.UUID
+	RequestID ulid.ULID
+	User      domain.User
+	Command
+}
+
+type RevertElevation struct {
+	RequestID ulid.ULID
+	Command
+}
@@ -11,14 +11,12 @@ type SendInvitation struct {
 	TeamID   uuid.UUID `json:"team_id"`
 	Invitees []Invitee `json:"invitees"`
 	Sender   domain.User
+	Results  []domain.InvitationRowResult
 	Command
 }
 
 // make a stuct that contains Email and Role, and use it in the InviteMember struct
-type Invitee struct {
-	Email string          `json:"email"`
-	Role  domain.RoleType `json:"role"`
-}
+type Invitee = domain.InvitationRow
 
 type UpdateInvitationStatus struct {
 	InvitationID ulid.ULID `json:"invitation_id"`
@@ -39,3 +37,54 @@ type ResendInvitation struct {
 	Sender       domain.User
 	Command
 }
+
+type ClaimInvitation struct {
+	Token         string `json:"token" binding:"required"`
+	TeamID        uuid.UUID
+	EmailMismatch bool
+	User          domain.User
+	Command
+}
+
+type RevokeInvitations struct {
+	TeamID        uuid.UUID   `json:"-"`
+	InvitationIDs []ulid.ULID `json:"invitation_ids" binding:"required,min=1"`
+	Revoked       int
+	User          domain.User
+	Command
+}
+
+type ChangeInvitationRole struct {
+	TeamID       uuid.UUID       `json:"-"`
+	InvitationID ulid.ULID       `json:"-"`
+	Role         domain.RoleType `json:"role" binding:"required"`
+	User         domain.User
+	Command
+}
+
+type ExpireInvitations struct {
+	Expired int64
+	Command
+}
+
+type ImportInvitations struct {
+	TeamID   uuid.UUID
+	ImportID ulid.ULID
+	Invitees []Invitee `json:"invitees"`
+	Async    bool
+	Sender   domain.User
+	Command
+}
+
+type ProcessInvitationImport struct {
+	ImportID ulid.ULID
+	Command
+}
+
+type UpdateInvitationSettings struct {
+	TeamID               uuid.UUID `json:"-"`
+	ExpiresInHours       int       `json:"expires_in_hours" binding:"required"`
+	ReminderOffsetsHours []int     `json:"reminder_offsets_hours"`
+	User                 domain.User
+	Command
+}
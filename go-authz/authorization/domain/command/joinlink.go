@@ -0,0 +1,34 @@
+package command
+
+import (
+	"authorization/domain"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type CreateJoinLink struct {
+	TeamID      uuid.UUID
+	LinkID      ulid.ULID
+	Role        domain.RoleType `json:"role" binding:"required"`
+	MaxUses     int             `json:"max_uses"`
+	ExpiresAt   *time.Time      `json:"expires_at"`
+	EmailDomain string          `json:"email_domain"`
+	User        domain.User
+	Command
+}
+
+type RevokeJoinLink struct {
+	TeamID uuid.UUID
+	LinkID ulid.ULID
+	User   domain.User
+	Command
+}
+
+type RedeemJoinLink struct {
+	Token  string
+	TeamID uuid.UUID
+	User   domain.User
+	Command
+}
@@ -0,0 +1,38 @@
+package command
+
+import (
+	"authorization/domain"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type RequestToJoin struct {
+	TeamID    uuid.UUID `json:"-"`
+	RequestID ulid.ULID `json:"-"`
+	Message   string    `json:"message"`
+	User      domain.User
+	Command
+}
+
+type ApproveJoinRequest struct {
+	TeamID    uuid.UUID       `json:"-"`
+	RequestID ulid.ULID       `json:"-"`
+	Role      domain.RoleType `json:"role"`
+	User      domain.User
+	Command
+}
+
+type DenyJoinRequest struct {
+	TeamID    uuid.UUID
+	RequestID ulid.ULID
+	User      domain.User
+	Command
+}
+
+type CancelJoinRequest struct {
+	TeamID    uuid.UUID
+	RequestID ulid.ULID
+	User      domain.User
+	Command
+}
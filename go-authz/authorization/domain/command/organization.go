@@ -0,0 +1,60 @@
+package command
+
+import (
+	"authorization/domain"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type CreateOrganization struct {
+	OrganizationID uuid.UUID
+	Name           string `json:"name" binding:"required"`
+	Description    string `json:"description"`
+	User           domain.User
+	Command
+}
+
+type UpdateOrganization struct {
+	OrganizationID uuid.UUID
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	User           domain.User
+	Command
+}
+
+type AddOrganizationMember struct {
+	OrganizationID uuid.UUID
+	Email          string          `json:"email" binding:"required"`
+	Role           domain.RoleType `json:"role" binding:"required"`
+	User           domain.User
+	Command
+}
+
+type ChangeOrganizationMemberRole struct {
+	OrganizationID uuid.UUID
+	MembershipID   uuid.UUID
+	Role           domain.RoleType `json:"role" binding:"required"`
+	User           domain.User
+	Command
+}
+
+type DeleteOrganizationMember struct {
+	OrganizationID uuid.UUID
+	MembershipID   uuid.UUID
+	User           domain.User
+	Command
+}
+
+type AttachOrganizationTeam struct {
+	OrganizationID uuid.UUID
+	TeamID         uuid.UUID
+	User           domain.User
+	Command
+}
+
+type DetachOrganizationTeam struct {
+	OrganizationID uuid.UUID
+	TeamID         uuid.UUID
+	User           domain.User
+	Command
+}
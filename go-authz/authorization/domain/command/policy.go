@@ -0,0 +1,37 @@
+package command
+
+import (
+	"authorization/domain"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type CreatePolicy struct {
+	PolicyID    uuid.UUID
+	TeamID      uuid.UUID
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Permission  string `json:"permission" binding:"required"`
+	Expression  string `json:"expression" binding:"required"`
+	User        domain.User
+	Command
+}
+
+type UpdatePolicy struct {
+	PolicyID    uuid.UUID
+	TeamID      uuid.UUID
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Permission  string `json:"permission"`
+	Expression  string `json:"expression"`
+	IsActive    *bool  `json:"is_active"`
+	User        domain.User
+	Command
+}
+
+type DeletePolicy struct {
+	PolicyID uuid.UUID
+	TeamID   uuid.UUID
+	User     domain.User
+	Command
+}
@@ -0,0 +1,15 @@
+package command
+
+import "authorization/domain"
+
+type WriteRelation struct {
+	Tuple string `json:"tuple" binding:"required"`
+	User  domain.User
+	Command
+}
+
+type DeleteRelation struct {
+	Tuple string `json:"tuple" binding:"required"`
+	User  domain.User
+	Command
+}
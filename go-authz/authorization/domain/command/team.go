@@ -3,7 +3,9 @@ package command
 import (
 	"authorization/domain"
 	"mime/multipart"
+	"time"
 
+	"github.com/oklog/ulid/v2"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -16,10 +18,11 @@ type CreateTeam struct {
 }
 
 type UpdateTeam struct {
-	TeamID      uuid.UUID
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	User        domain.User
+	TeamID         uuid.UUID
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	IsDiscoverable *bool  `json:"is_discoverable"`
+	User           domain.User
 	Command
 }
 
@@ -36,6 +39,12 @@ type DeleteTeamMember struct {
 	Command
 }
 
+type LeaveTeam struct {
+	TeamID uuid.UUID
+	User   domain.User
+	Command
+}
+
 type ChangeMemberRole struct {
 	TeamID       uuid.UUID
 	MembershipID uuid.UUID       `json:"membership_id"`
@@ -52,3 +61,84 @@ type UpdateTeamAvatar struct {
 type DeleteTeamAvatar struct {
 	TeamID uuid.UUID
 }
+
+type SetTeamParent struct {
+	TeamID      uuid.UUID
+	ParentID    uuid.UUID              `json:"parent_id" binding:"required"`
+	Inheritance domain.TeamInheritance `json:"inheritance"`
+	User        domain.User
+	Command
+}
+
+type RemoveTeamParent struct {
+	TeamID uuid.UUID
+	User   domain.User
+	Command
+}
+
+type ProposeOwnershipTransfer struct {
+	TeamID        uuid.UUID
+	TransferID    ulid.ULID
+	MembershipID  uuid.UUID `json:"membership_id" binding:"required"`
+	KeepOwnership bool      `json:"keep_ownership"`
+	User          domain.User
+	Command
+}
+
+type RespondOwnershipTransfer struct {
+	TransferID ulid.ULID
+	Accept     bool `json:"accept"`
+	User       domain.User
+	Command
+}
+
+type CancelOwnershipTransfer struct {
+	TeamID     uuid.UUID
+	TransferID ulid.ULID
+	User       domain.User
+	Command
+}
+
+type RelinquishOwnership struct {
+	TeamID uuid.UUID
+	User   domain.User
+	Command
+}
+
+type ArchiveTeam struct {
+	TeamID uuid.UUID
+	User   domain.User
+	Command
+}
+
+type RestoreTeam struct {
+	TeamID uuid.UUID
+	User   domain.User
+	Command
+}
+
+type PurgeTeam struct {
+	TeamID uuid.UUID
+	Command
+}
+
+// SetMembershipExpiry makes the membership time-bound, a null ExpiresAt makes it permanent again.
+type SetMembershipExpiry struct {
+	TeamID       uuid.UUID
+	MembershipID uuid.UUID
+	ExpiresAt    *time.Time `json:"expires_at"`
+	User         domain.User
+	Command
+}
+
+type ExpireMemberships struct {
+	Expired int
+	Command
+}
+
+type ChangeTeamPlan struct {
+	TeamID uuid.UUID `json:"-"`
+	Plan   string    `json:"plan" binding:"required"`
+	User   domain.User
+	Command
+}
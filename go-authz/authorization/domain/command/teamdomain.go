@@ -0,0 +1,32 @@
+package command
+
+import (
+	"authorization/domain"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type ClaimTeamDomain struct {
+	TeamID   uuid.UUID
+	DomainID ulid.ULID
+	Domain   string                `json:"domain" binding:"required"`
+	Role     domain.RoleType       `json:"role" binding:"required"`
+	JoinMode domain.DomainJoinMode `json:"join_mode"`
+	User     domain.User
+	Command
+}
+
+type VerifyTeamDomain struct {
+	TeamID   uuid.UUID
+	DomainID ulid.ULID
+	User     domain.User
+	Command
+}
+
+type RemoveTeamDomain struct {
+	TeamID   uuid.UUID
+	DomainID ulid.ULID
+	User     domain.User
+	Command
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// cursorTimeLayout keeps the microseconds stored by postgres so that no row is skipped between two pages
+const cursorTimeLayout = "2006-01-02T15:04:05.999999"
+
+// Cursor points at an item of a list ordered by a sort key and the item id. The next page starts right after it,
+// or right before it when the cursor is Backward. It is handed to the clients as an opaque string.
+type Cursor struct {
+	Value    string `json:"v"`
+	ID       string `json:"id"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+func NewTimeCursor(value time.Time, id string) Cursor {
+	return Cursor{Value: value.Format(cursorTimeLayout), ID: id}
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor handed out by Encode, an empty string means the first page.
+func DecodeCursor(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, exception.NewBadRequestException("invalid cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(b, &cursor); err != nil || cursor.ID == "" {
+		return nil, exception.NewBadRequestException("invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+// Page selects a page of a list paginated with a cursor, the list is walked from the cursor in the given order.
+type Page struct {
+	Limit      int
+	Descending bool
+	Cursor     *Cursor
+}
+
+// IsBackward tells whether the page is read backward from the cursor, towards the beginning of the list.
+func (p Page) IsBackward() bool {
+	return p.Cursor != nil && p.Cursor.Backward
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AccessReviewSchema is an access review campaign, the items are only listed when the review is retrieved alone.
+type AccessReviewSchema struct {
+	ID           ulid.ULID                `json:"id"`
+	Name         string                   `json:"name"`
+	Creator      interface{}              `json:"creator,omitempty"`
+	Roles        []string                 `json:"roles"`
+	Deadline     time.Time                `json:"deadline"`
+	AutoRevoke   bool                     `json:"auto_revoke"`
+	Status       string                   `json:"status"`
+	TotalItems   int                      `json:"total_items"`
+	PendingItems int                      `json:"pending_items"`
+	Items        []AccessReviewItemSchema `json:"items,omitempty"`
+	CompletedAt  *time.Time               `json:"completed_at,omitempty"`
+	CreatedAt    time.Time                `json:"created_at"`
+}
+
+// AccessReviewItemSchema is a membership under review along with the decision taken on it.
+type AccessReviewItemSchema struct {
+	ID           ulid.ULID   `json:"id"`
+	TeamID       uuid.UUID   `json:"team_id"`
+	TeamName     string      `json:"team_name"`
+	MembershipID uuid.UUID   `json:"membership_id"`
+	User         interface{} `json:"user"`
+	Role         string      `json:"role"`
+	Decision     string      `json:"decision"`
+	Reviewer     interface{} `json:"reviewer,omitempty"`
+	Note         string      `json:"note,omitempty"`
+	ReviewedAt   *time.Time  `json:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import uuid "github.com/satori/go.uuid"
+
+// DecisionSchema is the trace of a single authorization decision.
+type DecisionSchema struct {
+	Allowed        bool                   `json:"allowed"`
+	Reason         string                 `json:"reason"`
+	UserID         string                 `json:"user_id"`
+	Method         string                 `json:"method"`
+	Path           string                 `json:"path"`
+	MatchedPath    string                 `json:"matched_path"`
+	Endpoint       *EndpointSchema        `json:"endpoint,omitempty"`
+	TeamID         string                 `json:"team_id,omitempty"`
+	OrganizationID string                 `json:"organization_id,omitempty"`
+	IsMember       bool                   `json:"is_member"`
+	Role           string                 `json:"role,omitempty"`
+	RoleSource     string                 `json:"role_source,omitempty"`
+	Permission     string                 `json:"permission,omitempty"`
+	Relation       *RelationCheckSchema   `json:"relation,omitempty"`
+	Policies       []PolicyDecisionSchema `json:"policies,omitempty"`
+}
+
+type EndpointSchema struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Method     string `json:"method"`
+	Permission string `json:"permission,omitempty"`
+	Object     string `json:"object,omitempty"`
+	Relation   string `json:"relation,omitempty"`
+	// AllowArchived is set on the endpoints that stay writable while the team is archived
+	AllowArchived bool `json:"allow_archived,omitempty"`
+	// AllowGuests is set on the endpoints the guests of a team can use
+	AllowGuests bool `json:"allow_guests,omitempty"`
+}
+
+type PolicyDecisionSchema struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Expression string    `json:"expression"`
+	Allowed    bool      `json:"allowed"`
+	Error      string    `json:"error,omitempty"`
+}
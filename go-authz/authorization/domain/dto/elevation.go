@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ElevationRequestSchema is a request of a member to hold a higher role for a while, along with its steps.
+type ElevationRequestSchema struct {
+	ID            ulid.ULID              `json:"id"`
+	TeamID        uuid.UUID              `json:"team_id"`
+	TeamName      string                 `json:"team_name"`
+	User          interface{}            `json:"user"`
+	FromRole      string                 `json:"from_role"`
+	ToRole        string                 `json:"to_role"`
+	Hours         int                    `json:"hours"`
+	Justification string                 `json:"justification"`
+	Status        string                 `json:"status"`
+	AutoApproved  bool                   `json:"auto_approved"`
+	ActivatedAt   *time.Time             `json:"activated_at,omitempty"`
+	EndsAt        *time.Time             `json:"ends_at,omitempty"`
+	EndedAt       *time.Time             `json:"ended_at,omitempty"`
+	Events        []ElevationEventSchema `json:"events,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// ElevationEventSchema is a step of an elevation request, the actor is missing for the steps taken by the service.
+type ElevationEventSchema struct {
+	Action    string      `json:"action"`
+	Actor     interface{} `json:"actor,omitempty"`
+	Note      string      `json:"note,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
 )
 
 type InvitationRetreivalSchema struct {
@@ -15,3 +16,18 @@ type InvitationRetreivalSchema struct {
 	SenderName string              `json:"sender_name"`
 	Team       TeamRetrievalSchema `json:"team"`
 }
+
+// InvitationSchema is an invitation as listed to the admins of its team and in the inbox of the invitee.
+type InvitationSchema struct {
+	ID         ulid.ULID `json:"id"`
+	Email      string    `json:"email"`
+	Status     string    `json:"status"`
+	Role       string    `json:"role"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	TeamID     uuid.UUID `json:"team_id"`
+	TeamName   string    `json:"team_name"`
+	SenderName string    `json:"sender_name"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	MembershipExpiresAt *time.Time `json:"membership_expires_at,omitempty"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type InvitationImportRetrievalSchema struct {
+	ID        ulid.ULID      `json:"id"`
+	TeamID    uuid.UUID      `json:"team_id"`
+	Status    string         `json:"status"`
+	Total     int            `json:"total"`
+	Summary   map[string]int `json:"summary"`
+	Results   []interface{}  `json:"results"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type InvitationSettingsRetrievalSchema struct {
+	TeamID               uuid.UUID `json:"team_id"`
+	ExpiresInHours       int       `json:"expires_in_hours"`
+	ReminderOffsetsHours []int     `json:"reminder_offsets_hours"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
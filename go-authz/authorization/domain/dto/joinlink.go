@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type JoinLinkRetrievalSchema struct {
+	ID          ulid.ULID   `json:"id"`
+	Token       string      `json:"token"`
+	TeamID      uuid.UUID   `json:"team_id"`
+	Role        string      `json:"role"`
+	Creator     interface{} `json:"creator"`
+	MaxUses     int         `json:"max_uses"`
+	Uses        int         `json:"uses"`
+	EmailDomain string      `json:"email_domain,omitempty"`
+	ExpiresAt   time.Time   `json:"expires_at"`
+	RevokedAt   *time.Time  `json:"revoked_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
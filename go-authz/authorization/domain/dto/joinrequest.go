@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// JoinRequestSchema is a request to join a team as listed to the admins of the team.
+type JoinRequestSchema struct {
+	ID         ulid.ULID   `json:"id"`
+	TeamID     uuid.UUID   `json:"team_id"`
+	TeamName   string      `json:"team_name"`
+	User       interface{} `json:"user"`
+	Message    string      `json:"message"`
+	Status     string      `json:"status"`
+	Role       string      `json:"role,omitempty"`
+	ReviewedAt *time.Time  `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type OrganizationRetrievalSchema struct {
+	ID          uuid.UUID                   `json:"id"`
+	Name        string                      `json:"name"`
+	Description string                      `json:"description"`
+	Creator     interface{}                 `json:"creator"`
+	Role        string                      `json:"role,omitempty"`
+	Memberships []MembershipRetrievalSchema `json:"memberships,omitempty"`
+	Teams       []TeamRetrievalSchema       `json:"teams,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
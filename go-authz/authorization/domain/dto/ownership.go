@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type OwnershipTransferRetrievalSchema struct {
+	ID            ulid.ULID   `json:"id"`
+	TeamID        uuid.UUID   `json:"team_id"`
+	TeamName      string      `json:"team_name"`
+	From          interface{} `json:"from"`
+	To            interface{} `json:"to"`
+	KeepOwnership bool        `json:"keep_ownership"`
+	Status        string      `json:"status"`
+	ExpiresAt     time.Time   `json:"expires_at"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
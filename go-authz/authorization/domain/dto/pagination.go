@@ -1,46 +1,25 @@
 package dto
 
-import "math"
-
-type Pagination struct {
-	Page      int           `json:"page"`
-	PageSize  int           `json:"page_size"`
+// CursorPagination is the envelope of the lists paginated with a cursor, pass Next or Prev as the cursor
+// of the following request to get the next or the previous page. They are empty at the ends of the list.
+type CursorPagination struct {
+	Limit     int           `json:"limit"`
 	TotalData int64         `json:"total_data"`
-	TotalPage int           `json:"total_page"`
-	NextPage  int           `json:"next_page"`
-	PrevPage  int           `json:"prev_page"`
+	Next      string        `json:"next,omitempty"`
+	Prev      string        `json:"prev,omitempty"`
 	HasNext   bool          `json:"has_next"`
 	HasPrev   bool          `json:"has_prev"`
 	Data      []interface{} `json:"data"`
 }
 
-// create a function to generate paginate data
-func Paginate(page, pageSize int, totalData int64, data []interface{}) Pagination {
-	totalPage := int(math.Ceil(float64(totalData) / float64(pageSize)))
-
-	nextPage := -1
-	if page < totalPage {
-		nextPage = page + 1
-	}
-
-	prevPage := -1
-	if page > 1 && page <= totalPage {
-		prevPage = page - 1
-	} else if page > totalPage {
-		prevPage = totalPage
-	}
-
-	hasNext := page < totalPage
-	hasPrev := page > 1
-	return Pagination{
-		Page:      page,
-		PageSize:  pageSize,
+func CursorPaginate(limit int, totalData int64, next, prev string, data []interface{}) CursorPagination {
+	return CursorPagination{
+		Limit:     limit,
 		TotalData: totalData,
-		TotalPage: totalPage,
-		NextPage:  nextPage,
-		PrevPage:  prevPage,
-		HasNext:   hasNext,
-		HasPrev:   hasPrev,
+		Next:      next,
+		Prev:      prev,
+		HasNext:   next != "",
+		HasPrev:   prev != "",
 		Data:      data,
 	}
 }
@@ -0,0 +1,13 @@
+package dto
+
+// QuotaSchema is the usage of a quota, a Limit of 0 is unlimited.
+type QuotaSchema struct {
+	Used  int64 `json:"used"`
+	Limit int   `json:"limit"`
+}
+
+type TeamUsageSchema struct {
+	Plan               string      `json:"plan"`
+	Members            QuotaSchema `json:"members"`
+	PendingInvitations QuotaSchema `json:"pending_invitations"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type PolicyRetrievalSchema struct {
+	ID          uuid.UUID `json:"id"`
+	TeamID      uuid.UUID `json:"team_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permission  string    `json:"permission"`
+	Expression  string    `json:"expression"`
+	IsActive    bool      `json:"is_active"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
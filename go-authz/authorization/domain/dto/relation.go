@@ -0,0 +1,21 @@
+package dto
+
+type RelationCheckSchema struct {
+	Object   string `json:"object"`
+	Relation string `json:"relation"`
+	Subject  string `json:"subject"`
+	Allowed  bool   `json:"allowed"`
+}
+
+type RelationTreeSchema struct {
+	Object   string               `json:"object"`
+	Relation string               `json:"relation"`
+	Subjects []string             `json:"subjects"`
+	Children []RelationTreeSchema `json:"children,omitempty"`
+}
+
+type RelationObjectsSchema struct {
+	ObjectType string   `json:"object_type"`
+	Relation   string   `json:"relation"`
+	ObjectIDs  []string `json:"object_ids"`
+}
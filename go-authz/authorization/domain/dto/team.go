@@ -7,22 +7,33 @@ import (
 )
 
 type TeamRetrievalSchema struct {
-	ID           uuid.UUID                   `json:"id"`
-	Name         string                      `json:"name"`
-	Description  string                      `json:"description"`
-	AvatarURL    string                      `json:"avatar_url"`
-	IsPersonal   bool                        `json:"is_personal"`
-	Creator      interface{}                 `json:"creator"`
-	LastActiveAt time.Time                   `json:"last_active_at,omitempty"`
-	NumOfMembers int64                       `json:"num_of_members,omitempty"`
-	Memberships  []MembershipRetrievalSchema `json:"memberships,omitempty"`
+	ID             uuid.UUID                   `json:"id"`
+	Name           string                      `json:"name"`
+	Description    string                      `json:"description"`
+	AvatarURL      string                      `json:"avatar_url"`
+	IsPersonal     bool                        `json:"is_personal"`
+	IsDiscoverable bool                        `json:"is_discoverable"`
+	ParentID       *uuid.UUID                  `json:"parent_id,omitempty"`
+	Inheritance    string                      `json:"inheritance,omitempty"`
+	Role           string                      `json:"role,omitempty"`
+	RoleSource     string                      `json:"role_source,omitempty"`
+	ArchivedAt     *time.Time                  `json:"archived_at,omitempty"`
+	PurgeAt        *time.Time                  `json:"purge_at,omitempty"`
+	Creator        interface{}                 `json:"creator"`
+	LastActiveAt   time.Time                   `json:"last_active_at,omitempty"`
+	NumOfMembers   int64                       `json:"num_of_members,omitempty"`
+	NumOfRoles     map[string]int64            `json:"num_of_roles,omitempty"`
+	Memberships    []MembershipRetrievalSchema `json:"memberships,omitempty"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type MembershipRetrievalSchema struct {
-	ID   uuid.UUID   `json:"id"`
-	Role string      `json:"role"`
-	User interface{} `json:"user"`
+	ID           uuid.UUID   `json:"id"`
+	Role         string      `json:"role"`
+	User         interface{} `json:"user"`
+	JoinedAt     time.Time   `json:"joined_at"`
+	LastActiveAt time.Time   `json:"last_active_at"`
+	ExpiresAt    *time.Time  `json:"expires_at,omitempty"`
 }
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type TeamDomainRetrievalSchema struct {
+	ID                 ulid.ULID  `json:"id"`
+	TeamID             uuid.UUID  `json:"team_id"`
+	Domain             string     `json:"domain"`
+	Role               string     `json:"role"`
+	JoinMode           string     `json:"join_mode"`
+	VerificationName   string     `json:"verification_name"`
+	VerificationRecord string     `json:"verification_record"`
+	VerifiedAt         *time.Time `json:"verified_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
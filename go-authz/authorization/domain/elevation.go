@@ -0,0 +1,255 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type ElevationStatus string
+
+const (
+	ElevationPending   ElevationStatus = "pending"
+	ElevationActive    ElevationStatus = "active"
+	ElevationDenied    ElevationStatus = "denied"
+	ElevationCancelled ElevationStatus = "cancelled"
+	ElevationEnded     ElevationStatus = "ended"
+)
+
+// ElevationAction is a step of an elevation request, each one is recorded as an ElevationEvent.
+type ElevationAction string
+
+const (
+	ElevationActionRequested    ElevationAction = "requested"
+	ElevationActionApproved     ElevationAction = "approved"
+	ElevationActionAutoApproved ElevationAction = "auto_approved"
+	ElevationActionDenied       ElevationAction = "denied"
+	ElevationActionCancelled    ElevationAction = "cancelled"
+	ElevationActionRelinquished ElevationAction = "relinquished"
+	ElevationActionReverted     ElevationAction = "reverted"
+)
+
+// ElevationAutoApprovePermission is held by no role, the active policies of a team on it approve the elevation
+// requests they all allow without waiting for an owner. The requested role and hours are available to them
+// as request.role and request.hours, e.g.
+//
+//	role.name == "member" && request.role == "admin" && request.hours <= 4
+const ElevationAutoApprovePermission = "elevation:auto-approve"
+
+// MaxElevationJustificationLength bounds the justification the member gives for the elevation.
+const MaxElevationJustificationLength = 500
+
+// ElevationRequest is the request of a member to hold a higher role in the team for a few hours.
+// Once approved the membership holds ToRole until EndsAt, when it goes back to FromRole.
+type ElevationRequest struct {
+	ID            ulid.ULID
+	TeamID        uuid.UUID
+	Team          Team
+	MembershipID  uuid.UUID
+	UserID        uuid.UUID
+	User          User
+	FromRoleID    ulid.ULID
+	FromRole      Role
+	ToRoleID      ulid.ULID
+	ToRole        Role
+	Duration      time.Duration
+	Justification string
+	Status        ElevationStatus
+	ReviewerID    uuid.NullUUID
+	AutoApproved  bool
+	ActivatedAt   *time.Time
+	EndsAt        *time.Time
+	EndedAt       *time.Time
+	Events        []ElevationEvent
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// ElevationEvent records who took a step of an elevation request and when, the actor is not set for the steps
+// taken by the service itself such as an automatic approval or the role going back.
+type ElevationEvent struct {
+	ID        ulid.ULID
+	RequestID ulid.ULID
+	Action    ElevationAction
+	ActorID   uuid.NullUUID
+	Actor     User
+	Note      string
+	CreatedAt time.Time
+}
+
+// NewElevationRequest checks the request of the member holding the membership for the role during hours,
+// maxDuration bounds how long an elevation can last.
+func NewElevationRequest(membership Membership, role Role, hours int, justification string, maxDuration time.Duration) (ElevationRequest, error) {
+	justification = strings.TrimSpace(justification)
+	if justification == "" {
+		return ElevationRequest{}, exception.NewBadRequestException("justification is required")
+	} else if len(justification) > MaxElevationJustificationLength {
+		return ElevationRequest{}, exception.NewBadRequestException(fmt.Sprintf("justification must be at most %d characters", MaxElevationJustificationLength))
+	}
+
+	duration := time.Duration(hours) * time.Hour
+	if hours < 1 || duration > maxDuration {
+		return ElevationRequest{}, exception.NewBadRequestException(fmt.Sprintf("an elevation lasts from 1 to %d hours", int(maxDuration.Hours())))
+	}
+
+	now := util.GetTimestampUTC()
+	return ElevationRequest{
+		ID:            ulid.Make(),
+		TeamID:        membership.TeamID,
+		MembershipID:  membership.ID,
+		UserID:        membership.UserID,
+		FromRoleID:    membership.RoleID,
+		FromRole:      membership.Role,
+		ToRoleID:      role.ID,
+		ToRole:        role,
+		Duration:      duration,
+		Justification: justification,
+		Status:        ElevationPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+func (r ElevationRequest) IsPending() bool {
+	return r.Status == ElevationPending
+}
+
+func (r ElevationRequest) IsActive() bool {
+	return r.Status == ElevationActive
+}
+
+// Approve starts the elevation, reviewerID is not set when the request is approved by the policies of the team.
+// The role of the membership itself is changed by the caller.
+func (r *ElevationRequest) Approve(reviewerID uuid.NullUUID) error {
+	if !r.IsPending() {
+		return exception.NewBadRequestException(fmt.Sprintf("elevation request with ID %s is already %s", r.ID, r.Status))
+	}
+
+	now := util.GetTimestampUTC()
+	endsAt := now.Add(r.Duration)
+	r.Status = ElevationActive
+	r.ReviewerID = reviewerID
+	r.AutoApproved = !reviewerID.Valid
+	r.ActivatedAt = &now
+	r.EndsAt = &endsAt
+	r.UpdatedAt = now
+	return nil
+}
+
+func (r *ElevationRequest) Deny(reviewerID uuid.UUID) error {
+	if !r.IsPending() {
+		return exception.NewBadRequestException(fmt.Sprintf("elevation request with ID %s is already %s", r.ID, r.Status))
+	}
+
+	r.Status = ElevationDenied
+	r.ReviewerID = uuid.NullUUID{UUID: reviewerID, Valid: true}
+	r.UpdatedAt = util.GetTimestampUTC()
+	return nil
+}
+
+// Cancel withdraws a pending request, an active elevation is ended instead.
+func (r *ElevationRequest) Cancel() error {
+	if !r.IsPending() {
+		return exception.NewBadRequestException(fmt.Sprintf("elevation request with ID %s is already %s", r.ID, r.Status))
+	}
+
+	r.Status = ElevationCancelled
+	r.UpdatedAt = util.GetTimestampUTC()
+	return nil
+}
+
+// End closes an active elevation, the role of the membership itself is changed back by the caller.
+func (r *ElevationRequest) End() error {
+	if !r.IsActive() {
+		return exception.NewBadRequestException(fmt.Sprintf("elevation request with ID %s is not active", r.ID))
+	}
+
+	now := util.GetTimestampUTC()
+	r.Status = ElevationEnded
+	r.EndedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// Record adds a step of the request to its events.
+func (r *ElevationRequest) Record(action ElevationAction, actorID uuid.NullUUID, note string) ElevationEvent {
+	event := ElevationEvent{
+		ID:        ulid.Make(),
+		RequestID: r.ID,
+		Action:    action,
+		ActorID:   actorID,
+		Note:      note,
+		CreatedAt: util.GetTimestampUTC(),
+	}
+	r.Events = append(r.Events, event)
+	return event
+}
+
+// ElevationPolicyAttributes are the attributes the auto-approval policies of the team are evaluated against,
+// the role is the one the member holds before the elevation.
+func ElevationPolicyAttributes(request ElevationRequest, user User, team Team) PolicyAttributes {
+	access := Access{RoleName: request.FromRole.Name, Permission: ElevationAutoApprovePermission, Source: AccessSourceTeam}
+	attributes := NewPolicyAttributes(AccessRequest{Time: request.CreatedAt}, user, team, access)
+	attributes.Request["role"] = string(request.ToRole.Name)
+	attributes.Request["hours"] = int64(request.Duration.Hours())
+	attributes.Request["justification"] = request.Justification
+	return attributes
+}
+
+// ElevationOptions filters the elevation requests, the unset fields are ignored.
+// The requests are ordered by creation, a Cursor taken from the last request of a page lists the next one.
+type ElevationOptions struct {
+	TeamID       uuid.UUID
+	MembershipID uuid.UUID
+	Statuses     []ElevationStatus
+	Limit        int
+	Descending   bool
+	Cursor       *Cursor
+}
+
+func (r ElevationRequest) Cursor() Cursor {
+	return NewTimeCursor(r.CreatedAt, r.ID.String())
+}
+
+func (r ElevationRequest) Parse() dto.ElevationRequestSchema {
+	events := make([]dto.ElevationEventSchema, 0, len(r.Events))
+	for _, event := range r.Events {
+		events = append(events, event.Parse())
+	}
+
+	return dto.ElevationRequestSchema{
+		ID:            r.ID,
+		TeamID:        r.TeamID,
+		TeamName:      r.Team.Name,
+		User:          r.User.PublicUser(),
+		FromRole:      string(r.FromRole.Name),
+		ToRole:        string(r.ToRole.Name),
+		Hours:         int(r.Duration.Hours()),
+		Justification: r.Justification,
+		Status:        string(r.Status),
+		AutoApproved:  r.AutoApproved,
+		ActivatedAt:   r.ActivatedAt,
+		EndsAt:        r.EndsAt,
+		EndedAt:       r.EndedAt,
+		Events:        events,
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+func (e ElevationEvent) Parse() dto.ElevationEventSchema {
+	schema := dto.ElevationEventSchema{
+		Action:    string(e.Action),
+		Note:      e.Note,
+		CreatedAt: e.CreatedAt,
+	}
+	if e.ActorID.Valid {
+		schema.Actor = e.Actor.PublicUser()
+	}
+	return schema
+}
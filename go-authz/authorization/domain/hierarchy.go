@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/util"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v2"
+)
+
+// RoleRule tells what a member holding the role can do to the other members of the team.
+// A Guest role belongs to the guest class, its members can only use the endpoints open to guests.
+type RoleRule struct {
+	Name    RoleType   `yaml:"name"`
+	Rank    int        `yaml:"rank"`
+	Guest   bool       `yaml:"guest"`
+	Assigns []RoleType `yaml:"assigns"`
+	Removes []RoleType `yaml:"removes"`
+}
+
+type RoleHierarchyYAML struct {
+	Roles []RoleRule `yaml:"roles"`
+}
+
+// RoleHierarchy holds the assignability rules of the team roles loaded from hierarchy.yml.
+type RoleHierarchy map[RoleType]RoleRule
+
+var roleHierarchy = make(RoleHierarchy)
+
+// LoadRoleHierarchy reads the role hierarchy from hierarchy.yml, it must be called on startup.
+func LoadRoleHierarchy() RoleHierarchy {
+	hierarchyDatas := util.ReadYAML("hierarchy.yml")
+	var hierarchyYAML RoleHierarchyYAML
+	err := yaml.Unmarshal(hierarchyDatas, &hierarchyYAML)
+	if err != nil {
+		log.Fatal().Caller().Err(err).Msg("Failed to unmarshal role hierarchy data")
+	}
+
+	hierarchy, err := NewRoleHierarchy(hierarchyYAML.Roles)
+	if err != nil {
+		log.Fatal().Caller().Err(err).Msg("Invalid role hierarchy")
+	}
+
+	roleHierarchy = hierarchy
+	return roleHierarchy
+}
+
+// NewRoleHierarchy builds the hierarchy and checks that no role assigns or removes a role of the same or a higher rank.
+func NewRoleHierarchy(rules []RoleRule) (RoleHierarchy, error) {
+	hierarchy := make(RoleHierarchy, len(rules))
+	for _, rule := range rules {
+		hierarchy[rule.Name] = rule
+	}
+
+	for _, rule := range rules {
+		for _, role := range append(append([]RoleType{}, rule.Assigns...), rule.Removes...) {
+			target, ok := hierarchy[role]
+			if !ok {
+				return nil, fmt.Errorf("role %s refers to unknown role %s", rule.Name, role)
+			}
+			if target.Rank >= rule.Rank {
+				return nil, fmt.Errorf("role %s cannot manage role %s of the same or a higher rank", rule.Name, role)
+			}
+			if rule.Guest {
+				return nil, fmt.Errorf("guest role %s cannot manage members", rule.Name)
+			}
+		}
+	}
+
+	return hierarchy, nil
+}
+
+func CurrentRoleHierarchy() RoleHierarchy {
+	return roleHierarchy
+}
+
+// CanAssign checks that a member with the actor role can give the role to someone.
+func (h RoleHierarchy) CanAssign(actor, role RoleType) error {
+	rule, err := h.rule(actor)
+	if err != nil {
+		return err
+	}
+
+	if !containsRole(rule.Assigns, role) {
+		return exception.NewForbiddenException(fmt.Sprintf("%s is not allowed to assign the %s role", actor, role))
+	}
+
+	return nil
+}
+
+// CanChange checks that a member with the actor role can change the role of a member from one role to another.
+func (h RoleHierarchy) CanChange(actor, from, to RoleType) error {
+	rule, err := h.rule(actor)
+	if err != nil {
+		return err
+	}
+
+	if !containsRole(rule.Assigns, from) {
+		return exception.NewForbiddenException(fmt.Sprintf("%s is not allowed to change the role of a member with the %s role", actor, from))
+	}
+
+	return h.CanAssign(actor, to)
+}
+
+// CanRemove checks that a member with the actor role can remove a member holding the role.
+func (h RoleHierarchy) CanRemove(actor, role RoleType) error {
+	rule, err := h.rule(actor)
+	if err != nil {
+		return err
+	}
+
+	if !containsRole(rule.Removes, role) {
+		return exception.NewForbiddenException(fmt.Sprintf("%s is not allowed to remove a member with the %s role", actor, role))
+	}
+
+	return nil
+}
+
+// Outranks tells whether the role has a higher rank than the other one.
+func (h RoleHierarchy) Outranks(role, other RoleType) bool {
+	return h[role].Rank > h[other].Rank
+}
+
+// IsGuest tells whether the role belongs to the guest class.
+func (h RoleHierarchy) IsGuest(role RoleType) bool {
+	return h[role].Guest
+}
+
+func (h RoleHierarchy) rule(actor RoleType) (RoleRule, error) {
+	if actor == "" {
+		return RoleRule{}, exception.NewForbiddenException("You are not a member of the team")
+	}
+
+	rule, ok := h[actor]
+	if !ok {
+		return RoleRule{}, exception.NewForbiddenException(fmt.Sprintf("%s is not allowed to manage members", actor))
+	}
+
+	return rule, nil
+}
+
+func containsRole(roles []RoleType, role RoleType) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
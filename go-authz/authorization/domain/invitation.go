@@ -2,7 +2,9 @@ package domain
 
 import (
 	"authorization/controller/exception"
+	"authorization/domain/dto"
 	"authorization/util"
+	"fmt"
 	"time"
 
 	"github.com/badoux/checkmail"
@@ -32,8 +34,10 @@ type Invitation struct {
 	SenderID  uuid.UUID
 	Sender    User
 	IsActive  bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	// MembershipExpiresAt makes the membership granted by the invitation time-bound
+	MembershipExpiresAt *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
 
 func (invitation *Invitation) Validate(action string) map[string]string {
@@ -47,31 +51,112 @@ func (invitation *Invitation) Validate(action string) map[string]string {
 	return errorMessages
 }
 
-func (invitation *Invitation) ResendUpdate() error {
-	// check if invitation is not expired
-	if invitation.ExpiresAt.After(util.GetTimestampUTC()) || invitation.Status != InvitationStatusExpired {
+// invitationTransitions lists the statuses an invitation can move to from each status.
+// An accepted or declined invitation is final, an expired one can only be sent again.
+var invitationTransitions = map[InvitationStatus][]InvitationStatus{
+	InvitationStatusPending: {InvitationStatusSent, InvitationStatusAccepted, InvitationStatusDeclined, InvitationStatusExpired},
+	InvitationStatusSent:    {InvitationStatusAccepted, InvitationStatusDeclined, InvitationStatusExpired},
+	InvitationStatusExpired: {InvitationStatusPending},
+}
+
+// InvitationStatusesOpen are the statuses of the invitations still waiting for an answer.
+var InvitationStatusesOpen = []InvitationStatus{InvitationStatusPending, InvitationStatusSent}
+
+func (status InvitationStatus) CanTransitionTo(to InvitationStatus) bool {
+	for _, next := range invitationTransitions[status] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired tells whether the invitation is past its expiry, the sweeper may not have marked it expired yet.
+func (invitation Invitation) IsExpired() bool {
+	return !invitation.ExpiresAt.After(util.GetTimestampUTC())
+}
+
+// Transition moves the invitation to the given status, only an open invitation is active.
+func (invitation *Invitation) Transition(to InvitationStatus) error {
+	if !invitation.Status.CanTransitionTo(to) {
+		return exception.NewBadRequestException(fmt.Sprintf("invitation can't go from %s to %s", invitation.Status, to))
+	}
+
+	if to == InvitationStatusAccepted && invitation.IsExpired() {
+		return exception.NewBadRequestException("invitation is expired")
+	}
+
+	if to == InvitationStatusAccepted && ValidateMembershipExpiry(invitation.MembershipExpiresAt) != nil {
+		return exception.NewBadRequestException("the membership offered by the invitation has already expired")
+	}
+
+	invitation.Status = to
+	invitation.IsActive = to == InvitationStatusPending || to == InvitationStatusSent
+	invitation.UpdatedAt = util.GetTimestampUTC()
+	return nil
+}
+
+// ResendUpdate opens the expired invitation again for expiresIn.
+func (invitation *Invitation) ResendUpdate(expiresIn time.Duration) error {
+	// an invitation past its expiry is expired even when the sweeper has not run yet
+	if invitation.Status != InvitationStatusExpired && invitation.IsExpired() {
+		if err := invitation.Transition(InvitationStatusExpired); err != nil {
+			return err
+		}
+	}
+
+	if invitation.Status != InvitationStatusExpired {
 		return exception.NewBadRequestException("invitation is not expired")
 	}
 
-	invitation.Status = InvitationStatusPending
-	invitation.ExpiresAt = util.GetTimestampUTC().Add(time.Hour * 24 * 7)
+	if err := invitation.Transition(InvitationStatusPending); err != nil {
+		return err
+	}
+	invitation.ExpiresAt = util.GetTimestampUTC().Add(expiresIn)
 	return nil
 }
 
+// InvitationOptions filters the invitations, the unset fields are ignored.
+// The invitations are ordered by creation, a Cursor taken from the last invitation of a page lists the next one.
+// Email matches the whole address regardless of its case and Query any part of it.
 type InvitationOptions struct {
-	Email     string
-	TeamID    uuid.UUID
-	ExpiresAt time.Time
-	RoleID    ulid.ULID
-	Statuses  []InvitationStatus
-	Limit     int
+	IDs          []ulid.ULID
+	Email        string
+	Query        string
+	TeamID       uuid.UUID
+	ExpiresAfter time.Time
+	RoleID       ulid.ULID
+	Statuses     []InvitationStatus
+	Limit        int
+	Descending   bool
+	Cursor       *Cursor
+}
+
+func (invitation Invitation) Cursor() Cursor {
+	return NewTimeCursor(invitation.CreatedAt, invitation.ID.String())
+}
+
+func (invitation Invitation) Parse() dto.InvitationSchema {
+	return dto.InvitationSchema{
+		ID:         invitation.ID,
+		Email:      invitation.Email,
+		Status:     string(invitation.Status),
+		Role:       string(invitation.Role.Name),
+		ExpiresAt:  invitation.ExpiresAt,
+		TeamID:     invitation.TeamID,
+		TeamName:   invitation.Team.Name,
+		SenderName: invitation.Sender.FullName(),
+		CreatedAt:  invitation.CreatedAt,
+
+		MembershipExpiresAt: invitation.MembershipExpiresAt,
+	}
 }
 
-func NewInvitation(email string, status InvitationStatus, teamID, senderID uuid.UUID, roleID ulid.ULID) Invitation {
+func NewInvitation(email string, status InvitationStatus, teamID, senderID uuid.UUID, roleID ulid.ULID, expiresIn time.Duration) Invitation {
 	return Invitation{
 		ID:        ulid.Make(),
 		Email:     email,
-		ExpiresAt: util.GetTimestampUTC().Add(time.Hour * 24 * 7),
+		ExpiresAt: util.GetTimestampUTC().Add(expiresIn),
 		Status:    status,
 		TeamID:    teamID,
 		RoleID:    roleID,
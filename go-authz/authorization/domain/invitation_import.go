@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// InvitationRow is an invitee of a bulk invitation, as sent in the JSON body or read from a CSV line.
+type InvitationRow struct {
+	Email string   `json:"email"`
+	Role  RoleType `json:"role"`
+	// MembershipExpiresAt makes the membership of the invitee time-bound
+	MembershipExpiresAt *time.Time `json:"membership_expires_at,omitempty"`
+}
+
+// InvitationRowStatus tells what became of an invitee of a bulk invitation.
+type InvitationRowStatus string
+
+const (
+	InvitationRowCreated        InvitationRowStatus = "created"
+	InvitationRowSkippedMember  InvitationRowStatus = "skipped_member"
+	InvitationRowSkippedInvited InvitationRowStatus = "skipped_invited"
+	InvitationRowSkippedQuota   InvitationRowStatus = "skipped_quota"
+	InvitationRowInvalid        InvitationRowStatus = "invalid"
+)
+
+// InvitationRowResult is the line of the report of a bulk invitation, Row counts the invitees from 1.
+type InvitationRowResult struct {
+	Row    int                 `json:"row"`
+	Email  string              `json:"email"`
+	Role   RoleType            `json:"role"`
+	Status InvitationRowStatus `json:"status"`
+	Reason string              `json:"reason,omitempty"`
+}
+
+type InvitationImportStatus string
+
+const (
+	InvitationImportPending   InvitationImportStatus = "pending"
+	InvitationImportCompleted InvitationImportStatus = "completed"
+	InvitationImportFailed    InvitationImportStatus = "failed"
+)
+
+// InvitationImport is a bulk invitation, the small ones are processed right away and the large ones
+// by a background task. Results holds the report once the import is completed, a failed import invited nobody.
+type InvitationImport struct {
+	ID        ulid.ULID
+	TeamID    uuid.UUID
+	SenderID  uuid.UUID
+	Status    InvitationImportStatus
+	Invitees  []InvitationRow
+	Results   []InvitationRowResult
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (i *InvitationImport) Complete(results []InvitationRowResult) {
+	i.Status = InvitationImportCompleted
+	i.Results = results
+	i.UpdatedAt = util.GetTimestampUTC()
+}
+
+func (i *InvitationImport) Fail() {
+	i.Status = InvitationImportFailed
+	i.Results = []InvitationRowResult{}
+	i.UpdatedAt = util.GetTimestampUTC()
+}
+
+func (i InvitationImport) Parse() dto.InvitationImportRetrievalSchema {
+	summary := make(map[string]int)
+	results := make([]interface{}, 0, len(i.Results))
+	for _, result := range i.Results {
+		summary[string(result.Status)]++
+		results = append(results, result)
+	}
+
+	return dto.InvitationImportRetrievalSchema{
+		ID:        i.ID,
+		TeamID:    i.TeamID,
+		Status:    string(i.Status),
+		Total:     len(i.Invitees),
+		Summary:   summary,
+		Results:   results,
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.UpdatedAt,
+	}
+}
+
+func NewInvitationImport(teamID, senderID uuid.UUID, invitees []InvitationRow) InvitationImport {
+	now := util.GetTimestampUTC()
+	return InvitationImport{
+		ID:        ulid.Make(),
+		TeamID:    teamID,
+		SenderID:  senderID,
+		Status:    InvitationImportPending,
+		Invitees:  invitees,
+		Results:   []InvitationRowResult{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// ParseInvitationCSV reads the invitees of a CSV file with an email and an optional role column,
+// a header line naming the columns is skipped and a missing role defaults to member.
+func ParseInvitationCSV(r io.Reader, maxRows int) ([]InvitationRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []InvitationRow
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, exception.NewBadRequestException(fmt.Sprintf("invalid CSV: %s", err.Error()))
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "email") {
+			continue
+		}
+
+		row := InvitationRow{Email: strings.TrimSpace(record[0]), Role: Member}
+		if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+			row.Role = RoleType(strings.ToLower(strings.TrimSpace(record[1])))
+		}
+
+		rows = append(rows, row)
+		if len(rows) > maxRows {
+			return nil, exception.NewBadRequestException(fmt.Sprintf("the file has more than %d invitees", maxRows))
+		}
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"authorization/config"
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"fmt"
+	"sort"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// MinInvitationExpiry and MaxInvitationExpiry bound how long a team keeps its invitations open.
+	MinInvitationExpiry = time.Hour
+	MaxInvitationExpiry = 30 * 24 * time.Hour
+
+	// MaxInvitationReminders is the number of reminders an invitation can get before it expires.
+	MaxInvitationReminders = 5
+)
+
+// InvitationSettings tells how long the invitations of a team are valid, and how long before their expiry
+// the invitees are reminded of them. The teams without settings of their own use the configured ones.
+type InvitationSettings struct {
+	TeamID          uuid.UUID
+	ExpiresIn       time.Duration
+	ReminderOffsets []time.Duration
+	UpdatedAt       time.Time
+}
+
+func DefaultInvitationSettings(teamID uuid.UUID) InvitationSettings {
+	return InvitationSettings{
+		TeamID:          teamID,
+		ExpiresIn:       config.AppConfig.InvitationExpiry,
+		ReminderOffsets: append([]time.Duration(nil), config.AppConfig.InvitationReminderOffsets...),
+	}
+}
+
+func NewInvitationSettings(teamID uuid.UUID, expiresInHours int, reminderOffsetsHours []int) (InvitationSettings, error) {
+	settings := InvitationSettings{
+		TeamID:          teamID,
+		ExpiresIn:       time.Duration(expiresInHours) * time.Hour,
+		ReminderOffsets: make([]time.Duration, 0, len(reminderOffsetsHours)),
+		UpdatedAt:       util.GetTimestampUTC(),
+	}
+	for _, hours := range reminderOffsetsHours {
+		settings.ReminderOffsets = append(settings.ReminderOffsets, time.Duration(hours)*time.Hour)
+	}
+
+	// the reminders are kept from the earliest to the latest one
+	sort.Slice(settings.ReminderOffsets, func(i, j int) bool {
+		return settings.ReminderOffsets[i] > settings.ReminderOffsets[j]
+	})
+
+	return settings, settings.Validate()
+}
+
+func (settings InvitationSettings) Validate() error {
+	if settings.ExpiresIn < MinInvitationExpiry || settings.ExpiresIn > MaxInvitationExpiry {
+		return exception.NewBadRequestException(fmt.Sprintf("invitation expiry must be between %d and %d hours", int(MinInvitationExpiry.Hours()), int(MaxInvitationExpiry.Hours())))
+	}
+
+	if len(settings.ReminderOffsets) > MaxInvitationReminders {
+		return exception.NewBadRequestException(fmt.Sprintf("an invitation can have at most %d reminders", MaxInvitationReminders))
+	}
+
+	seen := make(map[time.Duration]bool, len(settings.ReminderOffsets))
+	for _, offset := range settings.ReminderOffsets {
+		if offset <= 0 || offset >= settings.ExpiresIn {
+			return exception.NewBadRequestException("a reminder must be sent between the invitation and its expiry")
+		}
+		if seen[offset] {
+			return exception.NewBadRequestException(fmt.Sprintf("the reminder %d hours before expiry is set twice", int(offset.Hours())))
+		}
+		seen[offset] = true
+	}
+	return nil
+}
+
+// ReminderTimes are the times the reminders of an invitation expiring at expiresAt are due,
+// the ones already past are left out.
+func (settings InvitationSettings) ReminderTimes(expiresAt time.Time) []time.Time {
+	now := util.GetTimestampUTC()
+	times := make([]time.Time, 0, len(settings.ReminderOffsets))
+	for _, offset := range settings.ReminderOffsets {
+		if at := expiresAt.Add(-offset); at.After(now) {
+			times = append(times, at)
+		}
+	}
+	return times
+}
+
+func (settings InvitationSettings) Parse() dto.InvitationSettingsRetrievalSchema {
+	offsets := make([]int, 0, len(settings.ReminderOffsets))
+	for _, offset := range settings.ReminderOffsets {
+		offsets = append(offsets, int(offset.Hours()))
+	}
+
+	return dto.InvitationSettingsRetrievalSchema{
+		TeamID:               settings.TeamID,
+		ExpiresInHours:       int(settings.ExpiresIn.Hours()),
+		ReminderOffsetsHours: offsets,
+		UpdatedAt:            settings.UpdatedAt,
+	}
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// JoinLink is a shareable link anyone holding its token can redeem to join the team with the role of the link.
+// A link with MaxUses set is used up after that many members joined, one with EmailDomain set only
+// admits the users with a verified email address of that domain.
+type JoinLink struct {
+	ID          ulid.ULID
+	Token       string
+	TeamID      uuid.UUID
+	Team        Team
+	RoleID      ulid.ULID
+	Role        Role
+	CreatorID   uuid.UUID
+	Creator     User
+	MaxUses     int
+	Uses        int
+	EmailDomain string
+	ExpiresAt   time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (l JoinLink) IsRevoked() bool {
+	return l.RevokedAt != nil
+}
+
+func (l JoinLink) IsExpired() bool {
+	return !l.ExpiresAt.After(util.GetTimestampUTC())
+}
+
+func (l JoinLink) IsUsedUp() bool {
+	return l.MaxUses > 0 && l.Uses >= l.MaxUses
+}
+
+// CanRedeem checks that the user may still join the team through the link.
+func (l JoinLink) CanRedeem(user User) error {
+	if l.IsRevoked() {
+		return exception.NewBadRequestException("the join link is revoked")
+	}
+
+	if l.IsExpired() {
+		return exception.NewBadRequestException("the join link is expired")
+	}
+
+	if l.IsUsedUp() {
+		return exception.NewBadRequestException("the join link has reached its maximum number of uses")
+	}
+
+	if l.EmailDomain != "" && (!user.Verified || !strings.HasSuffix(strings.ToLower(user.Email), "@"+l.EmailDomain)) {
+		return exception.NewForbiddenException(fmt.Sprintf("the join link is restricted to verified %s email addresses", l.EmailDomain))
+	}
+
+	return nil
+}
+
+func (l *JoinLink) Revoke() error {
+	if l.IsRevoked() {
+		return exception.NewBadRequestException("the join link is already revoked")
+	}
+
+	now := util.GetTimestampUTC()
+	l.RevokedAt = &now
+	l.UpdatedAt = now
+	return nil
+}
+
+func (l JoinLink) Parse() dto.JoinLinkRetrievalSchema {
+	return dto.JoinLinkRetrievalSchema{
+		ID:          l.ID,
+		Token:       l.Token,
+		TeamID:      l.TeamID,
+		Role:        string(l.Role.Name),
+		Creator:     l.Creator.PublicUser(),
+		MaxUses:     l.MaxUses,
+		Uses:        l.Uses,
+		EmailDomain: l.EmailDomain,
+		ExpiresAt:   l.ExpiresAt,
+		RevokedAt:   l.RevokedAt,
+		CreatedAt:   l.CreatedAt,
+		UpdatedAt:   l.UpdatedAt,
+	}
+}
+
+// NewJoinLink creates a link with a random token, a zero maxUses leaves the number of uses unlimited.
+func NewJoinLink(teamID, creatorID uuid.UUID, roleID ulid.ULID, maxUses int, expiresAt time.Time, emailDomain string) (JoinLink, error) {
+	now := util.GetTimestampUTC()
+	if maxUses < 0 {
+		return JoinLink{}, exception.NewBadRequestException("the maximum number of uses can't be negative")
+	}
+
+	if !expiresAt.After(now) {
+		return JoinLink{}, exception.NewBadRequestException("the expiry of the join link must be in the future")
+	}
+
+	emailDomain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(emailDomain), "@"))
+	if strings.ContainsAny(emailDomain, "@ ") {
+		return JoinLink{}, exception.NewBadRequestException(fmt.Sprintf("%s is not a valid email domain", emailDomain))
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return JoinLink{}, err
+	}
+
+	return JoinLink{
+		ID:          ulid.Make(),
+		Token:       base64.RawURLEncoding.EncodeToString(token),
+		TeamID:      teamID,
+		RoleID:      roleID,
+		CreatorID:   creatorID,
+		MaxUses:     maxUses,
+		EmailDomain: emailDomain,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
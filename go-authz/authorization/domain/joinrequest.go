@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type JoinRequestStatus string
+
+const (
+	JoinRequestPending   JoinRequestStatus = "pending"
+	JoinRequestApproved  JoinRequestStatus = "approved"
+	JoinRequestDenied    JoinRequestStatus = "denied"
+	JoinRequestCancelled JoinRequestStatus = "cancelled"
+)
+
+// MaxJoinRequestMessageLength bounds the message the user leaves to the admins of the team.
+const MaxJoinRequestMessageLength = 500
+
+// JoinRequest is the request of a user to join a discoverable team, the reverse of an invitation.
+// The admins of the team approve it with a role or deny it, the user can cancel it while it is pending.
+// RoleID and ReviewerID are set once the request is reviewed.
+type JoinRequest struct {
+	ID         ulid.ULID
+	TeamID     uuid.UUID
+	Team       Team
+	UserID     uuid.UUID
+	User       User
+	Message    string
+	Status     JoinRequestStatus
+	RoleID     *ulid.ULID
+	Role       Role
+	ReviewerID uuid.NullUUID
+	ReviewedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func NewJoinRequest(teamID, userID uuid.UUID, message string) (JoinRequest, error) {
+	message = strings.TrimSpace(message)
+	if len(message) > MaxJoinRequestMessageLength {
+		return JoinRequest{}, exception.NewBadRequestException(fmt.Sprintf("message must be at most %d characters", MaxJoinRequestMessageLength))
+	}
+
+	now := util.GetTimestampUTC()
+	return JoinRequest{
+		ID:        ulid.Make(),
+		TeamID:    teamID,
+		UserID:    userID,
+		Message:   message,
+		Status:    JoinRequestPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func (r JoinRequest) IsPending() bool {
+	return r.Status == JoinRequestPending
+}
+
+// Approve grants the request with the role, the membership itself is added to the team by the caller.
+func (r *JoinRequest) Approve(reviewerID uuid.UUID, role Role) error {
+	if err := r.review(reviewerID, JoinRequestApproved); err != nil {
+		return err
+	}
+	r.RoleID = &role.ID
+	r.Role = role
+	return nil
+}
+
+func (r *JoinRequest) Deny(reviewerID uuid.UUID) error {
+	return r.review(reviewerID, JoinRequestDenied)
+}
+
+func (r *JoinRequest) Cancel() error {
+	if !r.IsPending() {
+		return exception.NewBadRequestException(fmt.Sprintf("join request with ID %s is already %s", r.ID, r.Status))
+	}
+	r.Status = JoinRequestCancelled
+	r.UpdatedAt = util.GetTimestampUTC()
+	return nil
+}
+
+func (r *JoinRequest) review(reviewerID uuid.UUID, status JoinRequestStatus) error {
+	if !r.IsPending() {
+		return exception.NewBadRequestException(fmt.Sprintf("join request with ID %s is already %s", r.ID, r.Status))
+	}
+
+	now := util.GetTimestampUTC()
+	r.Status = status
+	r.ReviewerID = uuid.NullUUID{UUID: reviewerID, Valid: true}
+	r.ReviewedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// JoinRequestOptions filters the join requests, the unset fields are ignored.
+// The requests are ordered by creation, a Cursor taken from the last request of a page lists the next one.
+type JoinRequestOptions struct {
+	TeamID     uuid.UUID
+	UserID     uuid.UUID
+	Statuses   []JoinRequestStatus
+	Limit      int
+	Descending bool
+	Cursor     *Cursor
+}
+
+func (r JoinRequest) Cursor() Cursor {
+	return NewTimeCursor(r.CreatedAt, r.ID.String())
+}
+
+func (r JoinRequest) Parse() dto.JoinRequestSchema {
+	return dto.JoinRequestSchema{
+		ID:         r.ID,
+		TeamID:     r.TeamID,
+		TeamName:   r.Team.Name,
+		User:       r.User.PublicUser(),
+		Message:    r.Message,
+		Status:     string(r.Status),
+		Role:       string(r.Role.Name),
+		ReviewedAt: r.ReviewedAt,
+		CreatedAt:  r.CreatedAt,
+	}
+}
@@ -3,7 +3,9 @@ package domain
 import (
 	"authorization/controller/exception"
 	"authorization/domain/dto"
+	"authorization/util"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -19,8 +21,41 @@ type Team struct {
 	CreatorID   uuid.UUID
 	Creator     User
 	Memberships []Membership
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// OrganizationID is set when the team belongs to an organization
+	OrganizationID uuid.NullUUID
+	// ParentID is set when the team is a sub-team, Inheritance decides which parent roles flow down to it
+	ParentID    uuid.NullUUID
+	Inheritance TeamInheritance
+	// IsDiscoverable lets the users who are not members request to join the team
+	IsDiscoverable bool
+	// Plan names the plan bounding the team, the default plan is used when it is empty
+	Plan string
+	// ArchivedAt is set while the team is archived, the team is deleted for good at PurgeAt
+	ArchivedAt *time.Time
+	PurgeAt    *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// TeamInheritance tells which members of the parent team are implicitly members of a sub-team.
+// Owners and admins of the parent become admins of the sub-team, members stay members.
+type TeamInheritance string
+
+const (
+	InheritNone    TeamInheritance = "none"
+	InheritAdmins  TeamInheritance = "admins"
+	InheritMembers TeamInheritance = "members"
+)
+
+// MaxTeamDepth bounds how deep sub-teams can be nested, it also bounds the recursive access resolution.
+const MaxTeamDepth = 8
+
+func (i TeamInheritance) Validate() error {
+	switch i {
+	case InheritNone, InheritAdmins, InheritMembers:
+		return nil
+	}
+	return exception.NewBadRequestException(fmt.Sprintf("inheritance must be one of %s, %s or %s", InheritNone, InheritAdmins, InheritMembers))
 }
 
 type Membership struct {
@@ -31,6 +66,10 @@ type Membership struct {
 	User   User
 	RoleID ulid.ULID
 	Role   Role
+	// Source tells whether the membership is held on the team itself or inherited, see AccessSource
+	Source string
+	// ExpiresAt is set on a time-bound membership, it grants nothing once past and is removed by the sweeper
+	ExpiresAt *time.Time
 
 	LastActiveAt time.Time
 	CreatedAt    time.Time
@@ -39,15 +78,59 @@ type Membership struct {
 
 func (m Membership) Parse() dto.MembershipRetrievalSchema {
 	return dto.MembershipRetrievalSchema{
-		ID:   m.ID,
-		User: m.User.PublicUser(),
-		Role: string(m.Role.Name),
+		ID:           m.ID,
+		User:         m.User.PublicUser(),
+		Role:         string(m.Role.Name),
+		JoinedAt:     m.CreatedAt,
+		LastActiveAt: m.LastActiveAt,
+		ExpiresAt:    m.ExpiresAt,
+	}
+}
+
+// IsExpired tells whether the time-bound membership is past its expiry, the sweeper may not have removed it yet.
+func (m Membership) IsExpired() bool {
+	return m.ExpiresAt != nil && !m.ExpiresAt.After(util.GetTimestampUTC())
+}
+
+// SetExpiry makes the membership time-bound, or permanent again when expiresAt is nil.
+func (m *Membership) SetExpiry(expiresAt *time.Time) error {
+	if err := ValidateMembershipExpiry(expiresAt); err != nil {
+		return err
 	}
+
+	m.ExpiresAt = expiresAt
+	m.UpdatedAt = util.GetTimestampUTC()
+	return nil
 }
 
+// ValidateMembershipExpiry checks that a membership would not be granted already expired.
+func ValidateMembershipExpiry(expiresAt *time.Time) error {
+	if expiresAt != nil && !expiresAt.After(util.GetTimestampUTC()) {
+		return exception.NewBadRequestException("membership expiry must be in the future")
+	}
+	return nil
+}
+
+// TeamOptions filters the teams a user can access, directly or through an organization or a parent team.
+// The teams are ordered from the last active one, a Cursor taken from the last team of a page lists the next one.
+type TeamOptions struct {
+	UserID uuid.UUID
+	Name   string
+	// Archived lists the archived teams instead of the active ones
+	Archived bool
+	Limit    int
+	Cursor   *Cursor
+}
+
+// TeamCursor returns the cursor of the team in the list of the teams a user can access.
+func (m Membership) TeamCursor() Cursor {
+	return NewTimeCursor(m.LastActiveAt, m.TeamID.String())
+}
+
+// MembershipOptions filters the memberships of a team or a user, Name searches the name, email and username of the members.
+// The memberships are ordered by SortBy, a Cursor taken from the last membership of a page lists the next one.
 type MembershipOptions struct {
 	Limit        int
-	Skip         int
 	Name         string
 	IsSelectTeam bool
 	IsSelectUser bool
@@ -55,6 +138,37 @@ type MembershipOptions struct {
 	TeamID       uuid.UUID
 	UserID       uuid.UUID
 	RoleID       uuid.UUID
+	Role         RoleType
+	SortBy       MembershipSort
+	Descending   bool
+	Cursor       *Cursor
+}
+
+type MembershipSort string
+
+const (
+	SortByRole         MembershipSort = "role"
+	SortByJoinedAt     MembershipSort = "joined_at"
+	SortByLastActiveAt MembershipSort = "last_active_at"
+)
+
+func (s MembershipSort) Validate() error {
+	switch s {
+	case SortByRole, SortByJoinedAt, SortByLastActiveAt:
+		return nil
+	}
+	return exception.NewBadRequestException(fmt.Sprintf("sort must be one of %s, %s or %s", SortByRole, SortByJoinedAt, SortByLastActiveAt))
+}
+
+// Cursor returns the cursor of the membership in a list ordered by sortBy.
+func (m Membership) Cursor(sortBy MembershipSort) Cursor {
+	switch sortBy {
+	case SortByRole:
+		return Cursor{Value: strconv.Itoa(RolePrecedence.Rank(m.Role.Name)), ID: m.ID.String()}
+	case SortByLastActiveAt:
+		return NewTimeCursor(m.LastActiveAt, m.ID.String())
+	}
+	return NewTimeCursor(m.CreatedAt, m.ID.String())
 }
 
 func (t *Team) Update(payload map[string]any) {
@@ -69,19 +183,27 @@ func (t *Team) Update(payload map[string]any) {
 	if val, ok := payload["avatarURL"].(string); ok && val != "" {
 		t.AvatarURL = val
 	}
+
+	if val, ok := payload["isDiscoverable"].(bool); ok {
+		t.IsDiscoverable = val
+	}
 }
 
-func (t *Team) AddMembership(teamID, userID uuid.UUID, roleID ulid.ULID) {
+// AddMembership adds a member to the team, the membership is time-bound when expiresAt is set.
+func (t *Team) AddMembership(teamID, userID uuid.UUID, roleID ulid.ULID, expiresAt *time.Time) {
 	membership := Membership{
-		ID:     uuid.NewV4(),
-		TeamID: teamID,
-		UserID: userID,
-		RoleID: roleID,
+		ID:        uuid.NewV4(),
+		TeamID:    teamID,
+		UserID:    userID,
+		RoleID:    roleID,
+		ExpiresAt: expiresAt,
 	}
 	t.Memberships = append(t.Memberships, membership)
 }
 
-func (m *Membership) Validation(userID, teamID uuid.UUID, requestedRole RoleType) error {
+// Validation checks that the user, holding the actor role in the team, can change the membership to the requested role
+// or remove it when no role is requested, according to the role hierarchy.
+func (m *Membership) Validation(userID, teamID uuid.UUID, actorRole, requestedRole RoleType) error {
 	if m.UserID == userID {
 		return exception.NewForbiddenException("You cannot change your role")
 	}
@@ -90,12 +212,73 @@ func (m *Membership) Validation(userID, teamID uuid.UUID, requestedRole RoleType
 		return exception.NewForbiddenException(fmt.Sprintf("Team with ID %s is not match with membership-team ID", teamID))
 	}
 
-	if m.Role.Name == Owner {
-		return exception.NewForbiddenException("It's not allowed to change owner role")
+	if requestedRole == "" {
+		return roleHierarchy.CanRemove(actorRole, m.Role.Name)
+	}
+
+	return roleHierarchy.CanChange(actorRole, m.Role.Name, requestedRole)
+}
+
+// IsArchived tells whether the team is read-only and waiting to be deleted.
+func (t *Team) IsArchived() bool {
+	return t.ArchivedAt != nil
+}
+
+// Archive makes the team read-only and schedules its deletion once the grace period is over.
+func (t *Team) Archive(gracePeriod time.Duration) error {
+	if t.IsPersonal {
+		return exception.NewBadRequestException("personal team cannot be archived")
+	}
+
+	if t.IsArchived() {
+		return exception.NewBadRequestException("team is already archived")
+	}
+
+	now := util.GetTimestampUTC()
+	purgeAt := now.Add(gracePeriod)
+	t.ArchivedAt = &now
+	t.PurgeAt = &purgeAt
+	t.UpdatedAt = now
+	return nil
+}
+
+// Restore brings an archived team back as long as it has not been deleted yet.
+func (t *Team) Restore() error {
+	if !t.IsArchived() {
+		return exception.NewBadRequestException("team is not archived")
+	}
+
+	now := util.GetTimestampUTC()
+	if t.PurgeAt != nil && !now.Before(*t.PurgeAt) {
+		return exception.NewForbiddenException("the grace period of the team is over, it cannot be restored")
+	}
+
+	t.ArchivedAt = nil
+	t.PurgeAt = nil
+	t.UpdatedAt = now
+	return nil
+}
+
+// ValidateParent checks that parent can become the parent of the team,
+// ancestors are the ids of parent's ancestors ordered from the closest one
+// and height is how many levels of sub-teams move along with the team.
+func (t *Team) ValidateParent(parent Team, ancestors []uuid.UUID, height int) error {
+	if t.IsPersonal || parent.IsPersonal {
+		return exception.NewBadRequestException("personal team cannot be part of a team hierarchy")
+	}
+
+	if parent.ID == t.ID {
+		return exception.NewBadRequestException("team cannot be its own parent")
+	}
+
+	for _, ancestorID := range ancestors {
+		if ancestorID == t.ID {
+			return exception.NewBadRequestException(fmt.Sprintf("team %s is already an ancestor of team %s", t.ID, parent.ID))
+		}
 	}
 
-	if requestedRole != "" && requestedRole == Owner {
-		return exception.NewForbiddenException("You cannot change role to owner")
+	if len(ancestors)+2+height > MaxTeamDepth {
+		return exception.NewBadRequestException(fmt.Sprintf("teams cannot be nested more than %d levels deep", MaxTeamDepth))
 	}
 
 	return nil
@@ -117,6 +300,7 @@ func NewTeam(user User, roleID ulid.ULID, name, description string, isPersonal b
 		Description: description,
 		IsPersonal:  isPersonal,
 		CreatorID:   user.ID,
+		Inheritance: InheritAdmins,
 		Memberships: []Membership{membership},
 	}
 
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Organization owns teams and shares its owners and admins with them,
+// an organization owner or admin is implicitly an admin of every team of the organization.
+type Organization struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	CreatorID   uuid.UUID
+	Creator     User
+	Memberships []OrganizationMembership
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type OrganizationMembership struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	User           User
+	RoleID         ulid.ULID
+	Role           Role
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (o Organization) Parse() dto.OrganizationRetrievalSchema {
+	return dto.OrganizationRetrievalSchema{
+		ID:          o.ID,
+		Name:        o.Name,
+		Description: o.Description,
+		Creator:     o.Creator.PublicUser(),
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+	}
+}
+
+func (m OrganizationMembership) Parse() dto.MembershipRetrievalSchema {
+	return dto.MembershipRetrievalSchema{
+		ID:   m.ID,
+		User: m.User.PublicUser(),
+		Role: string(m.Role.Name),
+	}
+}
+
+func (o *Organization) Update(payload map[string]any) {
+	if val, ok := payload["name"].(string); ok && val != "" {
+		o.Name = val
+	}
+
+	if val, ok := payload["description"].(string); ok && val != "" {
+		o.Description = val
+	}
+
+	o.UpdatedAt = util.GetTimestampUTC()
+}
+
+func (m *OrganizationMembership) Validation(userID, organizationID uuid.UUID, requestedRole RoleType) error {
+	if m.UserID == userID {
+		return exception.NewForbiddenException("You cannot change your role")
+	}
+
+	if m.OrganizationID != organizationID {
+		return exception.NewForbiddenException(fmt.Sprintf("Organization with ID %s is not match with membership-organization ID", organizationID))
+	}
+
+	if m.Role.Name == Owner {
+		return exception.NewForbiddenException("It's not allowed to change owner role")
+	}
+
+	if requestedRole != "" && requestedRole == Owner {
+		return exception.NewForbiddenException("You cannot change role to owner")
+	}
+
+	return nil
+}
+
+func NewOrganization(user User, roleID ulid.ULID, name, description string) Organization {
+	now := util.GetTimestampUTC()
+	organizationID := uuid.NewV4()
+
+	return Organization{
+		ID:          organizationID,
+		Name:        name,
+		Description: description,
+		CreatorID:   user.ID,
+		Memberships: []OrganizationMembership{NewOrganizationMembership(organizationID, user.ID, roleID)},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func NewOrganizationMembership(organizationID, userID uuid.UUID, roleID ulid.ULID) OrganizationMembership {
+	now := util.GetTimestampUTC()
+	return OrganizationMembership{
+		ID:             uuid.NewV4(),
+		OrganizationID: organizationID,
+		UserID:         userID,
+		RoleID:         roleID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type OwnershipTransferStatus string
+
+var (
+	OwnershipTransferStatusPending   OwnershipTransferStatus = "pending"
+	OwnershipTransferStatusAccepted  OwnershipTransferStatus = "accepted"
+	OwnershipTransferStatusDeclined  OwnershipTransferStatus = "declined"
+	OwnershipTransferStatusCancelled OwnershipTransferStatus = "cancelled"
+	OwnershipTransferStatusExpired   OwnershipTransferStatus = "expired"
+)
+
+// OwnershipTransfer is an owner's proposal to make another member an owner of the team.
+// The proposer keeps the owner role next to the new owner when KeepOwnership is set,
+// otherwise the proposer becomes an admin once the transfer is accepted.
+type OwnershipTransfer struct {
+	ID            ulid.ULID
+	TeamID        uuid.UUID
+	Team          Team
+	FromUserID    uuid.UUID
+	FromUser      User
+	ToUserID      uuid.UUID
+	ToUser        User
+	KeepOwnership bool
+	Status        OwnershipTransferStatus
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (t OwnershipTransfer) Parse() dto.OwnershipTransferRetrievalSchema {
+	return dto.OwnershipTransferRetrievalSchema{
+		ID:            t.ID,
+		TeamID:        t.TeamID,
+		TeamName:      t.Team.Name,
+		From:          t.FromUser.PublicUser(),
+		To:            t.ToUser.PublicUser(),
+		KeepOwnership: t.KeepOwnership,
+		Status:        string(t.Status),
+		ExpiresAt:     t.ExpiresAt,
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
+	}
+}
+
+// Respond records the answer of the proposed owner.
+func (t *OwnershipTransfer) Respond(userID uuid.UUID, accept bool) error {
+	if t.ToUserID != userID {
+		return exception.NewForbiddenException("the ownership transfer is not addressed to you")
+	}
+
+	if t.Status != OwnershipTransferStatusPending {
+		return exception.NewBadRequestException("the ownership transfer is already " + string(t.Status))
+	}
+
+	if t.ExpiresAt.Before(util.GetTimestampUTC()) {
+		return exception.NewBadRequestException("the ownership transfer is expired")
+	}
+
+	t.Status = OwnershipTransferStatusDeclined
+	if accept {
+		t.Status = OwnershipTransferStatusAccepted
+	}
+	t.UpdatedAt = util.GetTimestampUTC()
+	return nil
+}
+
+func (t *OwnershipTransfer) Cancel() error {
+	if t.Status != OwnershipTransferStatusPending {
+		return exception.NewBadRequestException("the ownership transfer is already " + string(t.Status))
+	}
+
+	t.Status = OwnershipTransferStatusCancelled
+	t.UpdatedAt = util.GetTimestampUTC()
+	return nil
+}
+
+// Expire closes a pending transfer left unanswered past its expiry so the team can propose another one.
+func (t *OwnershipTransfer) Expire() error {
+	if t.Status != OwnershipTransferStatusPending {
+		return exception.NewBadRequestException("the ownership transfer is already " + string(t.Status))
+	}
+
+	if !t.ExpiresAt.Before(util.GetTimestampUTC()) {
+		return exception.NewBadRequestException("the ownership transfer is not expired yet")
+	}
+
+	t.Status = OwnershipTransferStatusExpired
+	t.UpdatedAt = util.GetTimestampUTC()
+	return nil
+}
+
+func NewOwnershipTransfer(teamID, fromUserID, toUserID uuid.UUID, keepOwnership bool) OwnershipTransfer {
+	now := util.GetTimestampUTC()
+	return OwnershipTransfer{
+		ID:            ulid.Make(),
+		TeamID:        teamID,
+		FromUserID:    fromUserID,
+		ToUserID:      toUserID,
+		KeepOwnership: keepOwnership,
+		Status:        OwnershipTransferStatusPending,
+		ExpiresAt:     now.Add(time.Hour * 24 * 7),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"authorization/config"
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v2"
+)
+
+// Plan bounds what a team can hold, a quota set to 0 is unlimited.
+type Plan struct {
+	Name                  string `yaml:"name"`
+	MaxMembers            int    `yaml:"max_members"`
+	MaxPendingInvitations int    `yaml:"max_pending_invitations"`
+}
+
+type PlansYAML struct {
+	Plans []Plan `yaml:"plans"`
+}
+
+// Plans holds the plans loaded from plans.yml by name.
+type Plans map[string]Plan
+
+var plans = make(Plans)
+
+// LoadPlans reads the plans from plans.yml, it must be called on startup.
+func LoadPlans() Plans {
+	planDatas := util.ReadYAML("plans.yml")
+	var plansYAML PlansYAML
+	err := yaml.Unmarshal(planDatas, &plansYAML)
+	if err != nil {
+		log.Fatal().Caller().Err(err).Msg("Failed to unmarshal plan data")
+	}
+
+	loaded, err := NewPlans(plansYAML.Plans, config.AppConfig.TeamDefaultPlan)
+	if err != nil {
+		log.Fatal().Caller().Err(err).Msg("Invalid plans")
+	}
+
+	plans = loaded
+	return plans
+}
+
+// NewPlans checks the quotas of the plans and that the default plan is one of them.
+func NewPlans(list []Plan, defaultPlan string) (Plans, error) {
+	loaded := make(Plans, len(list))
+	for _, plan := range list {
+		if plan.Name == "" {
+			return nil, fmt.Errorf("plan name is required")
+		}
+		if _, ok := loaded[plan.Name]; ok {
+			return nil, fmt.Errorf("plan %s is defined twice", plan.Name)
+		}
+		if plan.MaxMembers < 0 || plan.MaxPendingInvitations < 0 {
+			return nil, fmt.Errorf("plan %s has a negative quota", plan.Name)
+		}
+		loaded[plan.Name] = plan
+	}
+
+	if _, ok := loaded[defaultPlan]; !ok {
+		return nil, fmt.Errorf("default plan %s is not defined", defaultPlan)
+	}
+
+	return loaded, nil
+}
+
+func CurrentPlans() Plans {
+	return plans
+}
+
+// Get returns the plan named name.
+func (p Plans) Get(name string) (Plan, error) {
+	plan, ok := p[name]
+	if !ok {
+		return Plan{}, exception.NewBadRequestException(fmt.Sprintf("plan %s does not exist", name))
+	}
+	return plan, nil
+}
+
+// Of returns the plan of the team, the default plan when the team has none of its own.
+func (p Plans) Of(team Team) Plan {
+	if plan, ok := p[team.Plan]; ok {
+		return plan
+	}
+	return p[config.AppConfig.TeamDefaultPlan]
+}
+
+// TeamUsage is what the team holds against the quotas of its plan.
+type TeamUsage struct {
+	Members            int64
+	PendingInvitations int64
+}
+
+// CheckMembers tells whether the team has a seat left for each of the added members.
+func (p Plan) CheckMembers(usage TeamUsage, added int) error {
+	if p.MaxMembers > 0 && usage.Members+int64(added) > int64(p.MaxMembers) {
+		return exception.NewForbiddenException(fmt.Sprintf("the team has reached the %d members of its %s plan", p.MaxMembers, p.Name))
+	}
+	return nil
+}
+
+// CheckInvitations tells whether the team can hold the added invitations along with the ones waiting for an answer.
+func (p Plan) CheckInvitations(usage TeamUsage, added int) error {
+	if p.MaxPendingInvitations > 0 && usage.PendingInvitations+int64(added) > int64(p.MaxPendingInvitations) {
+		return exception.NewForbiddenException(fmt.Sprintf("the team has reached the %d pending invitations of its %s plan", p.MaxPendingInvitations, p.Name))
+	}
+	return nil
+}
+
+func (u TeamUsage) Parse(plan Plan) dto.TeamUsageSchema {
+	return dto.TeamUsageSchema{
+		Plan: plan.Name,
+		Members: dto.QuotaSchema{
+			Used:  u.Members,
+			Limit: plan.MaxMembers,
+		},
+		PendingInvitations: dto.QuotaSchema{
+			Used:  u.PendingInvitations,
+			Limit: plan.MaxPendingInvitations,
+		},
+	}
+}
@@ -0,0 +1,267 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AnyPermission makes a policy apply to every permission of the team.
+const AnyPermission = "*"
+
+// Policy is a team scoped condition evaluated after the role lookup grants a permission.
+// Every active policy matching the permission must evaluate to true, otherwise access is denied.
+//
+// Expressions use CEL over the request, user, team and role attributes, e.g.
+//
+//	role.name != "finance" || inCidr(request.ip, "10.0.0.0/8")
+//	request.time.getDayOfWeek("Asia/Jakarta") in [1, 2, 3, 4, 5]
+type Policy struct {
+	ID          uuid.UUID
+	TeamID      uuid.UUID
+	Name        string
+	Description string
+	Permission  string
+	Expression  string
+	IsActive    bool
+	CreatorID   uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// AccessRequest describes a single request that the ext-authz server has to authorize.
+type AccessRequest struct {
+	UserID string
+	Method string
+	Path   string
+	IP     string
+	Time   time.Time
+}
+
+func (p Policy) Parse() dto.PolicyRetrievalSchema {
+	return dto.PolicyRetrievalSchema{
+		ID:          p.ID,
+		TeamID:      p.TeamID,
+		Name:        p.Name,
+		Description: p.Description,
+		Permission:  p.Permission,
+		Expression:  p.Expression,
+		IsActive:    p.IsActive,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+func (p *Policy) Update(payload map[string]any) {
+	if val, ok := payload["name"].(string); ok && val != "" {
+		p.Name = val
+	}
+
+	if val, ok := payload["description"].(string); ok && val != "" {
+		p.Description = val
+	}
+
+	if val, ok := payload["permission"].(string); ok && val != "" {
+		p.Permission = val
+	}
+
+	if val, ok := payload["expression"].(string); ok && val != "" {
+		p.Expression = val
+	}
+
+	if val, ok := payload["isActive"].(*bool); ok && val != nil {
+		p.IsActive = *val
+	}
+
+	p.UpdatedAt = util.GetTimestampUTC()
+}
+
+// Validate compiles the expression so an invalid policy is rejected on save instead of on access.
+func (p Policy) Validate() error {
+	if p.Name == "" {
+		return exception.NewBadRequestException("policy name is required")
+	}
+	if p.Permission == "" {
+		return exception.NewBadRequestException("policy permission is required")
+	}
+	if _, err := compilePolicy(p.Expression); err != nil {
+		return exception.NewBadRequestException(err.Error())
+	}
+	return nil
+}
+
+// Evaluate runs the expression against attributes, an expression that does not yield a boolean is an error.
+func (p Policy) Evaluate(attributes PolicyAttributes) (bool, error) {
+	program, err := compilePolicy(p.Expression)
+	if err != nil {
+		return false, err
+	}
+
+	out, _, err := program.Eval(map[string]any{
+		"request": attributes.Request,
+		"user":    attributes.User,
+		"team":    attributes.Team,
+		"role":    attributes.Role,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	allowed, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("policy %s evaluated to %s, expected bool", p.Name, out.Type().TypeName())
+	}
+	return allowed, nil
+}
+
+type Policies []Policy
+
+// PolicyAttributes are the variables available to a policy expression.
+type PolicyAttributes struct {
+	Request map[string]any
+	User    map[string]any
+	Team    map[string]any
+	Role    map[string]any
+}
+
+func NewPolicyAttributes(request AccessRequest, user User, team Team, access Access) PolicyAttributes {
+	requestTime := request.Time
+	if requestTime.IsZero() {
+		requestTime = util.GetTimestampUTC()
+	}
+
+	return PolicyAttributes{
+		Request: map[string]any{
+			"method":     request.Method,
+			"path":       request.Path,
+			"ip":         request.IP,
+			"time":       requestTime,
+			"permission": access.Permission,
+		},
+		User: map[string]any{
+			"id":         user.ID.String(),
+			"email":      user.Email,
+			"username":   user.Username,
+			"first_name": user.FirstName,
+			"last_name":  user.LastName,
+			"verified":   user.Verified,
+			"provider":   user.Provider,
+		},
+		Team: map[string]any{
+			"id":          team.ID.String(),
+			"name":        team.Name,
+			"is_personal": team.IsPersonal,
+		},
+		Role: map[string]any{
+			"name": string(access.RoleName),
+		},
+	}
+}
+
+func NewPolicy(teamID, creatorID uuid.UUID, name, description, permission, expression string) Policy {
+	now := util.GetTimestampUTC()
+	return Policy{
+		ID:          uuid.NewV4(),
+		TeamID:      teamID,
+		Name:        name,
+		Description: description,
+		Permission:  permission,
+		Expression:  expression,
+		IsActive:    true,
+		CreatorID:   creatorID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+var (
+	policyEnvOnce sync.Once
+	policyEnv     *cel.Env
+	policyEnvErr  error
+	// policyPrograms caches compiled programs by expression, the ext-authz server evaluates them on every request
+	policyPrograms sync.Map
+)
+
+func policyEnvironment() (*cel.Env, error) {
+	policyEnvOnce.Do(func() {
+		attributes := cel.MapType(cel.StringType, cel.DynType)
+		policyEnv, policyEnvErr = cel.NewEnv(
+			cel.Variable("request", attributes),
+			cel.Variable("user", attributes),
+			cel.Variable("team", attributes),
+			cel.Variable("role", attributes),
+			cel.Function("inCidr",
+				cel.Overload("in_cidr_string_string",
+					[]*cel.Type{cel.StringType, cel.StringType},
+					cel.BoolType,
+					cel.BinaryBinding(inCidr),
+				),
+			),
+		)
+	})
+	return policyEnv, policyEnvErr
+}
+
+func compilePolicy(expression string) (cel.Program, error) {
+	if program, ok := policyPrograms.Load(expression); ok {
+		return program.(cel.Program), nil
+	}
+
+	if expression == "" {
+		return nil, fmt.Errorf("policy expression is required")
+	}
+
+	env, err := policyEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid policy expression: %s", issues.Err())
+	}
+
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("policy expression must return bool, got %s", ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	policyPrograms.Store(expression, program)
+	return program, nil
+}
+
+func inCidr(ip, cidr ref.Val) ref.Val {
+	ipString, ok := ip.Value().(string)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(ip)
+	}
+	cidrString, ok := cidr.Value().(string)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(cidr)
+	}
+
+	_, network, err := net.ParseCIDR(cidrString)
+	if err != nil {
+		return types.NewErr("invalid cidr %q", cidrString)
+	}
+
+	parsedIP := net.ParseIP(ipString)
+	if parsedIP == nil {
+		return types.False
+	}
+
+	return types.Bool(network.Contains(parsedIP))
+}
@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/util"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	UserNamespace         = "user"
+	OrganizationNamespace = "organization"
+	TeamNamespace         = "team"
+	ApplicationNamespace  = "application"
+)
+
+// ParentRelation links a sub-team or an application to the team it belongs to
+const ParentRelation = "parent"
+
+// RelationTuple is a single relationship in the form object#relation@subject,
+// e.g. team:1234#admin@user:5678 or application:42#viewer@team:1234#member.
+// A tuple whose subject has a relation points to a userset instead of a single subject.
+type RelationTuple struct {
+	ObjectType      string
+	ObjectID        string
+	Relation        string
+	SubjectType     string
+	SubjectID       string
+	SubjectRelation string
+	CreatedAt       time.Time
+}
+
+type RelationTupleOptions struct {
+	ObjectType  string
+	ObjectID    string
+	Relation    string
+	SubjectType string
+	SubjectID   string
+	Limit       int
+}
+
+// RelationObjectOptions selects the objects of ObjectType on which the subject has Relation,
+// Rewrites are the rewrite rules of every namespace the lookup may follow.
+type RelationObjectOptions struct {
+	ObjectType      string
+	Relation        string
+	SubjectType     string
+	SubjectID       string
+	SubjectRelation string
+	Rewrites        []RelationRewriteRule
+}
+
+func (t RelationTuple) Object() string {
+	return t.ObjectType + ":" + t.ObjectID
+}
+
+func (t RelationTuple) Subject() string {
+	subject := t.SubjectType + ":" + t.SubjectID
+	if t.SubjectRelation != "" {
+		subject += "#" + t.SubjectRelation
+	}
+	return subject
+}
+
+func (t RelationTuple) String() string {
+	return t.Object() + "#" + t.Relation + "@" + t.Subject()
+}
+
+// IsUserset reports whether the tuple subject refers to a set of subjects (e.g. team:1#member).
+func (t RelationTuple) IsUserset() bool {
+	return t.SubjectRelation != ""
+}
+
+// ParseObject parses "type:id" into its parts.
+func ParseObject(object string) (string, string, error) {
+	parts := strings.SplitN(object, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", exception.NewBadRequestException(fmt.Sprintf("invalid object %q, expected type:id", object))
+	}
+	return parts[0], parts[1], nil
+}
+
+// ParseSubject parses "type:id" or "type:id#relation" into its parts.
+func ParseSubject(subject string) (string, string, string, error) {
+	var relation string
+	if i := strings.Index(subject, "#"); i >= 0 {
+		relation = subject[i+1:]
+		subject = subject[:i]
+		if relation == "" {
+			return "", "", "", exception.NewBadRequestException(fmt.Sprintf("invalid subject %q, relation is empty", subject))
+		}
+	}
+	subjectType, subjectID, err := ParseObject(subject)
+	if err != nil {
+		return "", "", "", err
+	}
+	return subjectType, subjectID, relation, nil
+}
+
+// ParseRelationTuple parses the object#relation@subject notation.
+func ParseRelationTuple(tuple string) (RelationTuple, error) {
+	at := strings.Index(tuple, "@")
+	if at < 0 {
+		return RelationTuple{}, exception.NewBadRequestException(fmt.Sprintf("invalid relation tuple %q, subject is missing", tuple))
+	}
+	hash := strings.Index(tuple[:at], "#")
+	if hash < 0 {
+		return RelationTuple{}, exception.NewBadRequestException(fmt.Sprintf("invalid relation tuple %q, relation is missing", tuple))
+	}
+	objectType, objectID, err := ParseObject(tuple[:hash])
+	if err != nil {
+		return RelationTuple{}, err
+	}
+	subjectType, subjectID, subjectRelation, err := ParseSubject(tuple[at+1:])
+	if err != nil {
+		return RelationTuple{}, err
+	}
+	return NewRelationTuple(objectType, objectID, tuple[hash+1:at], subjectType, subjectID, subjectRelation), nil
+}
+
+func NewRelationTuple(objectType, objectID, relation, subjectType, subjectID, subjectRelation string) RelationTuple {
+	return RelationTuple{
+		ObjectType:      objectType,
+		ObjectID:        objectID,
+		Relation:        relation,
+		SubjectType:     subjectType,
+		SubjectID:       subjectID,
+		SubjectRelation: subjectRelation,
+		CreatedAt:       util.GetTimestampUTC(),
+	}
+}
+
+// NamespaceYAML describes the relations of every object type, loaded from relations.yml.
+type NamespaceYAML struct {
+	Namespaces []Namespace `yaml:"namespaces"`
+}
+
+// Namespace is the relation configuration of a single object type.
+// Manage is the relation a subject needs on an object to write tuples for it or to inspect them.
+// Tuples of a Mirrored namespace are maintained from another source (e.g. memberships)
+// and cannot be written through the API.
+// Owner is the relation pointing to the team owning an object, a new object can only be claimed
+// through it and its ID must be scoped by the owning team, e.g. application:<team_id>/42.
+type Namespace struct {
+	Name      string              `yaml:"name"`
+	Manage    string              `yaml:"manage"`
+	Owner     string              `yaml:"owner"`
+	Mirrored  bool                `yaml:"mirrored"`
+	Relations []NamespaceRelation `yaml:"relations"`
+}
+
+// NamespaceRelation is a relation whose subjects are the direct tuples plus
+// the union of its rewrite rules.
+type NamespaceRelation struct {
+	Name  string            `yaml:"name"`
+	Union []RelationRewrite `yaml:"union"`
+}
+
+// RelationRewrite either points to another relation of the same object (Computed)
+// or follows the TupleToUserset relation to another object and checks Computed there.
+type RelationRewrite struct {
+	Computed       string `yaml:"computed"`
+	TupleToUserset string `yaml:"tuple_to_userset"`
+}
+
+// RelationRewriteRule is a rewrite rule of a namespace relation flattened for set-based lookups.
+type RelationRewriteRule struct {
+	ObjectType     string
+	Relation       string
+	TupleToUserset string
+	Computed       string
+}
+
+func (n Namespace) Relation(name string) (NamespaceRelation, bool) {
+	for _, r := range n.Relations {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return NamespaceRelation{}, false
+}
+
+// Rewrites flattens the rewrite rules of every relation of the namespace.
+func (n Namespace) Rewrites() []RelationRewriteRule {
+	rules := make([]RelationRewriteRule, 0)
+	for _, r := range n.Relations {
+		for _, rewrite := range r.Union {
+			rules = append(rules, RelationRewriteRule{
+				ObjectType:     n.Name,
+				Relation:       r.Name,
+				TupleToUserset: rewrite.TupleToUserset,
+				Computed:       rewrite.Computed,
+			})
+		}
+	}
+	return rules
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/util"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// DomainJoinMode tells what happens to the users of a verified team domain, they either join the team
+// right away or get an invitation they can accept.
+type DomainJoinMode string
+
+const (
+	DomainJoinAuto    DomainJoinMode = "auto"
+	DomainJoinPropose DomainJoinMode = "propose"
+)
+
+func (m DomainJoinMode) Validate() error {
+	switch m {
+	case DomainJoinAuto, DomainJoinPropose:
+		return nil
+	}
+	return exception.NewBadRequestException(fmt.Sprintf("join mode must be one of %s or %s", DomainJoinAuto, DomainJoinPropose))
+}
+
+// DomainVerificationPrefix prefixes the TXT record proving the team controls a domain,
+// the record is looked up on the _authz-verification subdomain.
+const DomainVerificationPrefix = "authz-verification="
+
+var domainNamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// TeamDomain is an email domain claimed by a team. Once verified through DNS the users with a verified email
+// address of the domain join the team with Role, or are invited to it, depending on JoinMode.
+type TeamDomain struct {
+	ID                ulid.ULID
+	TeamID            uuid.UUID
+	Team              Team
+	Domain            string
+	RoleID            ulid.ULID
+	Role              Role
+	JoinMode          DomainJoinMode
+	VerificationToken string
+	VerifiedAt        *time.Time
+	CreatorID         uuid.UUID
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (d TeamDomain) IsVerified() bool {
+	return d.VerifiedAt != nil
+}
+
+// VerificationName is the name of the TXT record to create in the DNS of the domain.
+func (d TeamDomain) VerificationName() string {
+	return "_authz-verification." + d.Domain
+}
+
+// VerificationRecord is the value of the TXT record to create in the DNS of the domain.
+func (d TeamDomain) VerificationRecord() string {
+	return DomainVerificationPrefix + d.VerificationToken
+}
+
+// Verify marks the domain verified when one of the TXT records found holds the verification record.
+func (d *TeamDomain) Verify(records []string) error {
+	if d.IsVerified() {
+		return exception.NewBadRequestException(fmt.Sprintf("domain %s is already verified", d.Domain))
+	}
+
+	for _, record := range records {
+		if strings.TrimSpace(record) == d.VerificationRecord() {
+			now := util.GetTimestampUTC()
+			d.VerifiedAt = &now
+			d.UpdatedAt = now
+			return nil
+		}
+	}
+
+	return exception.NewBadRequestException(fmt.Sprintf("TXT record %s is not found on %s", d.VerificationRecord(), d.VerificationName()))
+}
+
+func (d TeamDomain) Parse() dto.TeamDomainRetrievalSchema {
+	return dto.TeamDomainRetrievalSchema{
+		ID:                 d.ID,
+		TeamID:             d.TeamID,
+		Domain:             d.Domain,
+		Role:               string(d.Role.Name),
+		JoinMode:           string(d.JoinMode),
+		VerificationName:   d.VerificationName(),
+		VerificationRecord: d.VerificationRecord(),
+		VerifiedAt:         d.VerifiedAt,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
+	}
+}
+
+func NewTeamDomain(teamID, creatorID uuid.UUID, name string, roleID ulid.ULID, joinMode DomainJoinMode) (TeamDomain, error) {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "@"))
+	if !domainNamePattern.MatchString(name) {
+		return TeamDomain{}, exception.NewBadRequestException(fmt.Sprintf("%s is not a valid domain", name))
+	}
+
+	if joinMode == "" {
+		joinMode = DomainJoinAuto
+	}
+	if err := joinMode.Validate(); err != nil {
+		return TeamDomain{}, err
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return TeamDomain{}, err
+	}
+
+	now := util.GetTimestampUTC()
+	return TeamDomain{
+		ID:                ulid.Make(),
+		TeamID:            teamID,
+		Domain:            name,
+		RoleID:            roleID,
+		JoinMode:          joinMode,
+		VerificationToken: hex.EncodeToString(token),
+		CreatorID:         creatorID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, nil
+}
@@ -33,6 +33,26 @@ type User struct {
 
 type Users []User
 
+// UserOptions selects a page of the active users ordered by creation.
+type UserOptions struct {
+	Limit      int
+	Descending bool
+	Cursor     *Cursor
+}
+
+// EmailDomain returns the lower cased domain of the email address of the user.
+func (u User) EmailDomain() string {
+	at := strings.LastIndex(u.Email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(u.Email[at+1:])
+}
+
+func (u User) Cursor() Cursor {
+	return NewTimeCursor(u.CreatedAt, u.ID.String())
+}
+
 // So that we dont expose the user's email address and password to the world
 func (users Users) PublicUsers() []interface{} {
 	result := make([]interface{}, len(users))
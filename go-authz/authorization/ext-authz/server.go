@@ -3,10 +3,14 @@ package main
 import (
 	"authorization/config"
 	"authorization/domain"
+	"authorization/domain/dto"
+	"authorization/infrastructure/persistence"
 	"authorization/infrastructure/worker"
+	"authorization/repository"
 	"authorization/util"
 	"authorization/view"
 	"context"
+	"encoding/json"
 	"net"
 	"strings"
 
@@ -19,7 +23,6 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	_status "google.golang.org/grpc/status"
-	"gopkg.in/yaml.v2"
 )
 
 type AuthorizationServer struct {
@@ -35,19 +38,29 @@ func (a *AuthorizationServer) Check(ctx context.Context, req *auth.CheckRequest)
 
 	log.Printf("authorization for user_id: %s to path %s and method %s", userID, path, method)
 
-	isAuthorized, err := view.Authorization(ctx, userID, method, path, a.Endpoints)
+	request := domain.AccessRequest{
+		UserID: userID,
+		Method: method,
+		Path:   path,
+		IP:     clientIP(req),
+		Time:   util.GetTimestampUTC(),
+	}
+
+	decision, err := view.Decide(ctx, request, a.Endpoints)
 	if err != nil {
 		log.Printf("Error while authorizing: %v", err)
 		return nil, _status.Errorf(codes.Internal, "Error while authorizing: %v", err)
 	}
 
-	if isAuthorized {
+	if decision.Allowed {
 		return &auth.CheckResponse{
 			Status:       &status.Status{Code: int32(rpc.OK)},
 			HttpResponse: &auth.CheckResponse_OkResponse{},
 		}, nil
 	}
 
+	log.Printf("authorization denied for user_id: %s to path %s and method %s: %s", userID, path, method, decision.Reason)
+
 	return &auth.CheckResponse{
 		Status: &status.Status{Code: int32(rpc.PERMISSION_DENIED)},
 		HttpResponse: &auth.CheckResponse_DeniedResponse{
@@ -55,12 +68,42 @@ func (a *AuthorizationServer) Check(ctx context.Context, req *auth.CheckRequest)
 				Status: &envoy_type.HttpStatus{
 					Code: envoy_type.StatusCode_Forbidden,
 				},
-				Body: "You are not authorized to access this resource",
+				Body: deniedBody(decision),
 			},
 		},
 	}, nil
 }
 
+// deniedBody attaches the decision trace outside production so a denial can be debugged from the response
+func deniedBody(decision *dto.DecisionSchema) string {
+	message := "You are not authorized to access this resource"
+	if config.AppConfig.AppEnv == "production" {
+		return message
+	}
+
+	body, err := json.Marshal(map[string]any{"message": message, "decision": decision})
+	if err != nil {
+		return message
+	}
+	return string(body)
+}
+
+// clientIP is the downstream peer address as seen by Envoy, x-forwarded-for can be set by the client itself so
+// an entry of it is only trusted when it was appended by one of the configured trusted proxies
+func clientIP(req *auth.CheckRequest) string {
+	source := req.Attributes.GetSource().GetAddress().GetSocketAddress().GetAddress()
+	hops := config.AppConfig.AppExtAuthzTrustedHops
+	if hops <= 0 {
+		return source
+	}
+
+	forwarded := strings.Split(req.Attributes.GetRequest().GetHttp().GetHeaders()["x-forwarded-for"], ",")
+	if forwarded[0] == "" || len(forwarded) < hops {
+		return source
+	}
+	return strings.TrimSpace(forwarded[len(forwarded)-hops])
+}
+
 func main() {
 	// create a TCP listener on port 4000
 	lis, err := net.Listen("tcp", config.AppConfig.AppHost+":"+config.AppConfig.AppExtAuthzPort)
@@ -69,22 +112,21 @@ func main() {
 	}
 	log.Info().Caller().Msgf("listening on %s", lis.Addr())
 
+	persistence.ConnectDB()
+	defer persistence.Pool.Close()
+
+	repository.CreateRepositories()
+	view.LoadNamespaces()
+
 	mailerClient := worker.CreateMailerClient()
-	worker.CreateMailer(mailerClient)
 	defer mailerClient.Close()
 
-	endpointDatas := util.ReadYAML("endpoints.yml")
-	var endpointYAML domain.EndpointYAML
-	err = yaml.Unmarshal(endpointDatas, &endpointYAML)
-	if err != nil {
-		log.Fatal().Caller().Err(err).Msg("Failed to unmarshal endpoint data")
-	}
+	inspector := worker.CreateInspector()
+	defer inspector.Close()
 
-	endpoints := make(map[string]domain.Endpoint)
-	for _, endpoint := range endpointYAML.Endpoints {
-		endpointData := domain.NewEndpoint(endpoint.Name, endpoint.Path, endpoint.Method)
-		endpoints[endpoint.Name] = endpointData
-	}
+	worker.CreateMailer(mailerClient, inspector)
+
+	endpoints := view.LoadEndpoints()
 
 	grpcServer := grpc.NewServer()
 	authServer := &AuthorizationServer{Endpoints: endpoints}
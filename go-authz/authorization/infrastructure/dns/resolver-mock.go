@@ -0,0 +1,27 @@
+package dns
+
+import (
+	"context"
+	"net"
+)
+
+// ResolverMock serves the TXT records set by the tests instead of querying the DNS.
+type ResolverMock struct {
+	Records map[string][]string
+}
+
+var _ ResolverInterface = &ResolverMock{}
+
+func CreateResolverMock() *ResolverMock {
+	resolver := &ResolverMock{Records: make(map[string][]string)}
+	Resolver = resolver
+	return resolver
+}
+
+func (rm *ResolverMock) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, ok := rm.Records[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
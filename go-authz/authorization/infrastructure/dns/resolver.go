@@ -0,0 +1,22 @@
+package dns
+
+import (
+	"context"
+	"net"
+)
+
+var (
+	Resolver ResolverInterface
+)
+
+// ResolverInterface looks up the DNS records the domain verification relies on,
+// *net.Resolver satisfies it.
+type ResolverInterface interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+var _ ResolverInterface = &net.Resolver{}
+
+func CreateResolver() {
+	Resolver = net.DefaultResolver
+}
@@ -0,0 +1,17 @@
+CREATE TABLE join_links (
+    id BYTEA PRIMARY KEY,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    team_id UUID NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    role_id BYTEA NOT NULL REFERENCES roles (id),
+    creator_id UUID NOT NULL REFERENCES users (id),
+    -- zero leaves the number of uses unlimited
+    max_uses INTEGER NOT NULL DEFAULT 0,
+    uses INTEGER NOT NULL DEFAULT 0,
+    email_domain VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX join_links_team_id_created_at_idx ON join_links (team_id, created_at);
@@ -0,0 +1,16 @@
+CREATE TABLE team_domains (
+    id BYTEA PRIMARY KEY,
+    team_id UUID NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    domain VARCHAR(255) NOT NULL,
+    role_id BYTEA NOT NULL REFERENCES roles (id),
+    join_mode VARCHAR(20) NOT NULL DEFAULT 'auto',
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMP,
+    creator_id UUID NOT NULL REFERENCES users (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- a team claims a domain once, several teams may claim the same domain
+CREATE UNIQUE INDEX team_domains_team_id_domain_idx ON team_domains (team_id, domain);
+CREATE INDEX team_domains_verified_domain_idx ON team_domains (domain) WHERE verified_at IS NOT NULL;
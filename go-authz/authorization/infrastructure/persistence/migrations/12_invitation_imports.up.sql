@@ -0,0 +1,12 @@
+CREATE TABLE invitation_imports (
+    id BYTEA PRIMARY KEY,
+    team_id UUID NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL REFERENCES users (id),
+    status VARCHAR(20) NOT NULL,
+    invitees JSONB NOT NULL,
+    results JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX invitation_imports_team_id_idx ON invitation_imports (team_id);
@@ -0,0 +1,6 @@
+CREATE TABLE team_invitation_settings (
+    team_id UUID PRIMARY KEY REFERENCES teams (id) ON DELETE CASCADE,
+    expires_in_hours INT NOT NULL,
+    reminder_offsets_hours INT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS join_requests;
+
+ALTER TABLE teams DROP COLUMN IF EXISTS is_discoverable;
@@ -0,0 +1,18 @@
+ALTER TABLE teams ADD COLUMN is_discoverable BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE join_requests (
+    id BYTEA PRIMARY KEY,
+    team_id UUID NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    message VARCHAR(500) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    role_id BYTEA REFERENCES roles (id),
+    reviewer_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- a user waits on one request per team at a time
+CREATE UNIQUE INDEX join_requests_pending_idx ON join_requests (team_id, user_id) WHERE status = 'pending';
+CREATE INDEX join_requests_team_id_created_at_idx ON join_requests (team_id, created_at, id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS memberships_expires_at_idx;
+
+ALTER TABLE invitations DROP COLUMN IF EXISTS membership_expires_at;
+ALTER TABLE memberships DROP COLUMN IF EXISTS expires_at;
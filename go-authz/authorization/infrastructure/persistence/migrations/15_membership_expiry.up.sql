@@ -0,0 +1,5 @@
+ALTER TABLE memberships ADD COLUMN expires_at TIMESTAMP;
+ALTER TABLE invitations ADD COLUMN membership_expires_at TIMESTAMP;
+
+-- the sweeper only looks at the time-bound memberships
+CREATE INDEX memberships_expires_at_idx ON memberships (expires_at) WHERE expires_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS elevation_events;
+DROP TABLE IF EXISTS elevation_requests;
@@ -0,0 +1,35 @@
+CREATE TABLE elevation_requests (
+    id BYTEA PRIMARY KEY,
+    team_id UUID NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    -- the membership is not a foreign key, the request outlives a member leaving the team
+    membership_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    from_role_id BYTEA NOT NULL REFERENCES roles (id),
+    to_role_id BYTEA NOT NULL REFERENCES roles (id),
+    duration_hours INT NOT NULL,
+    justification VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewer_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    auto_approved BOOLEAN NOT NULL DEFAULT FALSE,
+    activated_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    ended_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- a membership waits on or holds one elevation at a time
+CREATE UNIQUE INDEX elevation_requests_open_idx ON elevation_requests (membership_id) WHERE status IN ('pending', 'active');
+CREATE INDEX elevation_requests_team_id_created_at_idx ON elevation_requests (team_id, created_at, id);
+
+-- every step of a request, from the request itself to the role going back
+CREATE TABLE elevation_events (
+    id BYTEA PRIMARY KEY,
+    request_id BYTEA NOT NULL REFERENCES elevation_requests (id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    actor_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX elevation_events_request_id_idx ON elevation_events (request_id, created_at, id);
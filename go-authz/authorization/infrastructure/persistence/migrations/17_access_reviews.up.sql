@@ -0,0 +1,33 @@
+CREATE TABLE access_reviews (
+    id BYTEA PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    creator_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    roles VARCHAR(50)[] NOT NULL DEFAULT '{}',
+    deadline TIMESTAMP NOT NULL,
+    auto_revoke BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    reminders_sent INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX access_reviews_open_idx ON access_reviews (deadline) WHERE status = 'open';
+
+-- an item is a snapshot of a membership taken when the review starts, it outlives the membership it revokes
+CREATE TABLE access_review_items (
+    id BYTEA PRIMARY KEY,
+    review_id BYTEA NOT NULL REFERENCES access_reviews (id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    membership_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role_id BYTEA NOT NULL REFERENCES roles (id),
+    decision VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewer_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX access_review_items_membership_idx ON access_review_items (review_id, membership_id);
+CREATE INDEX access_review_items_team_id_idx ON access_review_items (team_id, review_id);
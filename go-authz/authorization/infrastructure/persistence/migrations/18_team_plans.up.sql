@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN plan VARCHAR(50);
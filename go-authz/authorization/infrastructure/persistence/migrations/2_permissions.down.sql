@@ -0,0 +1,13 @@
+ALTER TABLE roles ADD COLUMN endpoints JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+UPDATE roles r
+SET endpoints = COALESCE((
+    SELECT jsonb_agg(jsonb_build_object('Name', p.name, 'Path', '', 'Method', ''))
+    FROM role_permissions rp
+    JOIN permissions p ON p.id = rp.permission_id
+    WHERE rp.role_id = r.id
+), '[]'::jsonb);
+
+DROP INDEX memberships_team_id_user_id_idx;
+DROP TABLE role_permissions;
+DROP TABLE permissions;
@@ -0,0 +1,52 @@
+CREATE TABLE permissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE role_permissions (
+    role_id BYTEA NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id UUID NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE INDEX memberships_team_id_user_id_idx ON memberships (team_id, user_id);
+
+-- legacy endpoint names stored in roles.endpoints and the permission that replaces them
+CREATE TEMPORARY TABLE legacy_endpoints (
+    endpoint VARCHAR(100) NOT NULL,
+    permission VARCHAR(100) NOT NULL
+);
+
+INSERT INTO legacy_endpoints (endpoint, permission) VALUES
+    ('invite-member', 'member:invite'),
+    ('update-team', 'team:update'),
+    ('delete-member', 'member:delete'),
+    ('change-role-member', 'member:update-role'),
+    ('update-avatar-team', 'team:update-avatar'),
+    ('get-team', 'team:read'),
+    ('get-applications-team', 'application:list'),
+    ('create-application-team', 'application:create'),
+    ('get-application-team-detail', 'application:read'),
+    ('update-application-team', 'application:update');
+
+INSERT INTO permissions (name)
+SELECT DISTINCT permission FROM legacy_endpoints
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(r.endpoints) = 'array' THEN r.endpoints ELSE '[]'::jsonb END
+) e
+JOIN legacy_endpoints l ON l.endpoint = e->>'Name'
+JOIN permissions p ON p.name = l.permission
+ON CONFLICT DO NOTHING;
+
+DROP TABLE legacy_endpoints;
+
+ALTER TABLE roles DROP COLUMN endpoints;
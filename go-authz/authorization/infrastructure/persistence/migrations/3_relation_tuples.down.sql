@@ -0,0 +1 @@
+DROP TABLE relation_tuples;
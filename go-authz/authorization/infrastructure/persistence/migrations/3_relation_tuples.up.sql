@@ -0,0 +1,19 @@
+CREATE TABLE relation_tuples (
+    object_type VARCHAR(100) NOT NULL,
+    object_id VARCHAR(100) NOT NULL,
+    relation VARCHAR(100) NOT NULL,
+    subject_type VARCHAR(100) NOT NULL,
+    subject_id VARCHAR(100) NOT NULL,
+    subject_relation VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (object_type, object_id, relation, subject_type, subject_id, subject_relation)
+);
+
+CREATE INDEX relation_tuples_subject_idx ON relation_tuples (subject_type, subject_id, subject_relation);
+
+-- mirror existing memberships as team:<team_id>#<role>@user:<user_id>
+INSERT INTO relation_tuples (object_type, object_id, relation, subject_type, subject_id)
+SELECT 'team', m.team_id::text, r.name, 'user', m.user_id::text
+FROM memberships m
+JOIN roles r ON r.id = m.role_id
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,15 @@
+CREATE TABLE policies (
+    id UUID PRIMARY KEY,
+    team_id UUID NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    permission VARCHAR(100) NOT NULL,
+    expression TEXT NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    creator_id UUID NOT NULL REFERENCES users (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_id, name)
+);
+
+CREATE INDEX policies_team_id_permission_idx ON policies (team_id, permission);
@@ -0,0 +1,7 @@
+DELETE FROM relation_tuples WHERE object_type = 'organization' OR (object_type = 'team' AND relation = 'organization');
+
+DROP INDEX IF EXISTS teams_organization_id_idx;
+ALTER TABLE teams DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS organization_memberships;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,24 @@
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    creator_id UUID NOT NULL REFERENCES users (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE organization_memberships (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id),
+    role_id BYTEA NOT NULL REFERENCES roles (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, user_id)
+);
+
+CREATE INDEX organization_memberships_user_id_idx ON organization_memberships (user_id);
+
+ALTER TABLE teams ADD COLUMN organization_id UUID REFERENCES organizations (id) ON DELETE SET NULL;
+
+CREATE INDEX teams_organization_id_idx ON teams (organization_id);
@@ -0,0 +1,7 @@
+DELETE FROM relation_tuples WHERE object_type = 'team' AND (relation = 'parent' OR subject_type = 'team');
+
+DROP INDEX IF EXISTS teams_parent_id_idx;
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_inheritance_check;
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_parent_not_self;
+ALTER TABLE teams DROP COLUMN IF EXISTS inheritance;
+ALTER TABLE teams DROP COLUMN IF EXISTS parent_id;
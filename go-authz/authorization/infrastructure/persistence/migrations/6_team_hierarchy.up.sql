@@ -0,0 +1,7 @@
+ALTER TABLE teams ADD COLUMN parent_id UUID REFERENCES teams (id) ON DELETE SET NULL;
+ALTER TABLE teams ADD COLUMN inheritance VARCHAR(20) NOT NULL DEFAULT 'admins';
+
+ALTER TABLE teams ADD CONSTRAINT teams_parent_not_self CHECK (parent_id <> id);
+ALTER TABLE teams ADD CONSTRAINT teams_inheritance_check CHECK (inheritance IN ('none', 'admins', 'members'));
+
+CREATE INDEX teams_parent_id_idx ON teams (parent_id);
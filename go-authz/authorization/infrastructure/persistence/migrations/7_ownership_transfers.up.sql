@@ -0,0 +1,15 @@
+CREATE TABLE ownership_transfers (
+    id BYTEA PRIMARY KEY,
+    team_id UUID NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    from_user_id UUID NOT NULL REFERENCES users (id),
+    to_user_id UUID NOT NULL REFERENCES users (id),
+    keep_ownership BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- a team has at most one pending ownership transfer
+CREATE UNIQUE INDEX ownership_transfers_pending_team_idx ON ownership_transfers (team_id) WHERE status = 'pending';
+CREATE INDEX ownership_transfers_to_user_id_idx ON ownership_transfers (to_user_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS teams_purge_at_idx;
+ALTER TABLE teams DROP COLUMN IF EXISTS purge_at;
+ALTER TABLE teams DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,4 @@
+ALTER TABLE teams ADD COLUMN archived_at TIMESTAMP;
+ALTER TABLE teams ADD COLUMN purge_at TIMESTAMP;
+
+CREATE INDEX teams_purge_at_idx ON teams (purge_at) WHERE purge_at IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS invitations_email_created_at_id_idx;
+DROP INDEX IF EXISTS invitations_team_id_created_at_id_idx;
+DROP INDEX IF EXISTS memberships_user_id_last_active_at_idx;
+DROP INDEX IF EXISTS memberships_team_id_created_at_id_idx;
+DROP INDEX IF EXISTS users_created_at_id_idx;
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS users_created_at_id_idx ON users (created_at, id) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS memberships_team_id_created_at_id_idx ON memberships (team_id, created_at, id);
+CREATE INDEX IF NOT EXISTS memberships_user_id_last_active_at_idx ON memberships (user_id, last_active_at);
+CREATE INDEX IF NOT EXISTS invitations_team_id_created_at_id_idx ON invitations (team_id, created_at, id);
+CREATE INDEX IF NOT EXISTS invitations_email_created_at_id_idx ON invitations (email, created_at, id);
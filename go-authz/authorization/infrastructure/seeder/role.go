@@ -23,12 +23,13 @@ func (s Seed) AccessSeed() {
 	}()
 
 	roleRepo := repository.NewRoleRepository(s.pool)
+	permissionRepo := repository.NewPermissionRepository(s.pool)
 
-	endpointDatas := util.ReadYAML("endpoints.yml")
-	var endpointYAML domain.EndpointYAML
-	err = yaml.Unmarshal(endpointDatas, &endpointYAML)
+	permissionDatas := util.ReadYAML("permissions.yml")
+	var permissionYAML domain.PermissionYAML
+	err = yaml.Unmarshal(permissionDatas, &permissionYAML)
 	if err != nil {
-		log.Fatal().Caller().Err(err).Msg("Failed to unmarshal endpoint data")
+		log.Fatal().Caller().Err(err).Msg("Failed to unmarshal permission data")
 	}
 
 	roleDatas := util.ReadYAML("roles.yml")
@@ -38,21 +39,29 @@ func (s Seed) AccessSeed() {
 		log.Fatal().Caller().Err(err).Msg("Failed to unmarshal role data")
 	}
 
-	cachedEndpoint := make(map[string]domain.Endpoint)
+	cachedPermission := make(map[string]domain.Permission)
 
-	for _, endpoint := range endpointYAML.Endpoints {
-		endpointData := domain.NewEndpoint(endpoint.Name, endpoint.Path, endpoint.Method)
-		cachedEndpoint[endpoint.Name] = endpointData
+	for _, permission := range permissionYAML.Permissions {
+		permissionData := domain.NewPermission(permission.Name, permission.Description)
+		log.Info().Caller().Msg(fmt.Sprintf("=> inserting permission %s", permissionData.Name))
+		permissionErr := permissionRepo.Save(ctx, tx, permissionData)
+		if permissionErr != nil {
+			log.Error().Caller().Err(permissionErr).Msg("Failed to insert permission")
+		}
+		cachedPermission[permission.Name] = permissionData
 	}
 
 	for _, role := range roleYAML {
 		roleData := domain.NewRole(role.Name)
-		for _, endpoint := range role.Endpoints {
-			if val, ok := cachedEndpoint[endpoint.Name]; ok {
-				roleData.Endpoints.Add(val)
+		for _, permission := range role.Permissions {
+			val, ok := cachedPermission[permission.Name]
+			if !ok {
+				log.Warn().Caller().Msg(fmt.Sprintf("=> role %s references unknown permission %s, skipping", role.Name, permission.Name))
+				continue
 			}
+			roleData.Permissions.Add(val)
 		}
-		log.Info().Caller().Msg(fmt.Sprintf("=> inserting role %s with endpoints size %d", roleData.Name, len(roleData.Endpoints)))
+		log.Info().Caller().Msg(fmt.Sprintf("=> inserting role %s with permissions size %d", roleData.Name, len(roleData.Permissions)))
 		roleErr := roleRepo.Save(ctx, tx, roleData)
 		if roleErr != nil {
 			log.Error().Caller().Err(roleErr).Msg("Failed to insert role")
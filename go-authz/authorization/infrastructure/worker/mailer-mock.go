@@ -1,7 +1,18 @@
 package worker
 
+import "time"
+
+// ClientMock keeps the emails sent through the mock so tests can inspect them.
+// Scheduled holds the emails not cancelled yet by their task id.
 type ClientMock struct {
-	// pool *pgxpool.Pool
+	Sent      []*EmailPayload
+	Scheduled map[string]ScheduledEmail
+	Cancelled []string
+}
+
+type ScheduledEmail struct {
+	Payload *EmailPayload
+	At      time.Time
 }
 
 type AsynqClientMock struct {
@@ -16,15 +27,36 @@ func CreateMailerMock(client *ClientMock) {
 
 // Enqueue task to send email
 func (ac *AsynqClientMock) SendEmail(payload *EmailPayload) error {
+	ac.client.Sent = append(ac.client.Sent, payload)
 	return nil
 }
 
-func (ac *AsynqClientMock) CreateEmailPayload(templateName EmailTemplate, to, subject string, data map[string]interface{}) *EmailPayload {
+func (ac *AsynqClientMock) ScheduleEmail(payload *EmailPayload, at time.Time, taskID string) error {
+	if _, ok := ac.client.Scheduled[taskID]; !ok {
+		ac.client.Scheduled[taskID] = ScheduledEmail{Payload: payload, At: at}
+	}
+	return nil
+}
+
+func (ac *AsynqClientMock) CancelEmail(taskID string) error {
+	if _, ok := ac.client.Scheduled[taskID]; ok {
+		delete(ac.client.Scheduled, taskID)
+		ac.client.Cancelled = append(ac.client.Cancelled, taskID)
+	}
 	return nil
 }
 
+func (ac *AsynqClientMock) CreateEmailPayload(templateName EmailTemplate, to, subject string, data map[string]interface{}) *EmailPayload {
+	return &EmailPayload{
+		TemplateName: templateName,
+		To:           to,
+		Subject:      subject,
+		Data:         data,
+	}
+}
+
 func CreateMailerClientMock() *ClientMock {
 	// Create a new Asynq client.
-	client := &ClientMock{}
+	client := &ClientMock{Scheduled: make(map[string]ScheduledEmail)}
 	return client
 }
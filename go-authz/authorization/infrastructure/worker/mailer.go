@@ -2,6 +2,7 @@ package worker
 
 import (
 	"authorization/config"
+	"errors"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -15,17 +16,20 @@ var (
 
 type MailerInterface interface {
 	SendEmail(payload *EmailPayload) error
+	ScheduleEmail(payload *EmailPayload, at time.Time, taskID string) error
+	CancelEmail(taskID string) error
 	CreateEmailPayload(templateName EmailTemplate, to, subject string, data map[string]interface{}) *EmailPayload
 }
 
 type AsynqClient struct {
-	client *asynq.Client
+	client    *asynq.Client
+	inspector *asynq.Inspector
 }
 
 var _ MailerInterface = &AsynqClient{}
 
-func CreateMailer(client *asynq.Client) {
-	Mailer = &AsynqClient{client: client}
+func CreateMailer(client *asynq.Client, inspector *asynq.Inspector) {
+	Mailer = &AsynqClient{client: client, inspector: inspector}
 }
 
 // Enqueue task to send email
@@ -48,6 +52,30 @@ func (ac *AsynqClient) SendEmail(payload *EmailPayload) error {
 	return nil
 }
 
+// ScheduleEmail enqueues the email to be sent at the given time, the task id lets it be cancelled until then.
+// An email already scheduled with the same task id is kept.
+func (ac *AsynqClient) ScheduleEmail(payload *EmailPayload, at time.Time, taskID string) error {
+	if _, err := ac.client.Enqueue(
+		newEmailTask(payload),
+		asynq.Queue("critical"),
+		asynq.TaskID(taskID),
+		asynq.ProcessAt(at),
+	); err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		log.Error().Caller().Err(err).Msg("Failed to enqueue a task")
+		return err
+	}
+	return nil
+}
+
+func (ac *AsynqClient) CancelEmail(taskID string) error {
+	err := ac.inspector.DeleteTask("critical", taskID)
+	if err != nil && !errors.Is(err, asynq.ErrTaskNotFound) && !errors.Is(err, asynq.ErrQueueNotFound) {
+		log.Error().Caller().Err(err).Msg("Failed to cancel a task")
+		return err
+	}
+	return nil
+}
+
 func (ac *AsynqClient) CreateEmailPayload(templateName EmailTemplate, to, subject string, data map[string]interface{}) *EmailPayload {
 	return &EmailPayload{
 		TemplateName: templateName,
@@ -58,11 +86,13 @@ func (ac *AsynqClient) CreateEmailPayload(templateName EmailTemplate, to, subjec
 }
 
 func CreateMailerClient() *asynq.Client {
-	// Create a new Redis connection for the client.
-	redisConnection := asynq.RedisClientOpt{
-		Addr: config.StorageConfig.RedisHost + ":" + config.StorageConfig.RedisPort, // Redis server address
-	}
 	// Create a new Asynq client.
-	client := asynq.NewClient(redisConnection)
+	client := asynq.NewClient(redisConnection())
 	return client
 }
+
+func redisConnection() asynq.RedisClientOpt {
+	return asynq.RedisClientOpt{
+		Addr: config.StorageConfig.RedisHost + ":" + config.StorageConfig.RedisPort, // Redis server address
+	}
+}
@@ -19,8 +19,20 @@ const (
 	TypeDelayedEmail = "email:delayed"
 
 	// Email templates
-	InvitationTemplate EmailTemplate = "invitation-message.html"
-	WelcomingTemplate  EmailTemplate = "welcoming-message.html"
+	InvitationTemplate          EmailTemplate = "invitation-message.html"
+	WelcomingTemplate           EmailTemplate = "welcoming-message.html"
+	OwnershipTransferTemplate   EmailTemplate = "ownership-transfer-message.html"
+	MemberLeftTemplate          EmailTemplate = "member-left-message.html"
+	InvitationClaimedTemplate   EmailTemplate = "invitation-claimed-message.html"
+	InvitationReminderTemplate  EmailTemplate = "invitation-reminder-message.html"
+	JoinRequestTemplate         EmailTemplate = "join-request-message.html"
+	JoinRequestReviewedTemplate EmailTemplate = "join-request-reviewed-message.html"
+	MembershipExpiredTemplate   EmailTemplate = "membership-expired-message.html"
+	MembershipEndedTemplate     EmailTemplate = "membership-ended-message.html"
+	ElevationRequestTemplate    EmailTemplate = "elevation-request-message.html"
+	ElevationReviewedTemplate   EmailTemplate = "elevation-reviewed-message.html"
+	AccessReviewTemplate        EmailTemplate = "access-review-message.html"
+	AccessReviewClosedTemplate  EmailTemplate = "access-review-closed-message.html"
 )
 
 type EmailPayload struct {
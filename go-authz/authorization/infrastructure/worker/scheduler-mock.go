@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SchedulerMock keeps the scheduled tasks in memory so tests can inspect them.
+type SchedulerMock struct {
+	PurgeTeams        map[uuid.UUID]time.Time
+	InvitationImports []ulid.ULID
+	ElevationReverts  map[ulid.ULID]time.Time
+}
+
+var _ SchedulerInterface = &SchedulerMock{}
+
+func CreateSchedulerMock() *SchedulerMock {
+	scheduler := &SchedulerMock{
+		PurgeTeams:       make(map[uuid.UUID]time.Time),
+		ElevationReverts: make(map[ulid.ULID]time.Time),
+	}
+	Scheduler = scheduler
+	return scheduler
+}
+
+func (sm *SchedulerMock) SchedulePurgeTeam(teamID uuid.UUID, at time.Time) error {
+	sm.PurgeTeams[teamID] = at
+	return nil
+}
+
+func (sm *SchedulerMock) CancelPurgeTeam(teamID uuid.UUID) error {
+	delete(sm.PurgeTeams, teamID)
+	return nil
+}
+
+func (sm *SchedulerMock) EnqueueInvitationImport(importID ulid.ULID) error {
+	sm.InvitationImports = append(sm.InvitationImports, importID)
+	return nil
+}
+
+func (sm *SchedulerMock) ScheduleElevationRevert(requestID ulid.ULID, at time.Time) error {
+	sm.ElevationReverts[requestID] = at
+	return nil
+}
+
+func (sm *SchedulerMock) CancelElevationRevert(requestID ulid.ULID) error {
+	delete(sm.ElevationReverts, requestID)
+	return nil
+}
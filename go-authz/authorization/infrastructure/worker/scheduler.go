@@ -0,0 +1,215 @@
+package worker
+
+import (
+	"authorization/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// TypePurgeTeam is a name of the task type
+	// for deleting an archived team once its grace period is over.
+	TypePurgeTeam = "team:purge"
+
+	// TypeInvitationImport is a name of the task type
+	// for processing a bulk invitation too large to be processed within the request.
+	TypeInvitationImport = "invitation:import"
+
+	// TypeExpireInvitations is a name of the task type
+	// for expiring the invitations past their expiry, it is enqueued periodically.
+	TypeExpireInvitations = "invitation:expire"
+
+	// TypeExpireMemberships is a name of the task type
+	// for removing the memberships past their expiry, it is enqueued periodically.
+	TypeExpireMemberships = "membership:expire"
+
+	// TypeSweepAccessReviews is a name of the task type
+	// for reminding the reviewers of the open access reviews and closing the ones past their deadline,
+	// it is enqueued periodically.
+	TypeSweepAccessReviews = "access-review:sweep"
+
+	// TypeRevertElevation is a name of the task type
+	// for giving a member its role back once its elevation is over.
+	TypeRevertElevation = "elevation:revert"
+
+	// QueueAuthorization is the queue of the tasks processed by the authorization service itself,
+	// it is kept apart from the mailer queues so the mailer never picks them up.
+	QueueAuthorization = "authorization"
+)
+
+var (
+	Scheduler SchedulerInterface
+)
+
+type SchedulerInterface interface {
+	SchedulePurgeTeam(teamID uuid.UUID, at time.Time) error
+	CancelPurgeTeam(teamID uuid.UUID) error
+	EnqueueInvitationImport(importID ulid.ULID) error
+	ScheduleElevationRevert(requestID ulid.ULID, at time.Time) error
+	CancelElevationRevert(requestID ulid.ULID) error
+}
+
+type PurgeTeamPayload struct {
+	TeamID uuid.UUID
+}
+
+type InvitationImportPayload struct {
+	ImportID ulid.ULID
+}
+
+type ElevationRevertPayload struct {
+	RequestID ulid.ULID
+}
+
+type AsynqScheduler struct {
+	client    *asynq.Client
+	inspector *asynq.Inspector
+}
+
+var _ SchedulerInterface = &AsynqScheduler{}
+
+func CreateScheduler(client *asynq.Client, inspector *asynq.Inspector) {
+	Scheduler = &AsynqScheduler{client: client, inspector: inspector}
+}
+
+// SchedulePurgeTeam enqueues the deletion of the team at the given time,
+// the task id is derived from the team so that it can be cancelled when the team is restored.
+// A purge left over from an earlier archival of the team is replaced, otherwise its task id would conflict.
+func (as *AsynqScheduler) SchedulePurgeTeam(teamID uuid.UUID, at time.Time) error {
+	b, err := json.Marshal(PurgeTeamPayload{TeamID: teamID})
+	if err != nil {
+		return err
+	}
+
+	if err := as.CancelPurgeTeam(teamID); err != nil {
+		return err
+	}
+
+	if _, err := as.client.Enqueue(
+		asynq.NewTask(TypePurgeTeam, b),
+		asynq.Queue(QueueAuthorization),
+		asynq.TaskID(purgeTeamTaskID(teamID)),
+		asynq.ProcessAt(at),
+	); err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to enqueue a task")
+		return err
+	}
+	return nil
+}
+
+func (as *AsynqScheduler) CancelPurgeTeam(teamID uuid.UUID) error {
+	err := as.inspector.DeleteTask(QueueAuthorization, purgeTeamTaskID(teamID))
+	if err != nil && !errors.Is(err, asynq.ErrTaskNotFound) && !errors.Is(err, asynq.ErrQueueNotFound) {
+		log.Error().Caller().Err(err).Msg("Failed to cancel a task")
+		return err
+	}
+	return nil
+}
+
+// EnqueueInvitationImport enqueues the processing of the bulk invitation,
+// the task id is derived from the import so that it is never processed twice.
+func (as *AsynqScheduler) EnqueueInvitationImport(importID ulid.ULID) error {
+	b, err := json.Marshal(InvitationImportPayload{ImportID: importID})
+	if err != nil {
+		return err
+	}
+
+	if _, err := as.client.Enqueue(
+		asynq.NewTask(TypeInvitationImport, b),
+		asynq.Queue(QueueAuthorization),
+		asynq.TaskID(TypeInvitationImport+":"+importID.String()),
+	); err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to enqueue a task")
+		return err
+	}
+	return nil
+}
+
+// ScheduleElevationRevert enqueues the end of the elevation at the given time,
+// the task id is derived from the request so that it can be cancelled when the elevation is ended earlier.
+func (as *AsynqScheduler) ScheduleElevationRevert(requestID ulid.ULID, at time.Time) error {
+	b, err := json.Marshal(ElevationRevertPayload{RequestID: requestID})
+	if err != nil {
+		return err
+	}
+
+	if _, err := as.client.Enqueue(
+		asynq.NewTask(TypeRevertElevation, b),
+		asynq.Queue(QueueAuthorization),
+		asynq.TaskID(elevationRevertTaskID(requestID)),
+		asynq.ProcessAt(at),
+	); err != nil {
+		log.Error().Caller().Err(err).Msg("Failed to enqueue a task")
+		return err
+	}
+	return nil
+}
+
+func (as *AsynqScheduler) CancelElevationRevert(requestID ulid.ULID) error {
+	err := as.inspector.DeleteTask(QueueAuthorization, elevationRevertTaskID(requestID))
+	if err != nil && !errors.Is(err, asynq.ErrTaskNotFound) && !errors.Is(err, asynq.ErrQueueNotFound) {
+		log.Error().Caller().Err(err).Msg("Failed to cancel a task")
+		return err
+	}
+	return nil
+}
+
+func elevationRevertTaskID(requestID ulid.ULID) string {
+	return TypeRevertElevation + ":" + requestID.String()
+}
+
+func purgeTeamTaskID(teamID uuid.UUID) string {
+	return TypePurgeTeam + ":" + teamID.String()
+}
+
+func CreateInspector() *asynq.Inspector {
+	return asynq.NewInspector(redisConnection())
+}
+
+// CreatePeriodicScheduler creates the scheduler enqueuing the periodic tasks of the authorization queue.
+func CreatePeriodicScheduler() (*asynq.Scheduler, error) {
+	scheduler := asynq.NewScheduler(redisConnection(), nil)
+
+	// the sweeps are idempotent, a run missed while the service was down is caught up by the next one
+	_, err := scheduler.Register(
+		fmt.Sprintf("@every %s", config.AppConfig.InvitationExpirySweepInterval),
+		asynq.NewTask(TypeExpireInvitations, nil),
+		asynq.Queue(QueueAuthorization),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = scheduler.Register(
+		fmt.Sprintf("@every %s", config.AppConfig.MembershipExpirySweepInterval),
+		asynq.NewTask(TypeExpireMemberships, nil),
+		asynq.Queue(QueueAuthorization),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = scheduler.Register(
+		fmt.Sprintf("@every %s", config.AppConfig.AccessReviewSweepInterval),
+		asynq.NewTask(TypeSweepAccessReviews, nil),
+		asynq.Queue(QueueAuthorization),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler, nil
+}
+
+// CreateTaskServer creates the server processing the tasks of the authorization queue.
+func CreateTaskServer() *asynq.Server {
+	return asynq.NewServer(redisConnection(), asynq.Config{
+		Queues: map[string]int{QueueAuthorization: 1},
+	})
+}
@@ -3,10 +3,14 @@ package main
 import (
 	"authorization/config"
 	"authorization/controller"
+	"authorization/domain"
+	"authorization/infrastructure/dns"
 	"authorization/infrastructure/persistence"
 	"authorization/infrastructure/seeder"
 	"authorization/infrastructure/worker"
 	"authorization/repository"
+	"authorization/service/tasks"
+	"authorization/view"
 	"flag"
 	"os"
 
@@ -56,11 +60,38 @@ func main() {
 	defer persistence.Pool.Close()
 
 	mailerClient := worker.CreateMailerClient()
-	worker.CreateMailer(mailerClient)
 	defer mailerClient.Close()
 
+	inspector := worker.CreateInspector()
+	defer inspector.Close()
+
+	worker.CreateMailer(mailerClient, inspector)
+	worker.CreateScheduler(mailerClient, inspector)
+
+	dns.CreateResolver()
+
 	repository.CreateRepositories()
+	view.LoadNamespaces()
+	domain.LoadRoleHierarchy()
+	domain.LoadPlans()
+	view.LoadEndpoints()
 	handleArgs(persistence.Pool)
+
+	taskServer := worker.CreateTaskServer()
+	if err := taskServer.Start(tasks.NewServeMux()); err != nil {
+		log.Fatal().Caller().Err(err).Msg("Cannot start the task server")
+	}
+	defer taskServer.Shutdown()
+
+	periodicScheduler, err := worker.CreatePeriodicScheduler()
+	if err != nil {
+		log.Fatal().Caller().Err(err).Msg("Cannot register the periodic tasks")
+	}
+	if err := periodicScheduler.Start(); err != nil {
+		log.Fatal().Caller().Err(err).Msg("Cannot start the periodic task scheduler")
+	}
+	defer periodicScheduler.Shutdown()
+
 	controller.CreateRouter()
 }
 
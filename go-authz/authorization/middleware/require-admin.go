@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"authorization/config"
+	"authorization/controller/exception"
+	"authorization/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin only lets through the operators listed in APP_ADMIN_EMAILS, it must run after DeserializeUser.
+func RequireAdmin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user := ctx.MustGet("currentUser").(domain.User)
+
+		if config.AppConfig.IsAdmin(user.Email) {
+			ctx.Next()
+			return
+		}
+
+		_ = ctx.Error(exception.NewForbiddenException("only administrators can access this resource"))
+		ctx.Abort()
+	}
+}
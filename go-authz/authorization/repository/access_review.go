@@ -0,0 +1,343 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type accessReviewRepository struct {
+	pool *pgxpool.Pool
+}
+
+type AccessReviewRepository interface {
+	Add(context.Context, domain.AccessReview, pgx.Tx) (domain.AccessReview, error)
+	Update(context.Context, domain.AccessReview, pgx.Tx) error
+	UpdateItem(context.Context, domain.AccessReviewItem, pgx.Tx) error
+	Lock(context.Context, ulid.ULID, pgx.Tx) error
+	CountPendingItems(context.Context, ulid.ULID, pgx.Tx) (int, error)
+	Get(context.Context, ulid.ULID) (domain.AccessReview, error)
+	List(context.Context, domain.AccessReviewOptions) ([]domain.AccessReview, error)
+	Count(context.Context, domain.AccessReviewOptions) (int64, error)
+	ListOpen(context.Context) ([]domain.AccessReview, error)
+}
+
+// accessReviewRepository implements the AccessReviewRepository interface
+func NewAccessReviewRepository(pool *pgxpool.Pool) AccessReviewRepository {
+	return &accessReviewRepository{pool: pool}
+}
+
+const accessReviewColumns = `
+	ar.id, ar.name, ar.creator_id, ar.roles, ar.deadline, ar.auto_revoke, ar.status, ar.reminders_sent,
+	ar.completed_at, ar.created_at, ar.updated_at,
+	COALESCE(u.first_name, ''), COALESCE(u.last_name, ''), COALESCE(u.email, ''), COALESCE(u.username, ''), COALESCE(u.avatar_url, ''),
+	(SELECT COUNT(*) FROM access_review_items i WHERE i.review_id = ar.id),
+	(SELECT COUNT(*) FROM access_review_items i WHERE i.review_id = ar.id AND i.decision = 'pending')
+`
+
+const accessReviewJoins = `
+	FROM access_reviews ar
+	LEFT JOIN users u ON u.id = ar.creator_id
+`
+
+func scanAccessReview(row pgx.Row) (domain.AccessReview, error) {
+	var review domain.AccessReview
+	var roles []string
+	err := row.Scan(
+		&review.ID,
+		&review.Name,
+		&review.CreatorID,
+		&roles,
+		&review.Deadline,
+		&review.AutoRevoke,
+		&review.Status,
+		&review.RemindersSent,
+		&review.CompletedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+		&review.Creator.FirstName,
+		&review.Creator.LastName,
+		&review.Creator.Email,
+		&review.Creator.Username,
+		&review.Creator.AvatarURL,
+		&review.TotalItems,
+		&review.PendingItems,
+	)
+	for _, role := range roles {
+		review.Roles = append(review.Roles, domain.RoleType(role))
+	}
+	review.Creator.ID = review.CreatorID.UUID
+	return review, err
+}
+
+// Add stores the review along with its items.
+func (repo *accessReviewRepository) Add(ctx context.Context, review domain.AccessReview, tx pgx.Tx) (domain.AccessReview, error) {
+	query := `
+		INSERT INTO access_reviews (id, name, creator_id, roles, deadline, auto_revoke, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		review.ID,
+		review.Name,
+		review.CreatorID,
+		review.Roles.Names(),
+		review.Deadline,
+		review.AutoRevoke,
+		review.Status,
+		review.CreatedAt,
+		review.UpdatedAt,
+	)
+	if err != nil {
+		return domain.AccessReview{}, err
+	}
+
+	query = `
+		INSERT INTO access_review_items (id, review_id, team_id, membership_id, user_id, role_id, decision, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	for _, item := range review.Items {
+		_, err := tx.Exec(ctx, query, item.ID, item.ReviewID, item.TeamID, item.MembershipID, item.UserID, item.RoleID, item.Decision, item.CreatedAt)
+		if err != nil {
+			return domain.AccessReview{}, err
+		}
+	}
+
+	return review, nil
+}
+
+func (repo *accessReviewRepository) Update(ctx context.Context, review domain.AccessReview, tx pgx.Tx) error {
+	query := `
+		UPDATE access_reviews
+		SET status = $2, reminders_sent = $3, completed_at = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, review.ID, review.Status, review.RemindersSent, review.CompletedAt, review.UpdatedAt)
+	return err
+}
+
+// UpdateItem records the decision on an item still pending, an item decided by a concurrent transaction is a conflict.
+func (repo *accessReviewRepository) UpdateItem(ctx context.Context, item domain.AccessReviewItem, tx pgx.Tx) error {
+	query := `
+		UPDATE access_review_items
+		SET decision = $2, reviewer_id = $3, note = $4, reviewed_at = $5
+		WHERE id = $1 AND decision = $6
+	`
+
+	tag, err := tx.Exec(ctx, query, item.ID, item.Decision, item.ReviewerID, item.Note, item.ReviewedAt, domain.AccessReviewPending)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return exception.NewConflictException(fmt.Sprintf("item with ID %s is already reviewed", item.ID))
+	}
+
+	return nil
+}
+
+// Lock locks the review until the transaction ends, so its items are decided and the review completed one at a time.
+func (repo *accessReviewRepository) Lock(ctx context.Context, id ulid.ULID, tx pgx.Tx) error {
+	var locked ulid.ULID
+	err := tx.QueryRow(ctx, `SELECT id FROM access_reviews WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return exception.NewNotFoundException("access review not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CountPendingItems counts the items of the review left to decide as seen by the transaction.
+func (repo *accessReviewRepository) CountPendingItems(ctx context.Context, id ulid.ULID, tx pgx.Tx) (int, error) {
+	var count int
+	err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM access_review_items WHERE review_id = $1 AND decision = $2`, id, domain.AccessReviewPending).Scan(&count)
+	return count, err
+}
+
+// Get returns the review along with its items ordered by team and user.
+func (repo *accessReviewRepository) Get(ctx context.Context, id ulid.ULID) (domain.AccessReview, error) {
+	query := "SELECT " + accessReviewColumns + accessReviewJoins + " WHERE ar.id = $1"
+
+	review, err := scanAccessReview(repo.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.AccessReview{}, exception.NewNotFoundException("access review not found")
+		}
+		return domain.AccessReview{}, err
+	}
+
+	query = `
+		SELECT i.id, i.review_id, i.team_id, t.name, i.membership_id, i.user_id, i.role_id, r.name, i.decision,
+			i.reviewer_id, i.note, i.reviewed_at, i.created_at,
+			u.first_name, u.last_name, u.email, u.username, u.avatar_url,
+			COALESCE(rv.first_name, ''), COALESCE(rv.last_name, ''), COALESCE(rv.email, ''), COALESCE(rv.username, ''), COALESCE(rv.avatar_url, '')
+		FROM access_review_items i
+		JOIN teams t ON t.id = i.team_id
+		JOIN users u ON u.id = i.user_id
+		JOIN roles r ON r.id = i.role_id
+		LEFT JOIN users rv ON rv.id = i.reviewer_id
+		WHERE i.review_id = $1
+		ORDER BY t.name, i.team_id, u.email
+	`
+
+	rows, err := repo.pool.Query(ctx, query, id)
+	if err != nil {
+		return domain.AccessReview{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.AccessReviewItem
+		err := rows.Scan(
+			&item.ID,
+			&item.ReviewID,
+			&item.TeamID,
+			&item.Team.Name,
+			&item.MembershipID,
+			&item.UserID,
+			&item.RoleID,
+			&item.Role.Name,
+			&item.Decision,
+			&item.ReviewerID,
+			&item.Note,
+			&item.ReviewedAt,
+			&item.CreatedAt,
+			&item.User.FirstName,
+			&item.User.LastName,
+			&item.User.Email,
+			&item.User.Username,
+			&item.User.AvatarURL,
+			&item.Reviewer.FirstName,
+			&item.Reviewer.LastName,
+			&item.Reviewer.Email,
+			&item.Reviewer.Username,
+			&item.Reviewer.AvatarURL,
+		)
+		if err != nil {
+			return domain.AccessReview{}, err
+		}
+
+		item.Team.ID = item.TeamID
+		item.User.ID = item.UserID
+		item.Role.ID = item.RoleID
+		item.Reviewer.ID = item.ReviewerID.UUID
+		review.Items = append(review.Items, item)
+	}
+
+	return review, rows.Err()
+}
+
+// List returns the reviews matching the options without their items.
+func (repo *accessReviewRepository) List(ctx context.Context, opts domain.AccessReviewOptions) ([]domain.AccessReview, error) {
+	query := "SELECT " + accessReviewColumns + accessReviewJoins
+
+	conditions, args := accessReviewConditions(opts)
+
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	order := keyset{
+		SortKey:  "ar.created_at",
+		SortType: "timestamp",
+		IDColumn: "ar.id",
+		ParseID: func(id string) (any, error) {
+			return ulid.Parse(id)
+		},
+	}
+
+	query, args, err := order.apply(query, conditions, args, page)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []domain.AccessReview
+	for rows.Next() {
+		review, err := scanAccessReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	reverse(reviews, page)
+	return reviews, rows.Err()
+}
+
+// ListOpen returns the open reviews without their items, the closest to their deadline first.
+func (repo *accessReviewRepository) ListOpen(ctx context.Context) ([]domain.AccessReview, error) {
+	query := "SELECT " + accessReviewColumns + accessReviewJoins + " WHERE ar.status = $1 ORDER BY ar.deadline, ar.id"
+
+	rows, err := repo.pool.Query(ctx, query, domain.AccessReviewOpen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []domain.AccessReview
+	for rows.Next() {
+		review, err := scanAccessReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+func (repo *accessReviewRepository) Count(ctx context.Context, opts domain.AccessReviewOptions) (int64, error) {
+	query := `
+		SELECT COUNT(ar.id)
+		FROM access_reviews ar
+	`
+
+	conditions, args := accessReviewConditions(opts)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var count int64
+	err := repo.pool.QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func accessReviewConditions(opts domain.AccessReviewOptions) ([]string, []any) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if opts.TeamID != uuid.Nil {
+		addCondition("EXISTS (SELECT 1 FROM access_review_items i WHERE i.review_id = ar.id AND i.team_id = $%d)", opts.TeamID)
+	}
+	if len(opts.Statuses) > 0 {
+		addCondition("ar.status = ANY($%d)", opts.Statuses)
+	}
+
+	return conditions, args
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type elevationRepository struct {
+	pool *pgxpool.Pool
+}
+
+type ElevationRepository interface {
+	Add(context.Context, domain.ElevationRequest, pgx.Tx) (domain.ElevationRequest, error)
+	Update(context.Context, domain.ElevationRequest, pgx.Tx) error
+	AddEvent(context.Context, domain.ElevationEvent, pgx.Tx) error
+	Get(context.Context, ulid.ULID) (domain.ElevationRequest, error)
+	List(context.Context, domain.ElevationOptions) ([]domain.ElevationRequest, error)
+	Count(context.Context, domain.ElevationOptions) (int64, error)
+}
+
+// elevationRepository implements the ElevationRepository interface
+func NewElevationRepository(pool *pgxpool.Pool) ElevationRepository {
+	return &elevationRepository{pool: pool}
+}
+
+const elevationColumns = `
+	er.id, er.team_id, t.name, er.membership_id, er.user_id, er.from_role_id, fr.name, er.to_role_id, tr.name,
+	er.duration_hours, er.justification, er.status, er.reviewer_id, er.auto_approved,
+	er.activated_at, er.ends_at, er.ended_at, er.created_at, er.updated_at,
+	u.first_name, u.last_name, u.email, u.username, u.avatar_url
+`
+
+const elevationJoins = `
+	FROM elevation_requests er
+	JOIN teams t ON t.id = er.team_id
+	JOIN users u ON u.id = er.user_id
+	JOIN roles fr ON fr.id = er.from_role_id
+	JOIN roles tr ON tr.id = er.to_role_id
+`
+
+func scanElevation(row pgx.Row) (domain.ElevationRequest, error) {
+	var request domain.ElevationRequest
+	var hours int
+	err := row.Scan(
+		&request.ID,
+		&request.TeamID,
+		&request.Team.Name,
+		&request.MembershipID,
+		&request.UserID,
+		&request.FromRoleID,
+		&request.FromRole.Name,
+		&request.ToRoleID,
+		&request.ToRole.Name,
+		&hours,
+		&request.Justification,
+		&request.Status,
+		&request.ReviewerID,
+		&request.AutoApproved,
+		&request.ActivatedAt,
+		&request.EndsAt,
+		&request.EndedAt,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+		&request.User.FirstName,
+		&request.User.LastName,
+		&request.User.Email,
+		&request.User.Username,
+		&request.User.AvatarURL,
+	)
+	request.Duration = time.Duration(hours) * time.Hour
+	request.Team.ID = request.TeamID
+	request.User.ID = request.UserID
+	request.FromRole.ID = request.FromRoleID
+	request.ToRole.ID = request.ToRoleID
+	return request, err
+}
+
+// Add stores the request along with the events recorded so far, an auto-approved request is stored active.
+func (repo *elevationRepository) Add(ctx context.Context, request domain.ElevationRequest, tx pgx.Tx) (domain.ElevationRequest, error) {
+	query := `
+		INSERT INTO elevation_requests (id, team_id, membership_id, user_id, from_role_id, to_role_id, duration_hours,
+			justification, status, reviewer_id, auto_approved, activated_at, ends_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		request.ID,
+		request.TeamID,
+		request.MembershipID,
+		request.UserID,
+		request.FromRoleID,
+		request.ToRoleID,
+		int(request.Duration.Hours()),
+		request.Justification,
+		request.Status,
+		request.ReviewerID,
+		request.AutoApproved,
+		request.ActivatedAt,
+		request.EndsAt,
+		request.CreatedAt,
+		request.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ElevationRequest{}, err
+	}
+
+	for _, event := range request.Events {
+		if err := repo.AddEvent(ctx, event, tx); err != nil {
+			return domain.ElevationRequest{}, err
+		}
+	}
+
+	return request, nil
+}
+
+func (repo *elevationRepository) Update(ctx context.Context, request domain.ElevationRequest, tx pgx.Tx) error {
+	query := `
+		UPDATE elevation_requests
+		SET from_role_id = $2, status = $3, reviewer_id = $4, auto_approved = $5, activated_at = $6, ends_at = $7,
+			ended_at = $8, updated_at = $9
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		request.ID,
+		request.FromRoleID,
+		request.Status,
+		request.ReviewerID,
+		request.AutoApproved,
+		request.ActivatedAt,
+		request.EndsAt,
+		request.EndedAt,
+		request.UpdatedAt,
+	)
+	return err
+}
+
+func (repo *elevationRepository) AddEvent(ctx context.Context, event domain.ElevationEvent, tx pgx.Tx) error {
+	query := `
+		INSERT INTO elevation_events (id, request_id, action, actor_id, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := tx.Exec(ctx, query, event.ID, event.RequestID, event.Action, event.ActorID, event.Note, event.CreatedAt)
+	return err
+}
+
+// Get returns the request along with its events in the order they were recorded.
+func (repo *elevationRepository) Get(ctx context.Context, id ulid.ULID) (domain.ElevationRequest, error) {
+	query := "SELECT " + elevationColumns + elevationJoins + " WHERE er.id = $1"
+
+	request, err := scanElevation(repo.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ElevationRequest{}, exception.NewNotFoundException("elevation request not found")
+		}
+		return domain.ElevationRequest{}, err
+	}
+
+	query = `
+		SELECT e.id, e.request_id, e.action, e.actor_id, e.note, e.created_at,
+			COALESCE(u.first_name, ''), COALESCE(u.last_name, ''), COALESCE(u.email, ''), COALESCE(u.username, ''), COALESCE(u.avatar_url, '')
+		FROM elevation_events e
+		LEFT JOIN users u ON u.id = e.actor_id
+		WHERE e.request_id = $1
+		ORDER BY e.created_at, e.id
+	`
+
+	rows, err := repo.pool.Query(ctx, query, id)
+	if err != nil {
+		return domain.ElevationRequest{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.ElevationEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.RequestID,
+			&event.Action,
+			&event.ActorID,
+			&event.Note,
+			&event.CreatedAt,
+			&event.Actor.FirstName,
+			&event.Actor.LastName,
+			&event.Actor.Email,
+			&event.Actor.Username,
+			&event.Actor.AvatarURL,
+		)
+		if err != nil {
+			return domain.ElevationRequest{}, err
+		}
+
+		event.Actor.ID = event.ActorID.UUID
+		request.Events = append(request.Events, event)
+	}
+
+	return request, rows.Err()
+}
+
+// List returns the requests matching the options without their events.
+func (repo *elevationRepository) List(ctx context.Context, opts domain.ElevationOptions) ([]domain.ElevationRequest, error) {
+	query := "SELECT " + elevationColumns + elevationJoins
+
+	conditions, args := elevationConditions(opts)
+
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	order := keyset{
+		SortKey:  "er.created_at",
+		SortType: "timestamp",
+		IDColumn: "er.id",
+		ParseID: func(id string) (any, error) {
+			return ulid.Parse(id)
+		},
+	}
+
+	query, args, err := order.apply(query, conditions, args, page)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []domain.ElevationRequest
+	for rows.Next() {
+		request, err := scanElevation(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	reverse(requests, page)
+	return requests, rows.Err()
+}
+
+func (repo *elevationRepository) Count(ctx context.Context, opts domain.ElevationOptions) (int64, error) {
+	query := `
+		SELECT COUNT(er.id)
+		FROM elevation_requests er
+	`
+
+	conditions, args := elevationConditions(opts)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var count int64
+	err := repo.pool.QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func elevationConditions(opts domain.ElevationOptions) ([]string, []any) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if opts.TeamID != uuid.Nil {
+		addCondition("er.team_id = $%d", opts.TeamID)
+	}
+	if opts.MembershipID != uuid.Nil {
+		addCondition("er.membership_id = $%d", opts.MembershipID)
+	}
+	if len(opts.Statuses) > 0 {
+		addCondition("er.status = ANY($%d)", opts.Statuses)
+	}
+
+	return conditions, args
+}
@@ -3,11 +3,16 @@ package repository
 import (
 	"authorization/controller/exception"
 	"authorization/domain"
+	"authorization/util"
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
 )
 
 type invitationRepository struct {
@@ -20,7 +25,9 @@ type InvitationRepository interface {
 	Update(context.Context, domain.Invitation, pgx.Tx) error
 	Get(context.Context, ulid.ULID) (domain.Invitation, error)
 	List(context.Context, domain.InvitationOptions) ([]domain.Invitation, error)
+	Count(context.Context, domain.InvitationOptions) (int64, error)
 	Delete(context.Context, ulid.ULID, pgx.Tx) error
+	ExpireStale(context.Context, time.Time, pgx.Tx) (int64, error)
 }
 
 // invitationRepository implements the InvitationRepository interface
@@ -30,14 +37,14 @@ func NewInvitationRepository(pool *pgxpool.Pool) InvitationRepository {
 
 func (repo *invitationRepository) Add(ctx context.Context, invitation domain.Invitation, tx pgx.Tx) (domain.Invitation, error) {
 	query := `
-		INSERT INTO invitations (id, email, expires_at, status, team_id, role_id, sender_id, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO invitations (id, email, expires_at, status, team_id, role_id, sender_id, is_active, created_at, updated_at, membership_expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id
 	`
 	_, err := tx.Exec(ctx, query,
 		invitation.ID, invitation.Email, invitation.ExpiresAt, invitation.Status,
 		invitation.TeamID, invitation.RoleID, invitation.SenderID, invitation.IsActive,
-		invitation.CreatedAt, invitation.UpdatedAt,
+		invitation.CreatedAt, invitation.UpdatedAt, invitation.MembershipExpiresAt,
 	)
 	if err != nil {
 		return domain.Invitation{}, err
@@ -79,13 +86,14 @@ func (repo *invitationRepository) AddBatch(ctx context.Context, invitations []do
 func (repo *invitationRepository) Update(ctx context.Context, invitation domain.Invitation, tx pgx.Tx) error {
 	query := `
 		UPDATE invitations
-		SET email = $1, expires_at = $2, status = $3, team_id = $4, role_id = $5, sender_id = $6, is_active = $7
-		WHERE id = $8
+		SET email = $1, expires_at = $2, status = $3, team_id = $4, role_id = $5, sender_id = $6, is_active = $7, updated_at = $8,
+			membership_expires_at = $10
+		WHERE id = $9
 	`
 	_, err := tx.Exec(ctx, query,
 		invitation.Email, invitation.ExpiresAt, invitation.Status,
 		invitation.TeamID, invitation.RoleID, invitation.SenderID, invitation.IsActive,
-		invitation.ID,
+		util.GetTimestampUTC(), invitation.ID, invitation.MembershipExpiresAt,
 	)
 	if err != nil {
 		return err
@@ -97,13 +105,15 @@ func (repo *invitationRepository) Update(ctx context.Context, invitation domain.
 func (repo *invitationRepository) Get(ctx context.Context, id ulid.ULID) (domain.Invitation, error) {
 	var invitation domain.Invitation
 	query := `
-		SELECT i.id, i.email, i.expires_at, i.status, i.team_id, i.role_id, i.sender_id, i.is_active
+		SELECT i.id, i.email, i.expires_at, i.status, i.team_id, i.role_id, i.sender_id, i.is_active, i.created_at, i.updated_at,
+			i.membership_expires_at
 		FROM invitations i
 		WHERE i.id = $1
 	`
 	err := repo.pool.QueryRow(ctx, query, id).
 		Scan(&invitation.ID, &invitation.Email, &invitation.ExpiresAt, &invitation.Status,
 			&invitation.TeamID, &invitation.RoleID, &invitation.SenderID, &invitation.IsActive,
+			&invitation.CreatedAt, &invitation.UpdatedAt, &invitation.MembershipExpiresAt,
 		)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -114,13 +124,35 @@ func (repo *invitationRepository) Get(ctx context.Context, id ulid.ULID) (domain
 	return invitation, nil
 }
 
+// List returns the invitations matching the options along with the name of their role, team and sender.
 func (repo *invitationRepository) List(ctx context.Context, opts domain.InvitationOptions) ([]domain.Invitation, error) {
 	query := `
-		SELECT id, email, expires_at, status, team_id, role_id, sender_id, is_active
-		FROM invitations
-		WHERE status = ANY($1) AND email = $2 AND team_id = $3 AND role_id = $4	
+		SELECT i.id, i.email, i.expires_at, i.status, i.team_id, i.role_id, i.sender_id, i.is_active, i.created_at, i.updated_at,
+			i.membership_expires_at, r.name, t.name, s.first_name, s.last_name
+		FROM invitations i
+		JOIN roles r ON r.id = i.role_id
+		JOIN teams t ON t.id = i.team_id
+		JOIN users s ON s.id = i.sender_id
 	`
-	rows, err := repo.pool.Query(ctx, query, opts.Statuses, opts.Email, opts.TeamID, opts.RoleID)
+
+	conditions, args := invitationConditions(opts)
+
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	order := keyset{
+		SortKey:  "i.created_at",
+		SortType: "timestamp",
+		IDColumn: "i.id",
+		ParseID: func(id string) (any, error) {
+			return ulid.Parse(id)
+		},
+	}
+
+	query, args, err := order.apply(query, conditions, args, page)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -130,13 +162,77 @@ func (repo *invitationRepository) List(ctx context.Context, opts domain.Invitati
 	for rows.Next() {
 		var invitation domain.Invitation
 		err := rows.Scan(&invitation.ID, &invitation.Email, &invitation.ExpiresAt, &invitation.Status,
-			&invitation.TeamID, &invitation.RoleID, &invitation.SenderID, &invitation.IsActive)
+			&invitation.TeamID, &invitation.RoleID, &invitation.SenderID, &invitation.IsActive,
+			&invitation.CreatedAt, &invitation.UpdatedAt, &invitation.MembershipExpiresAt,
+			&invitation.Role.Name, &invitation.Team.Name, &invitation.Sender.FirstName, &invitation.Sender.LastName)
 		if err != nil {
 			return nil, err
 		}
+		invitation.Role.ID = invitation.RoleID
+		invitation.Team.ID = invitation.TeamID
+		invitation.Sender.ID = invitation.SenderID
 		invitations = append(invitations, invitation)
 	}
-	return invitations, nil
+
+	reverse(invitations, page)
+	return invitations, rows.Err()
+}
+
+func (repo *invitationRepository) Count(ctx context.Context, opts domain.InvitationOptions) (int64, error) {
+	query := `
+		SELECT COUNT(i.id)
+		FROM invitations i
+	`
+
+	conditions, args := invitationConditions(opts)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var count int64
+	err := repo.pool.QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func invitationConditions(opts domain.InvitationOptions) ([]string, []any) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if len(opts.IDs) > 0 {
+		ids := make([][]byte, len(opts.IDs))
+		for i, id := range opts.IDs {
+			ids[i] = id.Bytes()
+		}
+		addCondition("i.id = ANY($%d::bytea[])", ids)
+	}
+	if len(opts.Statuses) > 0 {
+		addCondition("i.status = ANY($%d)", opts.Statuses)
+	}
+	if opts.Email != "" {
+		addCondition("LOWER(i.email) = LOWER($%d)", opts.Email)
+	}
+	if opts.Query != "" {
+		addCondition(`i.email ILIKE $%d ESCAPE '\'`, "%"+escapeLike(opts.Query)+"%")
+	}
+	if opts.TeamID != uuid.Nil {
+		addCondition("i.team_id = $%d", opts.TeamID)
+	}
+	if opts.RoleID != (ulid.ULID{}) {
+		addCondition("i.role_id = $%d", opts.RoleID)
+	}
+	if !opts.ExpiresAfter.IsZero() {
+		addCondition("i.expires_at > $%d", opts.ExpiresAfter)
+	}
+
+	return conditions, args
 }
 
 func (repo *invitationRepository) Delete(ctx context.Context, id ulid.ULID, tx pgx.Tx) error {
@@ -150,3 +246,18 @@ func (repo *invitationRepository) Delete(ctx context.Context, id ulid.ULID, tx p
 	}
 	return nil
 }
+
+// ExpireStale expires the open invitations past their expiry at the given time and returns how many were expired.
+// It is the set based form of the open to expired transition of domain.Invitation.
+func (repo *invitationRepository) ExpireStale(ctx context.Context, now time.Time, tx pgx.Tx) (int64, error) {
+	query := `
+		UPDATE invitations
+		SET status = $1, is_active = FALSE, updated_at = $2
+		WHERE status = ANY($3) AND expires_at <= $2
+	`
+	tag, err := tx.Exec(ctx, query, domain.InvitationStatusExpired, now, domain.InvitationStatusesOpen)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+)
+
+type invitationImportRepository struct {
+	pool *pgxpool.Pool
+}
+
+type InvitationImportRepository interface {
+	Add(context.Context, domain.InvitationImport, pgx.Tx) (domain.InvitationImport, error)
+	Update(context.Context, domain.InvitationImport, pgx.Tx) error
+	Get(context.Context, ulid.ULID) (domain.InvitationImport, error)
+}
+
+// invitationImportRepository implements the InvitationImportRepository interface
+func NewInvitationImportRepository(pool *pgxpool.Pool) InvitationImportRepository {
+	return &invitationImportRepository{pool: pool}
+}
+
+func (repo *invitationImportRepository) Add(ctx context.Context, invitationImport domain.InvitationImport, tx pgx.Tx) (domain.InvitationImport, error) {
+	query := `
+		INSERT INTO invitation_imports (id, team_id, sender_id, status, invitees, results, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		invitationImport.ID,
+		invitationImport.TeamID,
+		invitationImport.SenderID,
+		invitationImport.Status,
+		invitationImport.Invitees,
+		invitationImport.Results,
+		invitationImport.CreatedAt,
+		invitationImport.UpdatedAt,
+	)
+	if err != nil {
+		return domain.InvitationImport{}, err
+	}
+
+	return invitationImport, nil
+}
+
+func (repo *invitationImportRepository) Update(ctx context.Context, invitationImport domain.InvitationImport, tx pgx.Tx) error {
+	query := `
+		UPDATE invitation_imports
+		SET status = $2, results = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, invitationImport.ID, invitationImport.Status, invitationImport.Results, invitationImport.UpdatedAt)
+	return err
+}
+
+func (repo *invitationImportRepository) Get(ctx context.Context, id ulid.ULID) (domain.InvitationImport, error) {
+	query := `
+		SELECT id, team_id, sender_id, status, invitees, results, created_at, updated_at
+		FROM invitation_imports
+		WHERE id = $1
+	`
+
+	var invitationImport domain.InvitationImport
+	err := repo.pool.QueryRow(ctx, query, id).Scan(
+		&invitationImport.ID,
+		&invitationImport.TeamID,
+		&invitationImport.SenderID,
+		&invitationImport.Status,
+		&invitationImport.Invitees,
+		&invitationImport.Results,
+		&invitationImport.CreatedAt,
+		&invitationImport.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.InvitationImport{}, exception.NewNotFoundException("invitation import not found")
+		}
+		return domain.InvitationImport{}, err
+	}
+
+	return invitationImport, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"authorization/domain"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	uuid "github.com/satori/go.uuid"
+)
+
+type invitationSettingsRepository struct {
+	pool *pgxpool.Pool
+}
+
+type InvitationSettingsRepository interface {
+	Get(context.Context, uuid.UUID) (domain.InvitationSettings, error)
+	Save(context.Context, domain.InvitationSettings, pgx.Tx) error
+}
+
+// invitationSettingsRepository implements the InvitationSettingsRepository interface
+func NewInvitationSettingsRepository(pool *pgxpool.Pool) InvitationSettingsRepository {
+	return &invitationSettingsRepository{pool: pool}
+}
+
+// Get returns the invitation settings of the team, the default ones when the team has none of its own.
+func (repo *invitationSettingsRepository) Get(ctx context.Context, teamID uuid.UUID) (domain.InvitationSettings, error) {
+	query := `
+		SELECT expires_in_hours, reminder_offsets_hours, updated_at
+		FROM team_invitation_settings
+		WHERE team_id = $1
+	`
+
+	var (
+		expiresInHours       int
+		reminderOffsetsHours []int
+		updatedAt            time.Time
+	)
+	err := repo.pool.QueryRow(ctx, query, teamID).Scan(&expiresInHours, &reminderOffsetsHours, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.DefaultInvitationSettings(teamID), nil
+	} else if err != nil {
+		return domain.InvitationSettings{}, err
+	}
+
+	settings := domain.InvitationSettings{
+		TeamID:          teamID,
+		ExpiresIn:       time.Duration(expiresInHours) * time.Hour,
+		ReminderOffsets: make([]time.Duration, 0, len(reminderOffsetsHours)),
+		UpdatedAt:       updatedAt,
+	}
+	for _, hours := range reminderOffsetsHours {
+		settings.ReminderOffsets = append(settings.ReminderOffsets, time.Duration(hours)*time.Hour)
+	}
+	return settings, nil
+}
+
+func (repo *invitationSettingsRepository) Save(ctx context.Context, settings domain.InvitationSettings, tx pgx.Tx) error {
+	query := `
+		INSERT INTO team_invitation_settings (team_id, expires_in_hours, reminder_offsets_hours, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_id) DO UPDATE
+		SET expires_in_hours = EXCLUDED.expires_in_hours,
+			reminder_offsets_hours = EXCLUDED.reminder_offsets_hours,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	offsets := make([]int, 0, len(settings.ReminderOffsets))
+	for _, offset := range settings.ReminderOffsets {
+		offsets = append(offsets, int(offset.Hours()))
+	}
+
+	_, err := tx.Exec(ctx, query, settings.TeamID, int(settings.ExpiresIn.Hours()), offsets, settings.UpdatedAt)
+	return err
+}
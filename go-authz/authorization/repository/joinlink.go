@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type joinLinkRepository struct {
+	pool *pgxpool.Pool
+}
+
+type JoinLinkRepository interface {
+	Add(context.Context, domain.JoinLink, pgx.Tx) (domain.JoinLink, error)
+	Revoke(context.Context, domain.JoinLink, pgx.Tx) error
+	Use(context.Context, ulid.ULID, pgx.Tx) error
+	Get(context.Context, ulid.ULID) (domain.JoinLink, error)
+	GetByToken(context.Context, string) (domain.JoinLink, error)
+	ListByTeam(context.Context, uuid.UUID) ([]domain.JoinLink, error)
+}
+
+// joinLinkRepository implements the JoinLinkRepository interface
+func NewJoinLinkRepository(pool *pgxpool.Pool) JoinLinkRepository {
+	return &joinLinkRepository{pool: pool}
+}
+
+const joinLinkColumns = `
+	jl.id, jl.token, jl.team_id, t.name, jl.role_id, r.name, jl.creator_id, jl.max_uses, jl.uses, jl.email_domain,
+	jl.expires_at, jl.revoked_at, jl.created_at, jl.updated_at,
+	u.first_name, u.last_name, u.email, u.username, u.avatar_url
+`
+
+const joinLinkJoins = `
+	FROM join_links jl
+	JOIN teams t ON t.id = jl.team_id
+	JOIN roles r ON r.id = jl.role_id
+	JOIN users u ON u.id = jl.creator_id
+`
+
+func scanJoinLink(row pgx.Row) (domain.JoinLink, error) {
+	var link domain.JoinLink
+	err := row.Scan(
+		&link.ID,
+		&link.Token,
+		&link.TeamID,
+		&link.Team.Name,
+		&link.RoleID,
+		&link.Role.Name,
+		&link.CreatorID,
+		&link.MaxUses,
+		&link.Uses,
+		&link.EmailDomain,
+		&link.ExpiresAt,
+		&link.RevokedAt,
+		&link.CreatedAt,
+		&link.UpdatedAt,
+		&link.Creator.FirstName,
+		&link.Creator.LastName,
+		&link.Creator.Email,
+		&link.Creator.Username,
+		&link.Creator.AvatarURL,
+	)
+	link.Team.ID = link.TeamID
+	link.Role.ID = link.RoleID
+	link.Creator.ID = link.CreatorID
+	return link, err
+}
+
+func (repo *joinLinkRepository) Add(ctx context.Context, link domain.JoinLink, tx pgx.Tx) (domain.JoinLink, error) {
+	query := `
+		INSERT INTO join_links (id, token, team_id, role_id, creator_id, max_uses, uses, email_domain, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		link.ID,
+		link.Token,
+		link.TeamID,
+		link.RoleID,
+		link.CreatorID,
+		link.MaxUses,
+		link.Uses,
+		link.EmailDomain,
+		link.ExpiresAt,
+		link.CreatedAt,
+		link.UpdatedAt,
+	)
+	if err != nil {
+		return domain.JoinLink{}, err
+	}
+
+	return link, nil
+}
+
+func (repo *joinLinkRepository) Revoke(ctx context.Context, link domain.JoinLink, tx pgx.Tx) error {
+	query := `
+		UPDATE join_links
+		SET revoked_at = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, link.ID, link.RevokedAt, link.UpdatedAt)
+	return err
+}
+
+// Use counts a use of the link unless it was revoked, expired or used up in the meantime,
+// the check and the count are a single statement so concurrent redemptions can't exceed the maximum.
+func (repo *joinLinkRepository) Use(ctx context.Context, id ulid.ULID, tx pgx.Tx) error {
+	query := `
+		UPDATE join_links
+		SET uses = uses + 1, updated_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW() AND (max_uses = 0 OR uses < max_uses)
+	`
+
+	tag, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return exception.NewBadRequestException("the join link can't be used anymore")
+	}
+
+	return nil
+}
+
+func (repo *joinLinkRepository) Get(ctx context.Context, id ulid.ULID) (domain.JoinLink, error) {
+	query := `SELECT ` + joinLinkColumns + joinLinkJoins + ` WHERE jl.id = $1`
+
+	link, err := scanJoinLink(repo.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.JoinLink{}, exception.NewNotFoundException("join link not found")
+		}
+		return domain.JoinLink{}, err
+	}
+
+	return link, nil
+}
+
+func (repo *joinLinkRepository) GetByToken(ctx context.Context, token string) (domain.JoinLink, error) {
+	query := `SELECT ` + joinLinkColumns + joinLinkJoins + ` WHERE jl.token = $1`
+
+	link, err := scanJoinLink(repo.pool.QueryRow(ctx, query, token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.JoinLink{}, exception.NewNotFoundException("join link not found")
+		}
+		return domain.JoinLink{}, err
+	}
+
+	return link, nil
+}
+
+// ListByTeam returns the links of the team, the newest first, including the revoked and expired ones.
+func (repo *joinLinkRepository) ListByTeam(ctx context.Context, teamID uuid.UUID) ([]domain.JoinLink, error) {
+	query := `SELECT ` + joinLinkColumns + joinLinkJoins + ` WHERE jl.team_id = $1 ORDER BY jl.created_at DESC`
+
+	rows, err := repo.pool.Query(ctx, query, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []domain.JoinLink
+	for rows.Next() {
+		link, err := scanJoinLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
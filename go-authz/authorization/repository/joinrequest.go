@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type joinRequestRepository struct {
+	pool *pgxpool.Pool
+}
+
+type JoinRequestRepository interface {
+	Add(context.Context, domain.JoinRequest, pgx.Tx) (domain.JoinRequest, error)
+	Update(context.Context, domain.JoinRequest, pgx.Tx) error
+	Get(context.Context, ulid.ULID) (domain.JoinRequest, error)
+	List(context.Context, domain.JoinRequestOptions) ([]domain.JoinRequest, error)
+	Count(context.Context, domain.JoinRequestOptions) (int64, error)
+}
+
+// joinRequestRepository implements the JoinRequestRepository interface
+func NewJoinRequestRepository(pool *pgxpool.Pool) JoinRequestRepository {
+	return &joinRequestRepository{pool: pool}
+}
+
+const joinRequestColumns = `
+	jr.id, jr.team_id, t.name, jr.user_id, jr.message, jr.status, jr.role_id, COALESCE(r.name, ''),
+	jr.reviewer_id, jr.reviewed_at, jr.created_at, jr.updated_at,
+	u.first_name, u.last_name, u.email, u.username, u.avatar_url
+`
+
+const joinRequestJoins = `
+	FROM join_requests jr
+	JOIN teams t ON t.id = jr.team_id
+	JOIN users u ON u.id = jr.user_id
+	LEFT JOIN roles r ON r.id = jr.role_id
+`
+
+func scanJoinRequest(row pgx.Row) (domain.JoinRequest, error) {
+	var request domain.JoinRequest
+	err := row.Scan(
+		&request.ID,
+		&request.TeamID,
+		&request.Team.Name,
+		&request.UserID,
+		&request.Message,
+		&request.Status,
+		&request.RoleID,
+		&request.Role.Name,
+		&request.ReviewerID,
+		&request.ReviewedAt,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+		&request.User.FirstName,
+		&request.User.LastName,
+		&request.User.Email,
+		&request.User.Username,
+		&request.User.AvatarURL,
+	)
+	request.Team.ID = request.TeamID
+	request.User.ID = request.UserID
+	if request.RoleID != nil {
+		request.Role.ID = *request.RoleID
+	}
+	return request, err
+}
+
+func (repo *joinRequestRepository) Add(ctx context.Context, request domain.JoinRequest, tx pgx.Tx) (domain.JoinRequest, error) {
+	query := `
+		INSERT INTO join_requests (id, team_id, user_id, message, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		request.ID,
+		request.TeamID,
+		request.UserID,
+		request.Message,
+		request.Status,
+		request.CreatedAt,
+		request.UpdatedAt,
+	)
+	if err != nil {
+		return domain.JoinRequest{}, err
+	}
+
+	return request, nil
+}
+
+func (repo *joinRequestRepository) Update(ctx context.Context, request domain.JoinRequest, tx pgx.Tx) error {
+	query := `
+		UPDATE join_requests
+		SET status = $2, role_id = $3, reviewer_id = $4, reviewed_at = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, request.ID, request.Status, request.RoleID, request.ReviewerID, request.ReviewedAt, request.UpdatedAt)
+	return err
+}
+
+func (repo *joinRequestRepository) Get(ctx context.Context, id ulid.ULID) (domain.JoinRequest, error) {
+	query := "SELECT " + joinRequestColumns + joinRequestJoins + " WHERE jr.id = $1"
+
+	request, err := scanJoinRequest(repo.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.JoinRequest{}, exception.NewNotFoundException("join request not found")
+		}
+		return domain.JoinRequest{}, err
+	}
+
+	return request, nil
+}
+
+// List returns the join requests matching the options along with the name of their team and role and their user.
+func (repo *joinRequestRepository) List(ctx context.Context, opts domain.JoinRequestOptions) ([]domain.JoinRequest, error) {
+	query := "SELECT " + joinRequestColumns + joinRequestJoins
+
+	conditions, args := joinRequestConditions(opts)
+
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	order := keyset{
+		SortKey:  "jr.created_at",
+		SortType: "timestamp",
+		IDColumn: "jr.id",
+		ParseID: func(id string) (any, error) {
+			return ulid.Parse(id)
+		},
+	}
+
+	query, args, err := order.apply(query, conditions, args, page)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []domain.JoinRequest
+	for rows.Next() {
+		request, err := scanJoinRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	reverse(requests, page)
+	return requests, rows.Err()
+}
+
+func (repo *joinRequestRepository) Count(ctx context.Context, opts domain.JoinRequestOptions) (int64, error) {
+	query := `
+		SELECT COUNT(jr.id)
+		FROM join_requests jr
+	`
+
+	conditions, args := joinRequestConditions(opts)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var count int64
+	err := repo.pool.QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func joinRequestConditions(opts domain.JoinRequestOptions) ([]string, []any) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if opts.TeamID != uuid.Nil {
+		addCondition("jr.team_id = $%d", opts.TeamID)
+	}
+	if opts.UserID != uuid.Nil {
+		addCondition("jr.user_id = $%d", opts.UserID)
+	}
+	if len(opts.Statuses) > 0 {
+		addCondition("jr.status = ANY($%d)", opts.Statuses)
+	}
+
+	return conditions, args
+}
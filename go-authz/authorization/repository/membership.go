@@ -3,7 +3,10 @@ package repository
 import (
 	"authorization/controller/exception"
 	"authorization/domain"
+	"authorization/util"
 	"fmt"
+	"strings"
+	"time"
 
 	"context"
 	"errors"
@@ -22,9 +25,17 @@ type MembershipRepository interface {
 	AddBatch(context.Context, []domain.Membership) error
 	Update(context.Context, domain.Membership, pgx.Tx) (domain.Membership, error)
 	Get(context.Context, uuid.UUID) (domain.Membership, error)
+	GetByUser(context.Context, uuid.UUID, uuid.UUID) (domain.Membership, error)
+	CountByRole(context.Context, uuid.UUID, domain.RoleType, pgx.Tx) (int64, error)
+	ListByRoles(context.Context, uuid.UUID, domain.RoleTypes) ([]domain.Membership, error)
+	ResetLastActiveTeam(context.Context, uuid.UUID, uuid.UUID, pgx.Tx) error
 	List(context.Context, domain.MembershipOptions) ([]domain.Membership, error)
 	Delete(context.Context, uuid.UUID, pgx.Tx) error
 	Count(context.Context, domain.MembershipOptions) (int64, error)
+	CountByRoles(context.Context, uuid.UUID) (map[domain.RoleType]int64, error)
+	ListPreviews(context.Context, []uuid.UUID, int) (map[uuid.UUID][]domain.Membership, error)
+	CountByTeams(context.Context, []uuid.UUID) (map[uuid.UUID]int64, error)
+	ListExpired(context.Context, time.Time, int) ([]domain.Membership, error)
 }
 
 // membershipRepository implements the MembershipRepository interface
@@ -32,14 +43,21 @@ func NewMembershipRepository(pool *pgxpool.Pool) MembershipRepository {
 	return &membershipRepository{pool: pool}
 }
 
+// Add inserts the membership, a membership of the user in the team past its expiry that the sweep has not removed yet
+// is replaced so the sweep cannot take the mirrored tuples of the new one along with it.
 func (repo *membershipRepository) Add(ctx context.Context, membership domain.Membership, tx pgx.Tx) (domain.Membership, error) {
+	err := deleteExpiredMembership(ctx, tx, membership.TeamID, membership.UserID)
+	if err != nil {
+		return domain.Membership{}, err
+	}
+
 	query := `
-		INSERT INTO memberships (id, team_id, user_id, role_id, last_active_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO memberships (id, team_id, user_id, role_id, last_active_at, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id
 	`
 
-	err := tx.QueryRow(
+	err = tx.QueryRow(
 		ctx,
 		query,
 		membership.ID,
@@ -47,6 +65,7 @@ func (repo *membershipRepository) Add(ctx context.Context, membership domain.Mem
 		membership.UserID,
 		membership.RoleID,
 		membership.LastActiveAt,
+		membership.ExpiresAt,
 		membership.CreatedAt,
 		membership.UpdatedAt,
 	).Scan(&membership.ID)
@@ -55,13 +74,18 @@ func (repo *membershipRepository) Add(ctx context.Context, membership domain.Mem
 		return domain.Membership{}, err
 	}
 
+	err = mirrorMembership(ctx, tx, membership.TeamID, membership.UserID, membership.RoleID)
+	if err != nil {
+		return domain.Membership{}, err
+	}
+
 	return membership, nil
 }
 
 func (repo *membershipRepository) AddBatch(ctx context.Context, memberships []domain.Membership) error {
 	query := `
-		INSERT INTO memberships (id, team_id, user_id, role_id, last_active_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO memberships (id, team_id, user_id, role_id, last_active_at, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (id) DO NOTHING
 	`
 
@@ -80,11 +104,16 @@ func (repo *membershipRepository) AddBatch(ctx context.Context, memberships []do
 			membership.UserID,
 			membership.RoleID,
 			membership.LastActiveAt,
+			membership.ExpiresAt,
 			membership.CreatedAt,
 			membership.UpdatedAt,
 		); err != nil {
 			return err
 		}
+
+		if err := mirrorMembership(ctx, tx, membership.TeamID, membership.UserID, membership.RoleID); err != nil {
+			return err
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -97,7 +126,7 @@ func (repo *membershipRepository) AddBatch(ctx context.Context, memberships []do
 func (repo *membershipRepository) Update(ctx context.Context, membership domain.Membership, tx pgx.Tx) (domain.Membership, error) {
 	query := `
 		UPDATE memberships
-		SET team_id = $2, user_id = $3, role_id = $4, last_active_at = $5, updated_at = $6
+		SET team_id = $2, user_id = $3, role_id = $4, last_active_at = $5, updated_at = $6, expires_at = $7
 		WHERE id = $1
 	`
 
@@ -110,20 +139,27 @@ func (repo *membershipRepository) Update(ctx context.Context, membership domain.
 		membership.RoleID,
 		membership.LastActiveAt,
 		membership.UpdatedAt,
+		membership.ExpiresAt,
 	)
 
 	if err != nil {
 		return domain.Membership{}, err
 	}
 
+	err = mirrorMembership(ctx, tx, membership.TeamID, membership.UserID, membership.RoleID)
+	if err != nil {
+		return domain.Membership{}, err
+	}
+
 	return membership, nil
 }
 
 func (repo *membershipRepository) Get(ctx context.Context, id uuid.UUID) (domain.Membership, error) {
 	query := `
-		SELECT id, team_id, user_id, role_id, last_active_at, created_at, updated_at
-		FROM memberships
-		WHERE id = $1
+		SELECT m.id, m.team_id, m.user_id, m.role_id, r.name, m.last_active_at, m.expires_at, m.created_at, m.updated_at
+		FROM memberships m
+		JOIN roles r ON r.id = m.role_id
+		WHERE m.id = $1 AND (m.expires_at IS NULL OR m.expires_at > $2)
 	`
 
 	var membership domain.Membership
@@ -132,12 +168,15 @@ func (repo *membershipRepository) Get(ctx context.Context, id uuid.UUID) (domain
 		ctx,
 		query,
 		id,
+		util.GetTimestampUTC(),
 	).Scan(
 		&membership.ID,
 		&membership.TeamID,
 		&membership.UserID,
 		&membership.RoleID,
+		&membership.Role.Name,
 		&membership.LastActiveAt,
+		&membership.ExpiresAt,
 		&membership.CreatedAt,
 		&membership.UpdatedAt,
 	)
@@ -149,44 +188,175 @@ func (repo *membershipRepository) Get(ctx context.Context, id uuid.UUID) (domain
 		return domain.Membership{}, err
 	}
 
+	membership.Role.ID = membership.RoleID
 	return membership, nil
 }
 
-func (repo *membershipRepository) List(ctx context.Context, opts domain.MembershipOptions) ([]domain.Membership, error) {
+func (repo *membershipRepository) GetByUser(ctx context.Context, teamID, userID uuid.UUID) (domain.Membership, error) {
 	query := `
-		SELECT id, team_id, user_id, role_id, last_active_at, created_at, updated_at
-		FROM memberships
-		WHERE
+		SELECT m.id, m.team_id, m.user_id, m.role_id, r.name, m.last_active_at, m.expires_at, m.created_at, m.updated_at
+		FROM memberships m
+		JOIN roles r ON r.id = m.role_id
+		WHERE m.team_id = $1 AND m.user_id = $2 AND (m.expires_at IS NULL OR m.expires_at > $3)
 	`
 
-	argsNumber := 1
-	args := make([]interface{}, 0)
+	var membership domain.Membership
 
-	if opts.TeamID != uuid.Nil {
-		query += fmt.Sprintf("team_id = $%d", argsNumber)
-		argsNumber++
-		args = append(args, opts.TeamID)
+	err := repo.pool.QueryRow(
+		ctx,
+		query,
+		teamID,
+		userID,
+		util.GetTimestampUTC(),
+	).Scan(
+		&membership.ID,
+		&membership.TeamID,
+		&membership.UserID,
+		&membership.RoleID,
+		&membership.Role.Name,
+		&membership.LastActiveAt,
+		&membership.ExpiresAt,
+		&membership.CreatedAt,
+		&membership.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Membership{}, exception.NewNotFoundException("membership not found")
+		}
+		return domain.Membership{}, err
 	}
 
-	if opts.UserID != uuid.Nil {
-		query += fmt.Sprintf("user_id = $%d", argsNumber)
-		argsNumber++
-		args = append(args, opts.UserID)
+	membership.Role.ID = membership.RoleID
+	return membership, nil
+}
+
+// CountByRole counts the members holding the role in the team, it runs in tx to see the pending changes.
+func (repo *membershipRepository) CountByRole(ctx context.Context, teamID uuid.UUID, role domain.RoleType, tx pgx.Tx) (int64, error) {
+	query := `
+		SELECT COUNT(m.id)
+		FROM memberships m
+		JOIN roles r ON r.id = m.role_id
+		WHERE m.team_id = $1 AND r.name = $2 AND (m.expires_at IS NULL OR m.expires_at > $3)
+	`
+
+	var count int64
+
+	err := tx.QueryRow(ctx, query, teamID, role, util.GetTimestampUTC()).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ListByRoles returns the active members of the team holding one of the roles, along with their user.
+func (repo *membershipRepository) ListByRoles(ctx context.Context, teamID uuid.UUID, roles domain.RoleTypes) ([]domain.Membership, error) {
+	query := `
+		SELECT m.id, m.team_id, m.user_id, m.role_id, r.name, m.last_active_at, m.created_at, m.updated_at,
+			u.first_name, u.last_name, u.email, u.username, u.avatar_url
+		FROM memberships m
+		JOIN roles r ON r.id = m.role_id
+		JOIN users u ON u.id = m.user_id
+		WHERE m.team_id = $1 AND r.name::text = ANY($2::text[]) AND u.is_active = true
+			AND (m.expires_at IS NULL OR m.expires_at > $3)
+		ORDER BY array_position($2::text[], r.name::text), u.email
+	`
+
+	rows, err := repo.pool.Query(ctx, query, teamID, roles.Names(), util.GetTimestampUTC())
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
 	var memberships []domain.Membership
+	for rows.Next() {
+		var membership domain.Membership
+		err := rows.Scan(
+			&membership.ID,
+			&membership.TeamID,
+			&membership.UserID,
+			&membership.RoleID,
+			&membership.Role.Name,
+			&membership.LastActiveAt,
+			&membership.CreatedAt,
+			&membership.UpdatedAt,
+			&membership.User.FirstName,
+			&membership.User.LastName,
+			&membership.User.Email,
+			&membership.User.Username,
+			&membership.User.AvatarURL,
+		)
+		if err != nil {
+			return nil, err
+		}
 
-	rows, err := repo.pool.Query(
-		ctx,
-		query,
-		args...,
-	)
+		membership.Role.ID = membership.RoleID
+		membership.User.ID = membership.UserID
+		memberships = append(memberships, membership)
+	}
+
+	return memberships, rows.Err()
+}
+
+// ResetLastActiveTeam makes the personal team the last active team of the user when the team was,
+// it must run before the membership of the user in the team is removed.
+func (repo *membershipRepository) ResetLastActiveTeam(ctx context.Context, userID, teamID uuid.UUID, tx pgx.Tx) error {
+	query := `
+		UPDATE memberships m
+		SET last_active_at = $3, updated_at = $3
+		FROM teams t
+		WHERE t.id = m.team_id AND t.is_personal AND m.user_id = $1
+			AND $2 = (
+				SELECT l.team_id
+				FROM memberships l
+				WHERE l.user_id = $1
+				ORDER BY l.last_active_at DESC NULLS LAST
+				LIMIT 1
+			)
+	`
+
+	_, err := tx.Exec(ctx, query, userID, teamID, util.GetTimestampUTC())
+	return err
+}
+
+func (repo *membershipRepository) List(ctx context.Context, opts domain.MembershipOptions) ([]domain.Membership, error) {
+	query := `
+		SELECT m.id, m.team_id, m.user_id, m.role_id, m.last_active_at, m.expires_at, m.created_at, m.updated_at,
+			r.name, u.first_name, u.last_name, u.email, u.username, u.avatar_url,
+			t.name, t.description, t.is_personal, t.avatar_url, t.creator_id
+		FROM memberships m
+		JOIN roles r ON r.id = m.role_id
+		JOIN users u ON u.id = m.user_id
+		JOIN teams t ON t.id = m.team_id
+	`
+
+	conditions, args := membershipConditions(opts)
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	order := keyset{
+		SortKey:  membershipSortKey(opts.SortBy, &args),
+		SortType: membershipSortType(opts.SortBy),
+		IDColumn: "m.id",
+		IDType:   "uuid",
+	}
+
+	query, args, err := order.apply(query, conditions, args, page)
 	if err != nil {
 		return nil, err
 	}
 
+	rows, err := repo.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []domain.Membership
 	for rows.Next() {
 		var membership domain.Membership
+		var role domain.Role
+		var user domain.User
+		var team domain.Team
 
 		err := rows.Scan(
 			&membership.ID,
@@ -194,59 +364,133 @@ func (repo *membershipRepository) List(ctx context.Context, opts domain.Membersh
 			&membership.UserID,
 			&membership.RoleID,
 			&membership.LastActiveAt,
+			&membership.ExpiresAt,
 			&membership.CreatedAt,
 			&membership.UpdatedAt,
+			&role.Name,
+			&user.FirstName,
+			&user.LastName,
+			&user.Email,
+			&user.Username,
+			&user.AvatarURL,
+			&team.Name,
+			&team.Description,
+			&team.IsPersonal,
+			&team.AvatarURL,
+			&team.CreatorID,
 		)
-
 		if err != nil {
 			return nil, err
 		}
 
+		if opts.IsSelectRole {
+			role.ID = membership.RoleID
+			membership.Role = role
+		}
+		if opts.IsSelectUser {
+			user.ID = membership.UserID
+			membership.User = user
+		}
+		if opts.IsSelectTeam {
+			team.ID = membership.TeamID
+			membership.Team = team
+		}
+
 		memberships = append(memberships, membership)
 	}
 
-	return memberships, nil
+	reverse(memberships, page)
+	return memberships, rows.Err()
+}
+
+// membershipConditions builds the filters shared by List and Count, the members are searched by name, email and username.
+func membershipConditions(opts domain.MembershipOptions) ([]string, []any) {
+	args := []any{util.GetTimestampUTC()}
+	conditions := []string{"u.is_active = true", "(m.expires_at IS NULL OR m.expires_at > $1)"}
+
+	if opts.TeamID != uuid.Nil {
+		args = append(args, opts.TeamID)
+		conditions = append(conditions, fmt.Sprintf("m.team_id = $%d", len(args)))
+	}
+
+	if opts.UserID != uuid.Nil {
+		args = append(args, opts.UserID)
+		conditions = append(conditions, fmt.Sprintf("m.user_id = $%d", len(args)))
+	}
+
+	if opts.Role != "" {
+		args = append(args, opts.Role)
+		conditions = append(conditions, fmt.Sprintf("r.name = $%d", len(args)))
+	}
+
+	if opts.Name != "" {
+		args = append(args, "%"+escapeLike(opts.Name)+"%")
+		conditions = append(conditions, fmt.Sprintf(
+			`(u.first_name || ' ' || u.last_name ILIKE $%[1]d ESCAPE '\' OR u.email ILIKE $%[1]d ESCAPE '\' OR u.username ILIKE $%[1]d ESCAPE '\')`,
+			len(args)))
+	}
+
+	return conditions, args
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern so the search matches them literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// membershipSortKey returns the expression the memberships are ordered by, the role is ordered by precedence.
+func membershipSortKey(sortBy domain.MembershipSort, args *[]any) string {
+	switch sortBy {
+	case domain.SortByRole:
+		*args = append(*args, domain.RolePrecedence.Names())
+		return fmt.Sprintf("array_position($%d::text[], r.name::text)", len(*args))
+	case domain.SortByLastActiveAt:
+		return "COALESCE(m.last_active_at, m.created_at)"
+	}
+	return "m.created_at"
+}
+
+func membershipSortType(sortBy domain.MembershipSort) string {
+	if sortBy == domain.SortByRole {
+		return "int"
+	}
+	return "timestamp"
 }
 
 func (repo *membershipRepository) Delete(ctx context.Context, id uuid.UUID, tx pgx.Tx) error {
 	query := `
 		DELETE FROM memberships WHERE id = $1
+		RETURNING team_id, user_id
 	`
 
-	_, err := tx.Exec(
+	var teamID, userID uuid.UUID
+
+	err := tx.QueryRow(
 		ctx,
 		query,
 		id,
-	)
+	).Scan(&teamID, &userID)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return exception.NewNotFoundException("membership not found")
+		}
 		return err
 	}
 
-	return nil
+	return unmirrorMembership(ctx, tx, teamID, userID)
 }
 
 func (repo *membershipRepository) Count(ctx context.Context, opts domain.MembershipOptions) (int64, error) {
 	query := `
-		SELECT COUNT(id)
-		FROM memberships
-		WHERE 
+		SELECT COUNT(m.id)
+		FROM memberships m
+		JOIN roles r ON r.id = m.role_id
+		JOIN users u ON u.id = m.user_id
 	`
 
-	argsNumber := 1
-	args := make([]interface{}, 0)
-
-	if opts.TeamID != uuid.Nil {
-		query += fmt.Sprintf("team_id = $%d", argsNumber)
-		argsNumber++
-		args = append(args, opts.TeamID)
-	}
-
-	if opts.UserID != uuid.Nil {
-		query += fmt.Sprintf("user_id = $%d", argsNumber)
-		argsNumber++
-		args = append(args, opts.UserID)
-	}
+	conditions, args := membershipConditions(opts)
+	query += " WHERE " + strings.Join(conditions, " AND ")
 
 	var count int64
 
@@ -262,3 +506,184 @@ func (repo *membershipRepository) Count(ctx context.Context, opts domain.Members
 
 	return count, nil
 }
+
+// CountByRoles counts the active members of the team for each role.
+func (repo *membershipRepository) CountByRoles(ctx context.Context, teamID uuid.UUID) (map[domain.RoleType]int64, error) {
+	query := `
+		SELECT r.name, COUNT(m.id)
+		FROM memberships m
+		JOIN roles r ON r.id = m.role_id
+		JOIN users u ON u.id = m.user_id
+		WHERE m.team_id = $1 AND u.is_active = true AND (m.expires_at IS NULL OR m.expires_at > $2)
+		GROUP BY r.name
+	`
+
+	rows, err := repo.pool.Query(ctx, query, teamID, util.GetTimestampUTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.RoleType]int64)
+	for rows.Next() {
+		var role domain.RoleType
+		var count int64
+		if err := rows.Scan(&role, &count); err != nil {
+			return nil, err
+		}
+		counts[role] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// ListPreviews returns the first active members of each team ordered by role, up to limit per team, in one query.
+func (repo *membershipRepository) ListPreviews(ctx context.Context, teamIDs []uuid.UUID, limit int) (map[uuid.UUID][]domain.Membership, error) {
+	query := `
+		SELECT id, team_id, user_id, role_id, last_active_at, created_at, updated_at,
+			role_name, first_name, last_name, email, username, avatar_url
+		FROM (
+			SELECT m.id, m.team_id, m.user_id, m.role_id, m.last_active_at, m.created_at, m.updated_at,
+				r.name AS role_name, u.first_name, u.last_name, u.email, u.username, u.avatar_url,
+				ROW_NUMBER() OVER (
+					PARTITION BY m.team_id
+					ORDER BY array_position($2::text[], r.name::text), m.id
+				) AS position
+			FROM memberships m
+			JOIN roles r ON r.id = m.role_id
+			JOIN users u ON u.id = m.user_id
+			WHERE m.team_id = ANY($1::uuid[]) AND u.is_active = true AND (m.expires_at IS NULL OR m.expires_at > $4)
+		) previews
+		WHERE position <= $3
+		ORDER BY team_id, position
+	`
+
+	rows, err := repo.pool.Query(ctx, query, uuidStrings(teamIDs), domain.RolePrecedence.Names(), limit, util.GetTimestampUTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	previews := make(map[uuid.UUID][]domain.Membership, len(teamIDs))
+	for rows.Next() {
+		var membership domain.Membership
+		err := rows.Scan(
+			&membership.ID,
+			&membership.TeamID,
+			&membership.UserID,
+			&membership.RoleID,
+			&membership.LastActiveAt,
+			&membership.CreatedAt,
+			&membership.UpdatedAt,
+			&membership.Role.Name,
+			&membership.User.FirstName,
+			&membership.User.LastName,
+			&membership.User.Email,
+			&membership.User.Username,
+			&membership.User.AvatarURL,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		membership.Role.ID = membership.RoleID
+		membership.User.ID = membership.UserID
+		previews[membership.TeamID] = append(previews[membership.TeamID], membership)
+	}
+
+	return previews, rows.Err()
+}
+
+// CountByTeams counts the active members of each team in one query, the teams without member are left out.
+func (repo *membershipRepository) CountByTeams(ctx context.Context, teamIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	query := `
+		SELECT m.team_id, COUNT(m.id)
+		FROM memberships m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.team_id = ANY($1::uuid[]) AND u.is_active = true AND (m.expires_at IS NULL OR m.expires_at > $2)
+		GROUP BY m.team_id
+	`
+
+	rows, err := repo.pool.Query(ctx, query, uuidStrings(teamIDs), util.GetTimestampUTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]int64, len(teamIDs))
+	for rows.Next() {
+		var teamID uuid.UUID
+		var count int64
+		if err := rows.Scan(&teamID, &count); err != nil {
+			return nil, err
+		}
+		counts[teamID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return values
+}
+
+// ListExpired returns up to limit memberships past their expiry at now, along with their user, role and team name.
+func (repo *membershipRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]domain.Membership, error) {
+	query := `
+		SELECT m.id, m.team_id, m.user_id, m.role_id, r.name, m.expires_at,
+			u.first_name, u.last_name, u.email, t.name
+		FROM memberships m
+		JOIN roles r ON r.id = m.role_id
+		JOIN users u ON u.id = m.user_id
+		JOIN teams t ON t.id = m.team_id
+		WHERE m.expires_at IS NOT NULL AND m.expires_at <= $1
+		ORDER BY m.expires_at, m.id
+		LIMIT $2
+	`
+
+	rows, err := repo.pool.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []domain.Membership
+	for rows.Next() {
+		var membership domain.Membership
+		err := rows.Scan(
+			&membership.ID,
+			&membership.TeamID,
+			&membership.UserID,
+			&membership.RoleID,
+			&membership.Role.Name,
+			&membership.ExpiresAt,
+			&membership.User.FirstName,
+			&membership.User.LastName,
+			&membership.User.Email,
+			&membership.Team.Name,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		membership.Role.ID = membership.RoleID
+		membership.User.ID = membership.UserID
+		membership.Team.ID = membership.TeamID
+		memberships = append(memberships, membership)
+	}
+
+	return memberships, rows.Err()
+}
+
+// deleteExpiredMembership deletes the membership of the user in the team past its expiry, it must run before the user
+// is added to the team again so the team never has two memberships of the user.
+func deleteExpiredMembership(ctx context.Context, tx pgx.Tx, teamID, userID uuid.UUID) error {
+	query := `DELETE FROM memberships WHERE team_id = $1 AND user_id = $2 AND expires_at <= $3`
+
+	_, err := tx.Exec(ctx, query, teamID, userID, util.GetTimestampUTC())
+	return err
+}
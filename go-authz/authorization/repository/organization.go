@@ -0,0 +1,430 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type organizationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// organizationRepository implements the OrganizationRepository interface
+type OrganizationRepository interface {
+	Add(context.Context, domain.Organization, pgx.Tx) (domain.Organization, error)
+	Update(context.Context, domain.Organization, pgx.Tx) (domain.Organization, error)
+	Get(context.Context, uuid.UUID) (domain.Organization, error)
+	ListByUser(context.Context, uuid.UUID) ([]domain.Organization, error)
+	AddMembership(context.Context, domain.OrganizationMembership, pgx.Tx) (domain.OrganizationMembership, error)
+	UpdateMembership(context.Context, domain.OrganizationMembership, pgx.Tx) (domain.OrganizationMembership, error)
+	DeleteMembership(context.Context, uuid.UUID, pgx.Tx) error
+	GetMembership(context.Context, uuid.UUID) (domain.OrganizationMembership, error)
+	ListMemberships(context.Context, uuid.UUID) ([]domain.OrganizationMembership, error)
+	GetMembershipByUser(context.Context, uuid.UUID, uuid.UUID) (domain.OrganizationMembership, error)
+	SetTeamOrganization(context.Context, uuid.UUID, uuid.NullUUID, pgx.Tx) error
+	ListTeams(context.Context, uuid.UUID) ([]domain.Team, error)
+}
+
+func NewOrganizationRepository(pool *pgxpool.Pool) OrganizationRepository {
+	return &organizationRepository{pool: pool}
+}
+
+func (repo *organizationRepository) Add(ctx context.Context, organization domain.Organization, tx pgx.Tx) (domain.Organization, error) {
+	query := `
+		INSERT INTO organizations (id, name, description, creator_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		organization.ID,
+		organization.Name,
+		organization.Description,
+		organization.CreatorID,
+		organization.CreatedAt,
+		organization.UpdatedAt,
+	)
+	if err != nil {
+		return domain.Organization{}, err
+	}
+
+	for _, membership := range organization.Memberships {
+		_, err = repo.AddMembership(ctx, membership, tx)
+		if err != nil {
+			return domain.Organization{}, err
+		}
+	}
+
+	return organization, nil
+}
+
+func (repo *organizationRepository) Update(ctx context.Context, organization domain.Organization, tx pgx.Tx) (domain.Organization, error) {
+	query := `
+		UPDATE organizations
+		SET name = $2, description = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		organization.ID,
+		organization.Name,
+		organization.Description,
+		organization.UpdatedAt,
+	)
+	if err != nil {
+		return domain.Organization{}, err
+	}
+
+	return organization, nil
+}
+
+func (repo *organizationRepository) Get(ctx context.Context, id uuid.UUID) (domain.Organization, error) {
+	query := `
+		SELECT o.id, o.name, o.description, o.creator_id, o.created_at, o.updated_at,
+			u.id, u.first_name, u.last_name, u.email, u.username, u.avatar_url
+		FROM organizations o
+		JOIN users u ON u.id = o.creator_id
+		WHERE o.id = $1
+	`
+
+	var organization domain.Organization
+
+	err := repo.pool.QueryRow(ctx, query, id).Scan(
+		&organization.ID,
+		&organization.Name,
+		&organization.Description,
+		&organization.CreatorID,
+		&organization.CreatedAt,
+		&organization.UpdatedAt,
+		&organization.Creator.ID,
+		&organization.Creator.FirstName,
+		&organization.Creator.LastName,
+		&organization.Creator.Email,
+		&organization.Creator.Username,
+		&organization.Creator.AvatarURL,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Organization{}, exception.NewNotFoundException("organization not found")
+		}
+		return domain.Organization{}, err
+	}
+
+	return organization, nil
+}
+
+// ListByUser returns the organizations of the user, each with the user's own membership.
+func (repo *organizationRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.Organization, error) {
+	query := `
+		SELECT o.id, o.name, o.description, o.creator_id, o.created_at, o.updated_at,
+			om.id, om.user_id, om.role_id, r.name
+		FROM organization_memberships om
+		JOIN organizations o ON o.id = om.organization_id
+		JOIN roles r ON r.id = om.role_id
+		WHERE om.user_id = $1
+		ORDER BY o.name
+	`
+
+	rows, err := repo.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var organizations []domain.Organization
+	for rows.Next() {
+		var organization domain.Organization
+		var membership domain.OrganizationMembership
+		err := rows.Scan(
+			&organization.ID,
+			&organization.Name,
+			&organization.Description,
+			&organization.CreatorID,
+			&organization.CreatedAt,
+			&organization.UpdatedAt,
+			&membership.ID,
+			&membership.UserID,
+			&membership.RoleID,
+			&membership.Role.Name,
+		)
+		if err != nil {
+			return nil, err
+		}
+		membership.OrganizationID = organization.ID
+		organization.Memberships = []domain.OrganizationMembership{membership}
+		organizations = append(organizations, organization)
+	}
+
+	return organizations, nil
+}
+
+func (repo *organizationRepository) AddMembership(ctx context.Context, membership domain.OrganizationMembership, tx pgx.Tx) (domain.OrganizationMembership, error) {
+	query := `
+		INSERT INTO organization_memberships (id, organization_id, user_id, role_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		membership.ID,
+		membership.OrganizationID,
+		membership.UserID,
+		membership.RoleID,
+		membership.CreatedAt,
+		membership.UpdatedAt,
+	)
+	if err != nil {
+		return domain.OrganizationMembership{}, err
+	}
+
+	err = mirrorOrganizationMembership(ctx, tx, membership.OrganizationID, membership.UserID, membership.RoleID)
+	if err != nil {
+		return domain.OrganizationMembership{}, err
+	}
+
+	return membership, nil
+}
+
+func (repo *organizationRepository) UpdateMembership(ctx context.Context, membership domain.OrganizationMembership, tx pgx.Tx) (domain.OrganizationMembership, error) {
+	query := `
+		UPDATE organization_memberships
+		SET role_id = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, membership.ID, membership.RoleID, membership.UpdatedAt)
+	if err != nil {
+		return domain.OrganizationMembership{}, err
+	}
+
+	err = mirrorOrganizationMembership(ctx, tx, membership.OrganizationID, membership.UserID, membership.RoleID)
+	if err != nil {
+		return domain.OrganizationMembership{}, err
+	}
+
+	return membership, nil
+}
+
+func (repo *organizationRepository) DeleteMembership(ctx context.Context, id uuid.UUID, tx pgx.Tx) error {
+	query := `
+		DELETE FROM organization_memberships WHERE id = $1
+		RETURNING organization_id, user_id
+	`
+
+	var organizationID, userID uuid.UUID
+
+	err := tx.QueryRow(ctx, query, id).Scan(&organizationID, &userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return exception.NewNotFoundException("organization membership not found")
+		}
+		return err
+	}
+
+	return unmirrorOrganizationMembership(ctx, tx, organizationID, userID)
+}
+
+func (repo *organizationRepository) GetMembership(ctx context.Context, id uuid.UUID) (domain.OrganizationMembership, error) {
+	query := `
+		SELECT om.id, om.organization_id, om.user_id, om.role_id, r.name, om.created_at, om.updated_at
+		FROM organization_memberships om
+		JOIN roles r ON r.id = om.role_id
+		WHERE om.id = $1
+	`
+
+	membership, err := scanOrganizationMembership(repo.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OrganizationMembership{}, exception.NewNotFoundException("organization membership not found")
+		}
+		return domain.OrganizationMembership{}, err
+	}
+
+	return membership, nil
+}
+
+func (repo *organizationRepository) GetMembershipByUser(ctx context.Context, organizationID, userID uuid.UUID) (domain.OrganizationMembership, error) {
+	query := `
+		SELECT om.id, om.organization_id, om.user_id, om.role_id, r.name, om.created_at, om.updated_at
+		FROM organization_memberships om
+		JOIN roles r ON r.id = om.role_id
+		WHERE om.organization_id = $1 AND om.user_id = $2
+	`
+
+	membership, err := scanOrganizationMembership(repo.pool.QueryRow(ctx, query, organizationID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OrganizationMembership{}, exception.NewNotFoundException("organization membership not found")
+		}
+		return domain.OrganizationMembership{}, err
+	}
+
+	return membership, nil
+}
+
+func (repo *organizationRepository) ListMemberships(ctx context.Context, organizationID uuid.UUID) ([]domain.OrganizationMembership, error) {
+	query := `
+		SELECT om.id, om.organization_id, om.user_id, om.role_id, r.name, om.created_at, om.updated_at,
+			u.id, u.first_name, u.last_name, u.email, u.username, u.avatar_url
+		FROM organization_memberships om
+		JOIN roles r ON r.id = om.role_id
+		JOIN users u ON u.id = om.user_id
+		WHERE om.organization_id = $1
+		ORDER BY om.created_at
+	`
+
+	rows, err := repo.pool.Query(ctx, query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []domain.OrganizationMembership
+	for rows.Next() {
+		var membership domain.OrganizationMembership
+		err := rows.Scan(
+			&membership.ID,
+			&membership.OrganizationID,
+			&membership.UserID,
+			&membership.RoleID,
+			&membership.Role.Name,
+			&membership.CreatedAt,
+			&membership.UpdatedAt,
+			&membership.User.ID,
+			&membership.User.FirstName,
+			&membership.User.LastName,
+			&membership.User.Email,
+			&membership.User.Username,
+			&membership.User.AvatarURL,
+		)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, membership)
+	}
+
+	return memberships, nil
+}
+
+// SetTeamOrganization attaches the team to the organization, a null organization detaches it.
+func (repo *organizationRepository) SetTeamOrganization(ctx context.Context, teamID uuid.UUID, organizationID uuid.NullUUID, tx pgx.Tx) error {
+	query := `
+		UPDATE teams
+		SET organization_id = $2
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, teamID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	return mirrorTeamOrganization(ctx, tx, teamID, organizationID)
+}
+
+func (repo *organizationRepository) ListTeams(ctx context.Context, organizationID uuid.UUID) ([]domain.Team, error) {
+	query := `
+		SELECT id, name, description, is_personal, avatar_url, creator_id, organization_id, created_at, updated_at
+		FROM teams
+		WHERE organization_id = $1
+		ORDER BY name
+	`
+
+	rows, err := repo.pool.Query(ctx, query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []domain.Team
+	for rows.Next() {
+		var team domain.Team
+		err := rows.Scan(
+			&team.ID,
+			&team.Name,
+			&team.Description,
+			&team.IsPersonal,
+			&team.AvatarURL,
+			&team.CreatorID,
+			&team.OrganizationID,
+			&team.CreatedAt,
+			&team.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, nil
+}
+
+func scanOrganizationMembership(row pgx.Row) (domain.OrganizationMembership, error) {
+	var membership domain.OrganizationMembership
+	err := row.Scan(
+		&membership.ID,
+		&membership.OrganizationID,
+		&membership.UserID,
+		&membership.RoleID,
+		&membership.Role.Name,
+		&membership.CreatedAt,
+		&membership.UpdatedAt,
+	)
+	return membership, err
+}
+
+// mirrorOrganizationMembership keeps the organization:<id>#<role>@user:<user_id> tuple in sync with an organization membership.
+func mirrorOrganizationMembership(ctx context.Context, tx pgx.Tx, organizationID, userID uuid.UUID, roleID ulid.ULID) error {
+	if err := unmirrorOrganizationMembership(ctx, tx, organizationID, userID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO relation_tuples (object_type, object_id, relation, subject_type, subject_id)
+		SELECT $1, $2, r.name, $3, $4
+		FROM roles r
+		WHERE r.id = $5
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := tx.Exec(ctx, query, domain.OrganizationNamespace, organizationID.String(), domain.UserNamespace, userID.String(), roleID)
+	return err
+}
+
+func unmirrorOrganizationMembership(ctx context.Context, tx pgx.Tx, organizationID, userID uuid.UUID) error {
+	query := `
+		DELETE FROM relation_tuples
+		WHERE object_type = $1 AND object_id = $2 AND subject_type = $3 AND subject_id = $4
+			AND subject_relation = '' AND relation IN (SELECT name FROM roles)
+	`
+
+	_, err := tx.Exec(ctx, query, domain.OrganizationNamespace, organizationID.String(), domain.UserNamespace, userID.String())
+	return err
+}
+
+// mirrorTeamOrganization keeps the team:<id>#organization@organization:<organization_id> tuple in sync with teams.organization_id.
+func mirrorTeamOrganization(ctx context.Context, tx pgx.Tx, teamID uuid.UUID, organizationID uuid.NullUUID) error {
+	query := `
+		DELETE FROM relation_tuples
+		WHERE object_type = $1 AND object_id = $2 AND relation = $3
+	`
+
+	_, err := tx.Exec(ctx, query, domain.TeamNamespace, teamID.String(), domain.OrganizationNamespace)
+	if err != nil || !organizationID.Valid {
+		return err
+	}
+
+	tuple := domain.NewRelationTuple(domain.TeamNamespace, teamID.String(), domain.OrganizationNamespace, domain.OrganizationNamespace, organizationID.UUID.String(), "")
+	return Relation.Add(ctx, tuple, tx)
+}
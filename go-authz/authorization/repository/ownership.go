@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type ownershipTransferRepository struct {
+	pool *pgxpool.Pool
+}
+
+type OwnershipTransferRepository interface {
+	Add(context.Context, domain.OwnershipTransfer, pgx.Tx) (domain.OwnershipTransfer, error)
+	Update(context.Context, domain.OwnershipTransfer, pgx.Tx) error
+	Get(context.Context, ulid.ULID) (domain.OwnershipTransfer, error)
+	GetPendingByTeam(context.Context, uuid.UUID) (domain.OwnershipTransfer, error)
+}
+
+// ownershipTransferRepository implements the OwnershipTransferRepository interface
+func NewOwnershipTransferRepository(pool *pgxpool.Pool) OwnershipTransferRepository {
+	return &ownershipTransferRepository{pool: pool}
+}
+
+const ownershipTransferColumns = `
+	ot.id, ot.team_id, t.name, ot.from_user_id, ot.to_user_id, ot.keep_ownership, ot.status, ot.expires_at, ot.created_at, ot.updated_at,
+	fu.id, fu.first_name, fu.last_name, fu.email, fu.username, fu.avatar_url,
+	tu.id, tu.first_name, tu.last_name, tu.email, tu.username, tu.avatar_url
+`
+
+const ownershipTransferJoins = `
+	FROM ownership_transfers ot
+	JOIN teams t ON t.id = ot.team_id
+	JOIN users fu ON fu.id = ot.from_user_id
+	JOIN users tu ON tu.id = ot.to_user_id
+`
+
+func scanOwnershipTransfer(row pgx.Row) (domain.OwnershipTransfer, error) {
+	var transfer domain.OwnershipTransfer
+	err := row.Scan(
+		&transfer.ID,
+		&transfer.TeamID,
+		&transfer.Team.Name,
+		&transfer.FromUserID,
+		&transfer.ToUserID,
+		&transfer.KeepOwnership,
+		&transfer.Status,
+		&transfer.ExpiresAt,
+		&transfer.CreatedAt,
+		&transfer.UpdatedAt,
+		&transfer.FromUser.ID,
+		&transfer.FromUser.FirstName,
+		&transfer.FromUser.LastName,
+		&transfer.FromUser.Email,
+		&transfer.FromUser.Username,
+		&transfer.FromUser.AvatarURL,
+		&transfer.ToUser.ID,
+		&transfer.ToUser.FirstName,
+		&transfer.ToUser.LastName,
+		&transfer.ToUser.Email,
+		&transfer.ToUser.Username,
+		&transfer.ToUser.AvatarURL,
+	)
+	transfer.Team.ID = transfer.TeamID
+	return transfer, err
+}
+
+func (repo *ownershipTransferRepository) Add(ctx context.Context, transfer domain.OwnershipTransfer, tx pgx.Tx) (domain.OwnershipTransfer, error) {
+	query := `
+		INSERT INTO ownership_transfers (id, team_id, from_user_id, to_user_id, keep_ownership, status, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		transfer.ID,
+		transfer.TeamID,
+		transfer.FromUserID,
+		transfer.ToUserID,
+		transfer.KeepOwnership,
+		transfer.Status,
+		transfer.ExpiresAt,
+		transfer.CreatedAt,
+		transfer.UpdatedAt,
+	)
+	if err != nil {
+		return domain.OwnershipTransfer{}, err
+	}
+
+	return transfer, nil
+}
+
+func (repo *ownershipTransferRepository) Update(ctx context.Context, transfer domain.OwnershipTransfer, tx pgx.Tx) error {
+	query := `
+		UPDATE ownership_transfers
+		SET status = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, transfer.ID, transfer.Status, transfer.UpdatedAt)
+	return err
+}
+
+func (repo *ownershipTransferRepository) Get(ctx context.Context, id ulid.ULID) (domain.OwnershipTransfer, error) {
+	query := `SELECT ` + ownershipTransferColumns + ownershipTransferJoins + ` WHERE ot.id = $1`
+
+	transfer, err := scanOwnershipTransfer(repo.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OwnershipTransfer{}, exception.NewNotFoundException("ownership transfer not found")
+		}
+		return domain.OwnershipTransfer{}, err
+	}
+
+	return transfer, nil
+}
+
+func (repo *ownershipTransferRepository) GetPendingByTeam(ctx context.Context, teamID uuid.UUID) (domain.OwnershipTransfer, error) {
+	query := `SELECT ` + ownershipTransferColumns + ownershipTransferJoins + ` WHERE ot.team_id = $1 AND ot.status = $2`
+
+	transfer, err := scanOwnershipTransfer(repo.pool.QueryRow(ctx, query, teamID, domain.OwnershipTransferStatusPending))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OwnershipTransfer{}, exception.NewNotFoundException("ownership transfer not found")
+		}
+		return domain.OwnershipTransfer{}, err
+	}
+
+	return transfer, nil
+}
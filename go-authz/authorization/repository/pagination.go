@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"fmt"
+	"strings"
+)
+
+// keyset orders a list by a sort key and then by the row id, so that the rows sharing a sort value keep a stable order
+// and a page can start right after the row a cursor points at without counting the rows before it.
+type keyset struct {
+	// SortKey is the expression the rows are ordered by and SortType its postgres type
+	SortKey  string
+	SortType string
+	// IDColumn is the primary key of the rows and IDType its postgres type
+	IDColumn string
+	IDType   string
+	// ParseID converts the id of a cursor when it is not stored in its text form, e.g. the ULIDs stored as bytes
+	ParseID func(string) (any, error)
+}
+
+// apply appends the conditions, the ordering and the limit of the page to the query.
+// A backward page is read in the reverse order, the caller puts the rows back in order with reverse.
+func (k keyset) apply(query string, conditions []string, args []any, page domain.Page) (string, []any, error) {
+	descending := page.Descending != page.IsBackward()
+
+	direction, comparison := "ASC", ">"
+	if descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if page.Cursor != nil {
+		var id any = page.Cursor.ID
+		idParam := "$%d::text::" + k.IDType
+		if k.ParseID != nil {
+			parsed, err := k.ParseID(page.Cursor.ID)
+			if err != nil {
+				return "", nil, exception.NewBadRequestException("invalid cursor")
+			}
+			id, idParam = parsed, "$%d"
+		}
+
+		args = append(args, page.Cursor.Value, id)
+		conditions = append(conditions, fmt.Sprintf("(%s, %s) %s ($%d::text::%s, "+idParam+")",
+			k.SortKey, k.IDColumn, comparison, len(args)-1, k.SortType, len(args)))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s %s, %s %s", k.SortKey, direction, k.IDColumn, direction)
+
+	if page.Limit > 0 {
+		args = append(args, page.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	return query, args, nil
+}
+
+// reverse puts the rows of a backward page back in the order of the list.
+func reverse[T any](rows []T, page domain.Page) {
+	if !page.IsBackward() {
+		return
+	}
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"errors"
+	"fmt"
+
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type permissionRepository struct {
+	pool *pgxpool.Pool // Use pgxpool.Pool for connection pooling
+}
+
+// permissionRepository implements the PermissionRepository interface
+type PermissionRepository interface {
+	Save(context.Context, pgx.Tx, domain.Permission) error
+	GetByName(context.Context, string) (domain.Permission, error)
+	List(context.Context) (domain.Permissions, error)
+}
+
+func NewPermissionRepository(pool *pgxpool.Pool) PermissionRepository {
+	return &permissionRepository{pool: pool}
+}
+
+func (repo *permissionRepository) Save(ctx context.Context, tx pgx.Tx, permission domain.Permission) error {
+	query := `
+		INSERT INTO permissions (id, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE SET
+			description = $3,
+			updated_at = $5
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		permission.ID,
+		permission.Name,
+		permission.Description,
+		permission.CreatedAt,
+		permission.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *permissionRepository) GetByName(ctx context.Context, name string) (domain.Permission, error) {
+	query := `
+		SELECT id, name, description, created_at, updated_at
+		FROM permissions
+		WHERE name = $1
+	`
+
+	var permission domain.Permission
+
+	err := repo.pool.QueryRow(
+		ctx,
+		query,
+		name,
+	).Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt, &permission.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Permission{}, exception.NewNotFoundException(fmt.Sprintf("Permission with name %s does not exist", name))
+		}
+		return domain.Permission{}, err
+	}
+
+	return permission, nil
+}
+
+func (repo *permissionRepository) List(ctx context.Context) (domain.Permissions, error) {
+	query := `
+		SELECT id, name, description, created_at, updated_at
+		FROM permissions
+		ORDER BY name
+	`
+
+	rows, err := repo.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions domain.Permissions
+	for rows.Next() {
+		var permission domain.Permission
+		err := rows.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt, &permission.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	uuid "github.com/satori/go.uuid"
+)
+
+type policyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// policyRepository implements the PolicyRepository interface
+type PolicyRepository interface {
+	Add(context.Context, domain.Policy, pgx.Tx) (domain.Policy, error)
+	Update(context.Context, domain.Policy, pgx.Tx) (domain.Policy, error)
+	Delete(context.Context, uuid.UUID, pgx.Tx) error
+	Get(context.Context, uuid.UUID) (domain.Policy, error)
+	List(context.Context, uuid.UUID) (domain.Policies, error)
+	ListActiveByPermission(context.Context, uuid.UUID, string) (domain.Policies, error)
+}
+
+func NewPolicyRepository(pool *pgxpool.Pool) PolicyRepository {
+	return &policyRepository{pool: pool}
+}
+
+const policyColumns = `id, team_id, name, description, permission, expression, is_active, creator_id, created_at, updated_at`
+
+func (repo *policyRepository) Add(ctx context.Context, policy domain.Policy, tx pgx.Tx) (domain.Policy, error) {
+	query := `
+		INSERT INTO policies (` + policyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		policy.ID,
+		policy.TeamID,
+		policy.Name,
+		policy.Description,
+		policy.Permission,
+		policy.Expression,
+		policy.IsActive,
+		policy.CreatorID,
+		policy.CreatedAt,
+		policy.UpdatedAt,
+	)
+	if err != nil {
+		return domain.Policy{}, err
+	}
+
+	return policy, nil
+}
+
+func (repo *policyRepository) Update(ctx context.Context, policy domain.Policy, tx pgx.Tx) (domain.Policy, error) {
+	query := `
+		UPDATE policies
+		SET name = $2, description = $3, permission = $4, expression = $5, is_active = $6, updated_at = $7
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		policy.ID,
+		policy.Name,
+		policy.Description,
+		policy.Permission,
+		policy.Expression,
+		policy.IsActive,
+		policy.UpdatedAt,
+	)
+	if err != nil {
+		return domain.Policy{}, err
+	}
+
+	return policy, nil
+}
+
+func (repo *policyRepository) Delete(ctx context.Context, id uuid.UUID, tx pgx.Tx) error {
+	query := `
+		DELETE FROM policies
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *policyRepository) Get(ctx context.Context, id uuid.UUID) (domain.Policy, error) {
+	query := `
+		SELECT ` + policyColumns + `
+		FROM policies
+		WHERE id = $1
+	`
+
+	policy, err := scanPolicy(repo.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Policy{}, exception.NewNotFoundException("policy not found")
+		}
+		return domain.Policy{}, err
+	}
+
+	return policy, nil
+}
+
+func (repo *policyRepository) List(ctx context.Context, teamID uuid.UUID) (domain.Policies, error) {
+	query := `
+		SELECT ` + policyColumns + `
+		FROM policies
+		WHERE team_id = $1
+		ORDER BY created_at
+	`
+
+	return repo.list(ctx, query, teamID)
+}
+
+// ListActiveByPermission returns the active policies of the team that apply to permission.
+func (repo *policyRepository) ListActiveByPermission(ctx context.Context, teamID uuid.UUID, permission string) (domain.Policies, error) {
+	query := `
+		SELECT ` + policyColumns + `
+		FROM policies
+		WHERE team_id = $1 AND is_active AND permission IN ($2, $3)
+		ORDER BY created_at
+	`
+
+	return repo.list(ctx, query, teamID, permission, domain.AnyPermission)
+}
+
+func (repo *policyRepository) list(ctx context.Context, query string, args ...interface{}) (domain.Policies, error) {
+	rows, err := repo.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies domain.Policies
+	for rows.Next() {
+		policy, err := scanPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+func scanPolicy(row pgx.Row) (domain.Policy, error) {
+	var policy domain.Policy
+	err := row.Scan(
+		&policy.ID,
+		&policy.TeamID,
+		&policy.Name,
+		&policy.Description,
+		&policy.Permission,
+		&policy.Expression,
+		&policy.IsActive,
+		&policy.CreatorID,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	)
+	return policy, err
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"authorization/domain"
+	"authorization/util"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type relationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// relationRepository implements the RelationRepository interface
+type RelationRepository interface {
+	Add(context.Context, domain.RelationTuple, pgx.Tx) error
+	Delete(context.Context, domain.RelationTuple, pgx.Tx) error
+	List(context.Context, domain.RelationTupleOptions) ([]domain.RelationTuple, error)
+	ListObjectIDs(context.Context, domain.RelationObjectOptions) ([]string, error)
+}
+
+func NewRelationRepository(pool *pgxpool.Pool) RelationRepository {
+	return &relationRepository{pool: pool}
+}
+
+func (repo *relationRepository) Add(ctx context.Context, tuple domain.RelationTuple, tx pgx.Tx) error {
+	query := `
+		INSERT INTO relation_tuples (object_type, object_id, relation, subject_type, subject_id, subject_relation, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		tuple.ObjectType,
+		tuple.ObjectID,
+		tuple.Relation,
+		tuple.SubjectType,
+		tuple.SubjectID,
+		tuple.SubjectRelation,
+		tuple.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *relationRepository) Delete(ctx context.Context, tuple domain.RelationTuple, tx pgx.Tx) error {
+	query := `
+		DELETE FROM relation_tuples
+		WHERE object_type = $1 AND object_id = $2 AND relation = $3
+			AND subject_type = $4 AND subject_id = $5 AND subject_relation = $6
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		tuple.ObjectType,
+		tuple.ObjectID,
+		tuple.Relation,
+		tuple.SubjectType,
+		tuple.SubjectID,
+		tuple.SubjectRelation,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// liveTuple leaves out the tuples mirrored from a membership past its expiry, they are only removed along with
+// the membership by the expiry sweep. The tuple is t and the current time the parameter of the given position.
+const liveTuple = `NOT EXISTS (
+	SELECT 1
+	FROM memberships em
+	WHERE t.object_type = 'team' AND t.subject_type = 'user' AND t.subject_relation = ''
+		AND em.team_id::text = t.object_id AND em.user_id::text = t.subject_id AND em.expires_at <= $%d
+)`
+
+func (repo *relationRepository) List(ctx context.Context, opts domain.RelationTupleOptions) ([]domain.RelationTuple, error) {
+	query := `
+		SELECT object_type, object_id, relation, subject_type, subject_id, subject_relation, created_at
+		FROM relation_tuples t
+	`
+
+	args := []interface{}{util.GetTimestampUTC()}
+	conditions := []string{fmt.Sprintf(liveTuple, len(args))}
+
+	addCondition := func(column, value string) {
+		if value == "" {
+			return
+		}
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	addCondition("object_type", opts.ObjectType)
+	addCondition("object_id", opts.ObjectID)
+	addCondition("relation", opts.Relation)
+	addCondition("subject_type", opts.SubjectType)
+	addCondition("subject_id", opts.SubjectID)
+
+	query += " WHERE " + strings.Join(conditions, " AND ")
+
+	if opts.Limit > 0 {
+		args = append(args, opts.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := repo.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tuples []domain.RelationTuple
+	for rows.Next() {
+		var tuple domain.RelationTuple
+		err := rows.Scan(
+			&tuple.ObjectType,
+			&tuple.ObjectID,
+			&tuple.Relation,
+			&tuple.SubjectType,
+			&tuple.SubjectID,
+			&tuple.SubjectRelation,
+			&tuple.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tuples = append(tuples, tuple)
+	}
+
+	return tuples, nil
+}
+
+// ListObjectIDs walks the relation graph backwards from the subject in a single recursive query: starting from the
+// usersets the subject belongs to, it follows the tuples granting them, the computed rewrites of the same object and
+// the tuple-to-userset rewrites pointing to them, until no new userset is found. Every step looks the tuples up by
+// their subject so only the tuples reachable from the subject are read. Like List, it ignores the tuples of the
+// memberships past their expiry.
+func (repo *relationRepository) ListObjectIDs(ctx context.Context, opts domain.RelationObjectOptions) ([]string, error) {
+	query := `
+		WITH RECURSIVE rewrites AS (
+			SELECT * FROM unnest($6::text[], $7::text[], $8::text[], $9::text[]) AS r(object_type, relation, tuple_to_userset, computed)
+		), usersets(object_type, object_id, relation) AS (
+			SELECT $3::text, $4::text, $5::text
+			WHERE $5 <> ''
+			UNION
+			SELECT t.object_type::text, t.object_id::text, t.relation::text
+			FROM relation_tuples t
+			WHERE t.subject_type = $3 AND t.subject_id = $4 AND t.subject_relation = $5
+				AND ` + fmt.Sprintf(liveTuple, 10) + `
+			UNION
+			SELECT n.object_type, n.object_id, n.relation
+			FROM usersets u
+			CROSS JOIN LATERAL (
+				SELECT t.object_type::text, t.object_id::text, t.relation::text
+				FROM relation_tuples t
+				WHERE t.subject_type = u.object_type AND t.subject_id = u.object_id AND t.subject_relation = u.relation
+					AND ` + fmt.Sprintf(liveTuple, 10) + `
+				UNION ALL
+				SELECT u.object_type, u.object_id, r.relation
+				FROM rewrites r
+				WHERE r.tuple_to_userset = '' AND r.object_type = u.object_type AND r.computed = u.relation
+				UNION ALL
+				SELECT t.object_type::text, t.object_id::text, r.relation
+				FROM rewrites r
+				JOIN relation_tuples t ON t.object_type = r.object_type AND t.relation = r.tuple_to_userset
+				WHERE r.tuple_to_userset <> '' AND r.computed = u.relation
+					AND t.subject_type = u.object_type AND t.subject_id = u.object_id
+					AND ` + fmt.Sprintf(liveTuple, 10) + `
+			) n
+		)
+		SELECT DISTINCT object_id
+		FROM usersets
+		WHERE object_type = $1 AND relation = $2
+		ORDER BY object_id
+	`
+
+	objectTypes := make([]string, 0, len(opts.Rewrites))
+	relations := make([]string, 0, len(opts.Rewrites))
+	tupleToUsersets := make([]string, 0, len(opts.Rewrites))
+	computed := make([]string, 0, len(opts.Rewrites))
+	for _, rewrite := range opts.Rewrites {
+		objectTypes = append(objectTypes, rewrite.ObjectType)
+		relations = append(relations, rewrite.Relation)
+		tupleToUsersets = append(tupleToUsersets, rewrite.TupleToUserset)
+		computed = append(computed, rewrite.Computed)
+	}
+
+	rows, err := repo.pool.Query(
+		ctx,
+		query,
+		opts.ObjectType,
+		opts.Relation,
+		opts.SubjectType,
+		opts.SubjectID,
+		opts.SubjectRelation,
+		objectTypes,
+		relations,
+		tupleToUsersets,
+		computed,
+		util.GetTimestampUTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	objectIDs := make([]string, 0)
+	for rows.Next() {
+		var objectID string
+		if err := rows.Scan(&objectID); err != nil {
+			return nil, err
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	return objectIDs, nil
+}
+
+// mirrorMembership keeps the team:<team_id>#<role>@user:<user_id> tuple in sync with a membership.
+// It must run in the same transaction as the membership write.
+func mirrorMembership(ctx context.Context, tx pgx.Tx, teamID, userID uuid.UUID, roleID ulid.ULID) error {
+	if err := unmirrorMembership(ctx, tx, teamID, userID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO relation_tuples (object_type, object_id, relation, subject_type, subject_id)
+		SELECT $1, $2, r.name, $3, $4
+		FROM roles r
+		WHERE r.id = $5
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := tx.Exec(ctx, query, domain.TeamNamespace, teamID.String(), domain.UserNamespace, userID.String(), roleID)
+	return err
+}
+
+// unmirrorMembership removes every role tuple of the user on the team.
+func unmirrorMembership(ctx context.Context, tx pgx.Tx, teamID, userID uuid.UUID) error {
+	query := `
+		DELETE FROM relation_tuples
+		WHERE object_type = $1 AND object_id = $2 AND subject_type = $3 AND subject_id = $4
+			AND subject_relation = '' AND relation IN (SELECT name FROM roles)
+	`
+
+	_, err := tx.Exec(ctx, query, domain.TeamNamespace, teamID.String(), domain.UserNamespace, userID.String())
+	return err
+}
@@ -5,15 +5,40 @@ import "authorization/infrastructure/persistence"
 var (
 	User       UserRepository
 	Role       RoleRepository
+	Permission PermissionRepository
 	Team       TeamRepository
 	Membership MembershipRepository
 	Invitation InvitationRepository
+	Relation   RelationRepository
+	Policy     PolicyRepository
+
+	Organization       OrganizationRepository
+	OwnershipTransfer  OwnershipTransferRepository
+	JoinLink           JoinLinkRepository
+	TeamDomain         TeamDomainRepository
+	InvitationImport   InvitationImportRepository
+	InvitationSettings InvitationSettingsRepository
+	JoinRequest        JoinRequestRepository
+	Elevation          ElevationRepository
+	AccessReview       AccessReviewRepository
 )
 
 func CreateRepositories() {
 	User = NewUserRepository(persistence.Pool)
 	Role = NewRoleRepository(persistence.Pool)
+	Permission = NewPermissionRepository(persistence.Pool)
 	Team = NewTeamRepository(persistence.Pool)
 	Membership = NewMembershipRepository(persistence.Pool)
 	Invitation = NewInvitationRepository(persistence.Pool)
+	Relation = NewRelationRepository(persistence.Pool)
+	Policy = NewPolicyRepository(persistence.Pool)
+	Organization = NewOrganizationRepository(persistence.Pool)
+	OwnershipTransfer = NewOwnershipTransferRepository(persistence.Pool)
+	JoinLink = NewJoinLinkRepository(persistence.Pool)
+	TeamDomain = NewTeamDomainRepository(persistence.Pool)
+	InvitationImport = NewInvitationImportRepository(persistence.Pool)
+	InvitationSettings = NewInvitationSettingsRepository(persistence.Pool)
+	JoinRequest = NewJoinRequestRepository(persistence.Pool)
+	Elevation = NewElevationRepository(persistence.Pool)
+	AccessReview = NewAccessReviewRepository(persistence.Pool)
 }
@@ -3,7 +3,7 @@ package repository
 import (
 	"authorization/controller/exception"
 	"authorization/domain"
-	"encoding/json"
+	"authorization/util"
 	"errors"
 	"fmt"
 
@@ -12,7 +12,6 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/oklog/ulid/v2"
-	"github.com/rs/zerolog/log"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -25,7 +24,8 @@ type RoleRepository interface {
 	Save(context.Context, pgx.Tx, domain.Role) error
 	Get(context.Context, ulid.ULID) (domain.Role, error)
 	GetByName(context.Context, domain.RoleType) (domain.Role, error)
-	GetAccess(context.Context, uuid.UUID, uuid.UUID, domain.Endpoint) (domain.Access, error)
+	GetAccess(context.Context, uuid.UUID, uuid.UUID, string) (domain.Access, error)
+	GetOrganizationAccess(context.Context, uuid.UUID, uuid.UUID, string) (domain.Access, error)
 }
 
 func NewRoleRepository(pool *pgxpool.Pool) RoleRepository {
@@ -34,28 +34,43 @@ func NewRoleRepository(pool *pgxpool.Pool) RoleRepository {
 
 func (repo *roleRepository) Save(ctx context.Context, tx pgx.Tx, role domain.Role) error {
 	query := `
-		INSERT INTO roles (id, name, endpoints, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO roles (id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (name) DO UPDATE SET
 			name = $2,
-			endpoints = $3,
-			updated_at = $5
+			updated_at = $4
+		RETURNING id
 	`
 
-	endpoints, err := role.Endpoints.ToJSON()
-	if err != nil {
-		return err
-	}
+	var roleID ulid.ULID
 
-	_, err = tx.Exec(
+	err := tx.QueryRow(
 		ctx,
 		query,
 		role.ID,
 		role.Name,
-		endpoints,
 		role.CreatedAt,
 		role.UpdatedAt,
-	)
+	).Scan(&roleID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		DELETE FROM role_permissions WHERE role_id = $1
+	`
+
+	_, err = tx.Exec(ctx, query, roleID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO role_permissions (role_id, permission_id)
+		SELECT $1, id FROM permissions WHERE name = ANY($2)
+	`
+
+	_, err = tx.Exec(ctx, query, roleID, role.Permissions.Names())
 	if err != nil {
 		return err
 	}
@@ -65,13 +80,17 @@ func (repo *roleRepository) Save(ctx context.Context, tx pgx.Tx, role domain.Rol
 
 func (repo *roleRepository) Get(ctx context.Context, id ulid.ULID) (domain.Role, error) {
 	query := `
-		SELECT id, name, created_at, updated_at, endpoints
-		FROM roles
-		WHERE id = $1
+		SELECT r.id, r.name, r.created_at, r.updated_at,
+			COALESCE(array_agg(p.name) FILTER (WHERE p.name IS NOT NULL), '{}')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		WHERE r.id = $1
+		GROUP BY r.id
 	`
 
 	var role domain.Role
-	var endpointsJSON []byte
+	var permissionNames []string
 
 	row := repo.pool.QueryRow(
 		ctx,
@@ -79,17 +98,15 @@ func (repo *roleRepository) Get(ctx context.Context, id ulid.ULID) (domain.Role,
 		id,
 	)
 
-	if err := row.Scan(&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt, &endpointsJSON); err != nil {
+	if err := row.Scan(&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt, &permissionNames); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.Role{}, exception.NewNotFoundException(fmt.Sprintf("Role with id %s does not exist", id))
 		}
 		return domain.Role{}, err
 	}
 
-	err := json.Unmarshal(endpointsJSON, &role.Endpoints)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to unmarshal endpoints")
-		return domain.Role{}, err
+	for _, name := range permissionNames {
+		role.Permissions.Add(domain.Permission{Name: name})
 	}
 
 	return role, nil
@@ -97,13 +114,17 @@ func (repo *roleRepository) Get(ctx context.Context, id ulid.ULID) (domain.Role,
 
 func (repo *roleRepository) GetByName(ctx context.Context, name domain.RoleType) (domain.Role, error) {
 	query := `
-		SELECT id, name, created_at, updated_at, endpoints
-		FROM roles
-		WHERE name = $1
+		SELECT r.id, r.name, r.created_at, r.updated_at,
+			COALESCE(array_agg(p.name) FILTER (WHERE p.name IS NOT NULL), '{}')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		WHERE r.name = $1
+		GROUP BY r.id
 	`
 
 	var role domain.Role
-	var endpointsJSON []byte
+	var permissionNames []string
 
 	row := repo.pool.QueryRow(
 		ctx,
@@ -111,53 +132,131 @@ func (repo *roleRepository) GetByName(ctx context.Context, name domain.RoleType)
 		name,
 	)
 
-	if err := row.Scan(&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt, &endpointsJSON); err != nil {
+	if err := row.Scan(&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt, &permissionNames); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.Role{}, exception.NewNotFoundException(fmt.Sprintf("Role with name %s does not exist", name))
 		}
 		return domain.Role{}, err
 	}
 
-	err := json.Unmarshal(endpointsJSON, &role.Endpoints)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to unmarshal endpoints")
-		return domain.Role{}, err
+	for _, permissionName := range permissionNames {
+		role.Permissions.Add(domain.Permission{Name: permissionName})
 	}
 
 	return role, nil
 }
 
-func (repo *roleRepository) GetAccess(ctx context.Context, teamID, userID uuid.UUID, endpoint domain.Endpoint) (domain.Access, error) {
+// GetAccess resolves the user's effective role in the team and checks the permission in a single query.
+// The effective role is the team membership role or an inherited one: owners and admins of the organization
+// owning the team or one of its ancestors, and owners and admins of an ancestor team are admins of the team,
+// members of an ancestor team are members when every sub-team on the way inherits members.
+// A user without any of them is not allowed, the memberships past their expiry are ignored.
+func (repo *roleRepository) GetAccess(ctx context.Context, teamID, userID uuid.UUID, permission string) (domain.Access, error) {
 	query := `
-		SELECT r.name, r.endpoints
-		FROM memberships m
-		LEFT JOIN roles r ON r.id = m.role_id
-		WHERE m.team_id = $1 AND m.user_id = $2
+		WITH RECURSIVE ancestors AS (
+			SELECT t.parent_id AS team_id, t.inheritance::text AS inheritance, 1 AS depth
+			FROM teams t
+			WHERE t.id = $1 AND t.parent_id IS NOT NULL AND t.inheritance <> $9
+			UNION ALL
+			SELECT t.parent_id, CASE WHEN t.inheritance = $10 THEN a.inheritance ELSE t.inheritance::text END, a.depth + 1
+			FROM teams t
+			JOIN ancestors a ON a.team_id = t.id
+			WHERE t.parent_id IS NOT NULL AND t.inheritance <> $9 AND a.depth < $11
+		),
+		candidates AS (
+			SELECT m.role_id, $4::text AS source
+			FROM memberships m
+			WHERE m.team_id = $1 AND m.user_id = $2 AND (m.expires_at IS NULL OR m.expires_at > $14)
+			UNION ALL
+			SELECT inherited.id, $5::text
+			FROM teams t
+			JOIN organization_memberships om ON om.organization_id = t.organization_id
+			JOIN roles org_role ON org_role.id = om.role_id
+			JOIN roles inherited ON inherited.name = $6
+			WHERE (t.id = $1 OR t.id IN (SELECT team_id FROM ancestors))
+				AND om.user_id = $2 AND org_role.name = ANY($7)
+			UNION ALL
+			SELECT inherited.id, $12::text
+			FROM ancestors a
+			JOIN memberships m ON m.team_id = a.team_id
+			JOIN roles parent_role ON parent_role.id = m.role_id
+			JOIN roles inherited ON inherited.name = CASE WHEN parent_role.name = ANY($7) THEN $6 ELSE parent_role.name END
+			WHERE m.user_id = $2 AND (m.expires_at IS NULL OR m.expires_at > $14)
+				AND (parent_role.name = ANY($7) OR (parent_role.name = $13 AND a.inheritance = $10))
+		)
+		SELECT r.name, c.source, EXISTS (
+			SELECT 1
+			FROM role_permissions rp
+			JOIN permissions p ON p.id = rp.permission_id
+			WHERE rp.role_id = c.role_id AND p.name = $3
+		) AS allowed
+		FROM candidates c
+		JOIN roles r ON r.id = c.role_id
+		ORDER BY allowed DESC, array_position($8::text[], r.name::text)
+		LIMIT 1
 	`
 
-	var access domain.Access
-	var endpointsJSON []byte
-	var endpoints domain.Endpoints
+	access := domain.Access{Permission: permission}
 
 	err := repo.pool.QueryRow(
 		ctx,
 		query,
 		teamID,
 		userID,
-	).Scan(access.RoleName, endpointsJSON)
+		permission,
+		domain.AccessSourceTeam,
+		domain.AccessSourceOrganization,
+		domain.Admin,
+		[]string{string(domain.Owner), string(domain.Admin)},
+		domain.RolePrecedence.Names(),
+		domain.InheritNone,
+		domain.InheritMembers,
+		domain.MaxTeamDepth,
+		domain.AccessSourceParentTeam,
+		domain.Member,
+		util.GetTimestampUTC(),
+	).Scan(&access.RoleName, &access.Source, &access.IsAllowed)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return access, nil
+		}
 		return domain.Access{}, err
 	}
 
-	err = json.Unmarshal(endpointsJSON, &endpoints)
+	return access, nil
+}
+
+// GetOrganizationAccess resolves the user's role in the organization and checks the permission.
+func (repo *roleRepository) GetOrganizationAccess(ctx context.Context, organizationID, userID uuid.UUID, permission string) (domain.Access, error) {
+	query := `
+		SELECT r.name, EXISTS (
+			SELECT 1
+			FROM role_permissions rp
+			JOIN permissions p ON p.id = rp.permission_id
+			WHERE rp.role_id = om.role_id AND p.name = $3
+		)
+		FROM organization_memberships om
+		JOIN roles r ON r.id = om.role_id
+		WHERE om.organization_id = $1 AND om.user_id = $2
+	`
+
+	access := domain.Access{Permission: permission, Source: domain.AccessSourceOrganization}
+
+	err := repo.pool.QueryRow(
+		ctx,
+		query,
+		organizationID,
+		userID,
+		permission,
+	).Scan(&access.RoleName, &access.IsAllowed)
 
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to unmarshal endpoints")
+		if errors.Is(err, pgx.ErrNoRows) {
+			return access, nil
+		}
 		return domain.Access{}, err
 	}
 
-	access.IsAllowed = endpoints.Contains(endpoint)
-	access.Endpoint = endpoint
 	return access, nil
 }
@@ -4,6 +4,8 @@ import (
 	"authorization/controller/exception"
 	"authorization/domain"
 	"authorization/util"
+	"strings"
+	"time"
 
 	"context"
 	"errors"
@@ -22,6 +24,18 @@ type TeamRepository interface {
 	Add(context.Context, domain.Team, pgx.Tx) (domain.Team, error)
 	Update(context.Context, domain.Team, pgx.Tx) (domain.Team, error)
 	Get(context.Context, uuid.UUID) (domain.Team, error)
+	SetParent(context.Context, domain.Team, pgx.Tx) error
+	Ancestors(context.Context, uuid.UUID) ([]uuid.UUID, error)
+	Height(context.Context, uuid.UUID) (int, error)
+	ListByUser(context.Context, domain.TeamOptions) ([]domain.Membership, error)
+	CountByUser(context.Context, domain.TeamOptions) (int64, error)
+	Archive(context.Context, domain.Team, pgx.Tx) error
+	Delete(context.Context, uuid.UUID, pgx.Tx) error
+	ListSoleOwned(context.Context, uuid.UUID) ([]domain.Team, error)
+	SetPlan(context.Context, domain.Team, pgx.Tx) error
+	Usage(context.Context, uuid.UUID) (domain.TeamUsage, error)
+	Lock(context.Context, uuid.UUID, pgx.Tx) error
+	LockUsage(context.Context, uuid.UUID, pgx.Tx) (domain.TeamUsage, error)
 }
 
 func NewTeamRepository(pool *pgxpool.Pool) TeamRepository {
@@ -30,8 +44,8 @@ func NewTeamRepository(pool *pgxpool.Pool) TeamRepository {
 
 func (repo *teamRepository) Add(ctx context.Context, team domain.Team, tx pgx.Tx) (domain.Team, error) {
 	query := `
-		INSERT INTO teams (id, name, description, is_personal, avatar_url, creator_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO teams (id, name, description, is_personal, avatar_url, creator_id, organization_id, parent_id, inheritance, is_discoverable, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id
 	`
 
@@ -44,6 +58,10 @@ func (repo *teamRepository) Add(ctx context.Context, team domain.Team, tx pgx.Tx
 		team.IsPersonal,
 		team.AvatarURL,
 		team.CreatorID,
+		team.OrganizationID,
+		team.ParentID,
+		team.Inheritance,
+		team.IsDiscoverable,
 		team.CreatedAt,
 		team.UpdatedAt,
 	)
@@ -74,6 +92,11 @@ func (repo *teamRepository) Add(ctx context.Context, team domain.Team, tx pgx.Tx
 		return domain.Team{}, err
 	}
 
+	err = mirrorMembership(ctx, tx, team.ID, team.CreatorID, team.Memberships[0].RoleID)
+	if err != nil {
+		return domain.Team{}, err
+	}
+
 	return team, nil
 }
 
@@ -81,7 +104,7 @@ func (repo *teamRepository) Update(ctx context.Context, team domain.Team, tx pgx
 
 	query := `
 		UPDATE teams
-		SET name = $2, description = $3, is_personal = $4, avatar_url = $5, creator_id = $6, updated_at = $7
+		SET name = $2, description = $3, is_personal = $4, avatar_url = $5, creator_id = $6, is_discoverable = $7, updated_at = $8
 		WHERE id = $1	
 	`
 
@@ -94,6 +117,7 @@ func (repo *teamRepository) Update(ctx context.Context, team domain.Team, tx pgx
 		team.IsPersonal,
 		team.AvatarURL,
 		team.CreatorID,
+		team.IsDiscoverable,
 		team.UpdatedAt,
 	)
 
@@ -102,9 +126,14 @@ func (repo *teamRepository) Update(ctx context.Context, team domain.Team, tx pgx
 	}
 
 	for _, membership := range team.Memberships {
+		err = deleteExpiredMembership(ctx, tx, team.ID, membership.UserID)
+		if err != nil {
+			return domain.Team{}, err
+		}
+
 		q := `
-			INSERT INTO memberships (id, team_id, user_id, role_id, last_active_at, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			INSERT INTO memberships (id, team_id, user_id, role_id, last_active_at, expires_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 			ON CONFLICT (id) DO NOTHING
 		`
 
@@ -113,9 +142,10 @@ func (repo *teamRepository) Update(ctx context.Context, team domain.Team, tx pgx
 			q,
 			membership.ID,
 			team.ID,
-			team.CreatorID,
+			membership.UserID,
 			membership.RoleID,
 			util.GetTimestampUTC(),
+			membership.ExpiresAt,
 			util.GetTimestampUTC(),
 			util.GetTimestampUTC(),
 		)
@@ -123,6 +153,11 @@ func (repo *teamRepository) Update(ctx context.Context, team domain.Team, tx pgx
 		if err != nil {
 			return domain.Team{}, err
 		}
+
+		err = mirrorMembership(ctx, tx, team.ID, membership.UserID, membership.RoleID)
+		if err != nil {
+			return domain.Team{}, err
+		}
 	}
 
 	return team, nil
@@ -130,7 +165,8 @@ func (repo *teamRepository) Update(ctx context.Context, team domain.Team, tx pgx
 
 func (repo *teamRepository) Get(ctx context.Context, id uuid.UUID) (domain.Team, error) {
 	query := `
-		SELECT id, name, description, is_personal, avatar_url, creator_id, created_at, updated_at
+		SELECT id, name, description, is_personal, avatar_url, creator_id, organization_id, parent_id, inheritance,
+			is_discoverable, COALESCE(plan, ''), archived_at, purge_at, created_at, updated_at
 		FROM teams
 		WHERE id = $1
 	`
@@ -148,6 +184,13 @@ func (repo *teamRepository) Get(ctx context.Context, id uuid.UUID) (domain.Team,
 		&team.IsPersonal,
 		&team.AvatarURL,
 		&team.CreatorID,
+		&team.OrganizationID,
+		&team.ParentID,
+		&team.Inheritance,
+		&team.IsDiscoverable,
+		&team.Plan,
+		&team.ArchivedAt,
+		&team.PurgeAt,
 		&team.CreatedAt,
 		&team.UpdatedAt,
 	)
@@ -161,3 +204,399 @@ func (repo *teamRepository) Get(ctx context.Context, id uuid.UUID) (domain.Team,
 
 	return team, nil
 }
+
+// SetParent stores the parent and the inheritance rule of the team and mirrors them as relation tuples.
+func (repo *teamRepository) SetParent(ctx context.Context, team domain.Team, tx pgx.Tx) error {
+	query := `
+		UPDATE teams
+		SET parent_id = $2, inheritance = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, team.ID, team.ParentID, team.Inheritance, team.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return mirrorTeamParent(ctx, tx, team)
+}
+
+// Ancestors returns the ids of the ancestors of the team ordered from its parent up to the root.
+func (repo *teamRepository) Ancestors(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT t.parent_id AS id, 1 AS depth
+			FROM teams t
+			WHERE t.id = $1 AND t.parent_id IS NOT NULL
+			UNION ALL
+			SELECT t.parent_id, a.depth + 1
+			FROM teams t
+			JOIN ancestors a ON a.id = t.id
+			WHERE t.parent_id IS NOT NULL AND a.depth <= $2
+		)
+		SELECT id FROM ancestors ORDER BY depth
+	`
+
+	rows, err := repo.pool.Query(ctx, query, id, domain.MaxTeamDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ancestors []uuid.UUID
+	for rows.Next() {
+		var ancestorID uuid.UUID
+		if err := rows.Scan(&ancestorID); err != nil {
+			return nil, err
+		}
+		ancestors = append(ancestors, ancestorID)
+	}
+
+	return ancestors, rows.Err()
+}
+
+// Height returns how many levels of sub-teams are nested below the team, 0 when it has no sub-team.
+func (repo *teamRepository) Height(ctx context.Context, id uuid.UUID) (int, error) {
+	query := `
+		WITH RECURSIVE descendants AS (
+			SELECT t.id, 1 AS depth
+			FROM teams t
+			WHERE t.parent_id = $1
+			UNION ALL
+			SELECT t.id, d.depth + 1
+			FROM teams t
+			JOIN descendants d ON t.parent_id = d.id
+			WHERE d.depth <= $2
+		)
+		SELECT COALESCE(MAX(depth), 0) FROM descendants
+	`
+
+	var height int
+	err := repo.pool.QueryRow(ctx, query, id, domain.MaxTeamDepth).Scan(&height)
+	if err != nil {
+		return 0, err
+	}
+
+	return height, nil
+}
+
+// accessibleTeams resolves every team the user can access with the effective role on each of them.
+// A team is accessible through a membership that has not expired, through the owner or admin role in the organization owning it,
+// or through one of its ancestors whose roles flow down according to the inheritance rule of each sub-team.
+const accessibleTeams = `
+	WITH RECURSIVE access AS (
+		SELECT m.team_id, r.name::text AS role, $2::text AS source, m.last_active_at, 0 AS depth
+		FROM memberships m
+		JOIN roles r ON r.id = m.role_id
+		WHERE m.user_id = $1 AND (m.expires_at IS NULL OR m.expires_at > $14)
+		UNION ALL
+		SELECT t.id, $4::text, $3::text, NULL::timestamp, 0
+		FROM organization_memberships om
+		JOIN roles r ON r.id = om.role_id
+		JOIN teams t ON t.organization_id = om.organization_id
+		WHERE om.user_id = $1 AND r.name::text = ANY($6::text[])
+		UNION ALL
+		SELECT child.id, CASE WHEN a.role = ANY($6::text[]) THEN $4::text ELSE a.role END, $5::text, NULL::timestamp, a.depth + 1
+		FROM access a
+		JOIN teams child ON child.parent_id = a.team_id
+		WHERE a.depth < $7
+			AND ((a.role = ANY($6::text[]) AND child.inheritance <> $9) OR (a.role = $8 AND child.inheritance = $10))
+	),
+	effective AS (
+		SELECT DISTINCT ON (a.team_id) a.team_id, a.role, a.source,
+			MAX(a.last_active_at) OVER (PARTITION BY a.team_id) AS last_active_at
+		FROM access a
+		ORDER BY a.team_id, array_position($11::text[], a.role), a.depth
+	)
+`
+
+func accessibleTeamsArgs(opts domain.TeamOptions) []any {
+	return []any{
+		opts.UserID,
+		domain.AccessSourceTeam,
+		domain.AccessSourceOrganization,
+		domain.Admin,
+		domain.AccessSourceParentTeam,
+		[]string{string(domain.Owner), string(domain.Admin)},
+		domain.MaxTeamDepth,
+		domain.Member,
+		domain.InheritNone,
+		domain.InheritMembers,
+		domain.RolePrecedence.Names(),
+		escapeLike(opts.Name),
+		opts.Archived,
+		util.GetTimestampUTC(),
+	}
+}
+
+// teamConditions filter the accessible teams by name and by archival, see accessibleTeamsArgs
+var teamConditions = []string{
+	`($12 = '' OR t.name ILIKE '%' || $12 || '%' ESCAPE '\')`,
+	"(t.archived_at IS NOT NULL) = $13",
+}
+
+// ListByUser returns the teams the user can access as memberships carrying the effective role and its source,
+// the membership id is only set when the user is a direct member of the team.
+func (repo *teamRepository) ListByUser(ctx context.Context, opts domain.TeamOptions) ([]domain.Membership, error) {
+	query := accessibleTeams + `
+		SELECT m.id, t.id, t.name, t.description, t.is_personal, t.avatar_url,
+			t.creator_id, t.organization_id, t.parent_id, t.inheritance, t.archived_at, t.purge_at,
+			t.created_at, t.updated_at, e.last_active_at, e.role, e.source
+		FROM effective e
+		JOIN teams t ON t.id = e.team_id
+		LEFT JOIN memberships m ON m.team_id = e.team_id AND m.user_id = $1
+	`
+
+	// the teams reached through an organization or a parent team have never been active, they come last
+	page := domain.Page{Limit: opts.Limit, Descending: true, Cursor: opts.Cursor}
+	order := keyset{
+		SortKey:  "COALESCE(e.last_active_at, '0001-01-01'::timestamp)",
+		SortType: "timestamp",
+		IDColumn: "t.id",
+		IDType:   "uuid",
+	}
+
+	query, args, err := order.apply(query, append([]string{}, teamConditions...), accessibleTeamsArgs(opts), page)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []domain.Membership
+	for rows.Next() {
+		var membership domain.Membership
+		var membershipID uuid.NullUUID
+		var lastActiveAt *time.Time
+		team := &membership.Team
+
+		err := rows.Scan(
+			&membershipID,
+			&team.ID,
+			&team.Name,
+			&team.Description,
+			&team.IsPersonal,
+			&team.AvatarURL,
+			&team.CreatorID,
+			&team.OrganizationID,
+			&team.ParentID,
+			&team.Inheritance,
+			&team.ArchivedAt,
+			&team.PurgeAt,
+			&team.CreatedAt,
+			&team.UpdatedAt,
+			&lastActiveAt,
+			&membership.Role.Name,
+			&membership.Source,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		membership.ID = membershipID.UUID
+		membership.TeamID = team.ID
+		membership.UserID = opts.UserID
+		if lastActiveAt != nil {
+			membership.LastActiveAt = *lastActiveAt
+		}
+		memberships = append(memberships, membership)
+	}
+
+	reverse(memberships, page)
+	return memberships, rows.Err()
+}
+
+func (repo *teamRepository) CountByUser(ctx context.Context, opts domain.TeamOptions) (int64, error) {
+	query := accessibleTeams + `
+		SELECT COUNT(*)
+		FROM effective e
+		JOIN teams t ON t.id = e.team_id
+		WHERE ` + strings.Join(teamConditions, " AND ")
+
+	var count int64
+	err := repo.pool.QueryRow(ctx, query, accessibleTeamsArgs(opts)...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Archive stores the archival state of the team, archiving and restoring both go through it.
+func (repo *teamRepository) Archive(ctx context.Context, team domain.Team, tx pgx.Tx) error {
+	query := `
+		UPDATE teams
+		SET archived_at = $2, purge_at = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, team.ID, team.ArchivedAt, team.PurgeAt, team.UpdatedAt)
+	return err
+}
+
+// Delete removes the team for good along with its memberships, invitations and relation tuples,
+// the policies and the ownership transfers of the team are removed by the database.
+func (repo *teamRepository) Delete(ctx context.Context, id uuid.UUID, tx pgx.Tx) error {
+	query := `
+		DELETE FROM relation_tuples
+		WHERE (object_type = $1 AND object_id = $2) OR (subject_type = $1 AND subject_id = $2)
+	`
+
+	_, err := tx.Exec(ctx, query, domain.TeamNamespace, id.String())
+	if err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		`DELETE FROM invitations WHERE team_id = $1`,
+		`DELETE FROM memberships WHERE team_id = $1`,
+		`DELETE FROM teams WHERE id = $1`,
+	} {
+		if _, err := tx.Exec(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListSoleOwned returns the active shared teams in which the user is the only owner.
+func (repo *teamRepository) ListSoleOwned(ctx context.Context, userID uuid.UUID) ([]domain.Team, error) {
+	query := `
+		SELECT t.id, t.name, t.description, t.is_personal, t.avatar_url, t.creator_id, t.organization_id,
+			t.parent_id, t.inheritance, t.archived_at, t.purge_at, t.created_at, t.updated_at
+		FROM teams t
+		JOIN memberships m ON m.team_id = t.id
+		JOIN roles r ON r.id = m.role_id
+		WHERE m.user_id = $1 AND r.name = $2 AND NOT t.is_personal AND t.archived_at IS NULL
+			AND (m.expires_at IS NULL OR m.expires_at > $3)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM memberships o
+				WHERE o.team_id = t.id AND o.user_id <> $1 AND o.role_id = r.id
+					AND (o.expires_at IS NULL OR o.expires_at > $3)
+			)
+	`
+
+	rows, err := repo.pool.Query(ctx, query, userID, domain.Owner, util.GetTimestampUTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []domain.Team
+	for rows.Next() {
+		var team domain.Team
+		err := rows.Scan(
+			&team.ID,
+			&team.Name,
+			&team.Description,
+			&team.IsPersonal,
+			&team.AvatarURL,
+			&team.CreatorID,
+			&team.OrganizationID,
+			&team.ParentID,
+			&team.Inheritance,
+			&team.ArchivedAt,
+			&team.PurgeAt,
+			&team.CreatedAt,
+			&team.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, rows.Err()
+}
+
+func (repo *teamRepository) SetPlan(ctx context.Context, team domain.Team, tx pgx.Tx) error {
+	query := `
+		UPDATE teams
+		SET plan = NULLIF($2, ''), updated_at = $3
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, team.ID, team.Plan, team.UpdatedAt)
+	return err
+}
+
+// teamUsageQuery counts the memberships of the team and its invitations waiting for an answer.
+const teamUsageQuery = `
+	SELECT
+		(SELECT COUNT(m.id) FROM memberships m WHERE m.team_id = $1 AND (m.expires_at IS NULL OR m.expires_at > $3)),
+		(SELECT COUNT(i.id) FROM invitations i WHERE i.team_id = $1 AND i.status = ANY($2) AND i.expires_at > $3)
+`
+
+func (repo *teamRepository) Usage(ctx context.Context, id uuid.UUID) (domain.TeamUsage, error) {
+	var usage domain.TeamUsage
+	err := repo.pool.QueryRow(ctx, teamUsageQuery, id, domain.InvitationStatusesOpen, util.GetTimestampUTC()).Scan(
+		&usage.Members,
+		&usage.PendingInvitations,
+	)
+	return usage, err
+}
+
+// Lock locks the team until the transaction ends, the transactions changing its members or quotas run one at a time.
+func (repo *teamRepository) Lock(ctx context.Context, id uuid.UUID, tx pgx.Tx) error {
+	var locked uuid.UUID
+	err := tx.QueryRow(ctx, `SELECT id FROM teams WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return exception.NewNotFoundException("team not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// LockUsage locks the team until the transaction ends and returns its usage, the members and invitations
+// added by concurrent transactions are counted once they are committed so that no quota is exceeded.
+func (repo *teamRepository) LockUsage(ctx context.Context, id uuid.UUID, tx pgx.Tx) (domain.TeamUsage, error) {
+	err := repo.Lock(ctx, id, tx)
+	if err != nil {
+		return domain.TeamUsage{}, err
+	}
+
+	var usage domain.TeamUsage
+	err = tx.QueryRow(ctx, teamUsageQuery, id, domain.InvitationStatusesOpen, util.GetTimestampUTC()).Scan(
+		&usage.Members,
+		&usage.PendingInvitations,
+	)
+	return usage, err
+}
+
+// mirrorTeamParent keeps the tuples of a sub-team in sync with teams.parent_id and teams.inheritance:
+// team:<id>#parent@team:<parent_id> lets the parent admins administer the team and
+// team:<id>#member@team:<parent_id>#member makes the parent members members of the team.
+func mirrorTeamParent(ctx context.Context, tx pgx.Tx, team domain.Team) error {
+	query := `
+		DELETE FROM relation_tuples
+		WHERE object_type = $1 AND object_id = $2 AND subject_type = $1
+			AND (relation = $3 OR (relation = $4 AND subject_relation = $4))
+	`
+
+	_, err := tx.Exec(ctx, query, domain.TeamNamespace, team.ID.String(), domain.ParentRelation, string(domain.Member))
+	if err != nil || !team.ParentID.Valid || team.Inheritance == domain.InheritNone {
+		return err
+	}
+
+	parentID := team.ParentID.UUID.String()
+	tuple := domain.NewRelationTuple(domain.TeamNamespace, team.ID.String(), domain.ParentRelation, domain.TeamNamespace, parentID, "")
+	if err := Relation.Add(ctx, tuple, tx); err != nil {
+		return err
+	}
+
+	if team.Inheritance != domain.InheritMembers {
+		return nil
+	}
+
+	tuple = domain.NewRelationTuple(domain.TeamNamespace, team.ID.String(), string(domain.Member), domain.TeamNamespace, parentID, string(domain.Member))
+	return Relation.Add(ctx, tuple, tx)
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+type teamDomainRepository struct {
+	pool *pgxpool.Pool
+}
+
+type TeamDomainRepository interface {
+	Add(context.Context, domain.TeamDomain, pgx.Tx) (domain.TeamDomain, error)
+	Verify(context.Context, domain.TeamDomain, pgx.Tx) error
+	Delete(context.Context, ulid.ULID, pgx.Tx) error
+	Get(context.Context, ulid.ULID) (domain.TeamDomain, error)
+	ListByTeam(context.Context, uuid.UUID) ([]domain.TeamDomain, error)
+	ListVerifiedByDomain(context.Context, string) ([]domain.TeamDomain, error)
+}
+
+// teamDomainRepository implements the TeamDomainRepository interface
+func NewTeamDomainRepository(pool *pgxpool.Pool) TeamDomainRepository {
+	return &teamDomainRepository{pool: pool}
+}
+
+const teamDomainColumns = `
+	td.id, td.team_id, t.name, t.is_personal, td.domain, td.role_id, r.name, td.join_mode,
+	td.verification_token, td.verified_at, td.creator_id, td.created_at, td.updated_at
+`
+
+const teamDomainJoins = `
+	FROM team_domains td
+	JOIN teams t ON t.id = td.team_id
+	JOIN roles r ON r.id = td.role_id
+`
+
+func scanTeamDomain(row pgx.Row) (domain.TeamDomain, error) {
+	var teamDomain domain.TeamDomain
+	err := row.Scan(
+		&teamDomain.ID,
+		&teamDomain.TeamID,
+		&teamDomain.Team.Name,
+		&teamDomain.Team.IsPersonal,
+		&teamDomain.Domain,
+		&teamDomain.RoleID,
+		&teamDomain.Role.Name,
+		&teamDomain.JoinMode,
+		&teamDomain.VerificationToken,
+		&teamDomain.VerifiedAt,
+		&teamDomain.CreatorID,
+		&teamDomain.CreatedAt,
+		&teamDomain.UpdatedAt,
+	)
+	teamDomain.Team.ID = teamDomain.TeamID
+	teamDomain.Role.ID = teamDomain.RoleID
+	return teamDomain, err
+}
+
+func (repo *teamDomainRepository) listTeamDomains(ctx context.Context, query string, args ...any) ([]domain.TeamDomain, error) {
+	rows, err := repo.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teamDomains []domain.TeamDomain
+	for rows.Next() {
+		teamDomain, err := scanTeamDomain(rows)
+		if err != nil {
+			return nil, err
+		}
+		teamDomains = append(teamDomains, teamDomain)
+	}
+
+	return teamDomains, rows.Err()
+}
+
+func (repo *teamDomainRepository) Add(ctx context.Context, teamDomain domain.TeamDomain, tx pgx.Tx) (domain.TeamDomain, error) {
+	query := `
+		INSERT INTO team_domains (id, team_id, domain, role_id, join_mode, verification_token, creator_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := tx.Exec(
+		ctx,
+		query,
+		teamDomain.ID,
+		teamDomain.TeamID,
+		teamDomain.Domain,
+		teamDomain.RoleID,
+		teamDomain.JoinMode,
+		teamDomain.VerificationToken,
+		teamDomain.CreatorID,
+		teamDomain.CreatedAt,
+		teamDomain.UpdatedAt,
+	)
+	if err != nil {
+		return domain.TeamDomain{}, err
+	}
+
+	return teamDomain, nil
+}
+
+func (repo *teamDomainRepository) Verify(ctx context.Context, teamDomain domain.TeamDomain, tx pgx.Tx) error {
+	query := `
+		UPDATE team_domains
+		SET verified_at = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, teamDomain.ID, teamDomain.VerifiedAt, teamDomain.UpdatedAt)
+	return err
+}
+
+func (repo *teamDomainRepository) Delete(ctx context.Context, id ulid.ULID, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, `DELETE FROM team_domains WHERE id = $1`, id)
+	return err
+}
+
+func (repo *teamDomainRepository) Get(ctx context.Context, id ulid.ULID) (domain.TeamDomain, error) {
+	query := `SELECT ` + teamDomainColumns + teamDomainJoins + ` WHERE td.id = $1`
+
+	teamDomain, err := scanTeamDomain(repo.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TeamDomain{}, exception.NewNotFoundException("team domain not found")
+		}
+		return domain.TeamDomain{}, err
+	}
+
+	return teamDomain, nil
+}
+
+func (repo *teamDomainRepository) ListByTeam(ctx context.Context, teamID uuid.UUID) ([]domain.TeamDomain, error) {
+	query := `SELECT ` + teamDomainColumns + teamDomainJoins + ` WHERE td.team_id = $1 ORDER BY td.domain`
+	return repo.listTeamDomains(ctx, query, teamID)
+}
+
+// ListVerifiedByDomain returns the claims of the domain verified by the teams that are not archived.
+func (repo *teamDomainRepository) ListVerifiedByDomain(ctx context.Context, name string) ([]domain.TeamDomain, error) {
+	query := `SELECT ` + teamDomainColumns + teamDomainJoins + `
+		WHERE td.domain = $1 AND td.verified_at IS NOT NULL AND t.archived_at IS NULL
+		ORDER BY td.created_at
+	`
+	return repo.listTeamDomains(ctx, query, name)
+}
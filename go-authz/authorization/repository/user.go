@@ -19,9 +19,10 @@ type UserRepository interface {
 	AddBatch(context.Context, domain.Users, pgx.Tx) error
 	Update(context.Context, domain.User, pgx.Tx) (domain.User, error)
 	Get(context.Context, uuid.UUID) (domain.User, error)
-	List(context.Context, int, int) (domain.Users, error)
+	List(context.Context, domain.UserOptions) (domain.Users, error)
 	GetByEmail(context.Context, string) (domain.User, error)
 	GetByUsername(context.Context, string) (domain.User, error)
+	ListVerifiedByEmailDomain(context.Context, string) (domain.Users, error)
 	Count(context.Context) (int64, error)
 }
 
@@ -93,11 +94,19 @@ func (repo *userRepository) Get(ctx context.Context, id uuid.UUID) (domain.User,
 	return user, nil
 }
 
-func (repo *userRepository) List(ctx context.Context, page, pageSize int) (domain.Users, error) {
-	query := "SELECT id, first_name, last_name, email, username, password, phone_number, avatar_url, is_active, verified, provider, created_at, updated_at FROM users WHERE is_active = true LIMIT $1 OFFSET $2"
+func (repo *userRepository) List(ctx context.Context, opts domain.UserOptions) (domain.Users, error) {
+	query := "SELECT id, first_name, last_name, email, username, password, phone_number, avatar_url, is_active, verified, provider, created_at, updated_at FROM users"
+
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	order := keyset{SortKey: "created_at", SortType: "timestamp", IDColumn: "id", IDType: "uuid"}
+
+	query, args, err := order.apply(query, []string{"is_active = true"}, nil, page)
+	if err != nil {
+		return nil, err
+	}
 
 	var users domain.Users
-	rows, err := repo.pool.Query(ctx, query, pageSize, (page-1)*pageSize)
+	rows, err := repo.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +120,9 @@ func (repo *userRepository) List(ctx context.Context, page, pageSize int) (domai
 		}
 		users = append(users, user)
 	}
-	return users, nil
+
+	reverse(users, page)
+	return users, rows.Err()
 }
 
 func (repo *userRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
@@ -136,6 +147,29 @@ func (repo *userRepository) GetByUsername(ctx context.Context, username string)
 	return user, nil
 }
 
+// ListVerifiedByEmailDomain returns the active users with a verified email address of the domain.
+func (repo *userRepository) ListVerifiedByEmailDomain(ctx context.Context, emailDomain string) (domain.Users, error) {
+	query := "SELECT id, first_name, last_name, email, username, password, phone_number, avatar_url, is_active, verified, provider, created_at, updated_at FROM users WHERE LOWER(email) LIKE '%@' || $1 AND verified = true AND is_active = true"
+
+	var users domain.Users
+	rows, err := repo.pool.Query(ctx, query, emailDomain)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user domain.User
+		err := rows.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.Username, &user.Password, &user.PhoneNumber, &user.AvatarURL, &user.IsActive, &user.Verified, &user.Provider, &user.CreatedAt, &user.UpdatedAt)
+		if err != nil {
+			return domain.Users{}, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
 func (repo *userRepository) Count(ctx context.Context) (int64, error) {
 	query := "SELECT COUNT(*) FROM users WHERE is_active = true"
 
@@ -0,0 +1,462 @@
+package handlers
+
+import (
+	"authorization/config"
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/util"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// StartAccessReview takes the memberships of the teams holding the reviewed roles for review and lets the reviewers
+// of every team know. Only the owners of all the teams or an administrator can start a review.
+func StartAccessReview(ctx context.Context, cmd *command.StartAccessReview) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	review, err := domain.NewAccessReview(cmd.Name, cmd.User.ID, cmd.Roles, cmd.Deadline, cmd.AutoRevoke)
+	if err != nil {
+		return err
+	}
+
+	teamIDs := make([]uuid.UUID, 0, len(cmd.TeamIDs))
+	seen := make(map[uuid.UUID]bool, len(cmd.TeamIDs))
+	for _, teamID := range cmd.TeamIDs {
+		if !seen[teamID] {
+			seen[teamID] = true
+			teamIDs = append(teamIDs, teamID)
+		}
+	}
+
+	if len(teamIDs) == 0 {
+		return exception.NewBadRequestException("an access review covers at least one team")
+	} else if len(teamIDs) > domain.MaxAccessReviewTeams {
+		return exception.NewBadRequestException(fmt.Sprintf("an access review covers at most %d teams", domain.MaxAccessReviewTeams))
+	}
+
+	isAdmin := config.AppConfig.IsAdmin(cmd.User.Email)
+	for _, teamID := range teamIDs {
+		team, err := repository.Team.Get(ctx, teamID)
+		if err != nil {
+			return err
+		}
+
+		if team.IsArchived() {
+			return exception.NewForbiddenException(fmt.Sprintf("team with ID %s is archived", team.ID))
+		}
+
+		if !isAdmin {
+			access, err := repository.Role.GetAccess(ctx, team.ID, cmd.User.ID, "member:access-review")
+			if err != nil {
+				return err
+			} else if !access.IsAllowed || access.RoleName != domain.Owner {
+				return exception.NewForbiddenException(fmt.Sprintf("only the owners of team with ID %s can start an access review of it", team.ID))
+			}
+		}
+
+		memberships, err := repository.Membership.ListByRoles(ctx, team.ID, review.ReviewedRoles())
+		if err != nil {
+			return err
+		}
+
+		for _, membership := range memberships {
+			membership.Team = team
+			review.AddItem(membership)
+		}
+	}
+
+	if len(review.Items) == 0 {
+		return exception.NewBadRequestException("the teams have no membership to review")
+	}
+
+	_, err = repository.AccessReview.Add(ctx, review, tx)
+	if err != nil {
+		return err
+	}
+
+	err = notifyAccessReviewers(ctx, review, false)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd.ReviewID = review.ID
+	return nil
+}
+
+// ReviewAccess confirms or revokes a membership under review, a revoked membership is removed from its team right away.
+// The reviewer must be able to review the access of the team members and to remove the member, administrators review
+// as the team owners would. The review is completed once every membership is reviewed.
+func ReviewAccess(ctx context.Context, cmd *command.ReviewAccess) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	err := repository.AccessReview.Lock(ctx, cmd.ReviewID, tx)
+	if err != nil {
+		return err
+	}
+
+	review, err := repository.AccessReview.Get(ctx, cmd.ReviewID)
+	if err != nil {
+		return err
+	}
+
+	if !review.IsOpen() {
+		return exception.NewBadRequestException(fmt.Sprintf("access review with ID %s is already %s", review.ID, review.Status))
+	} else if review.IsOverdue(util.GetTimestampUTC()) {
+		return exception.NewBadRequestException(fmt.Sprintf("the deadline of access review with ID %s has passed", review.ID))
+	}
+
+	var item *domain.AccessReviewItem
+	for i := range review.Items {
+		if review.Items[i].ID == cmd.ItemID {
+			item = &review.Items[i]
+			break
+		}
+	}
+	if item == nil {
+		return exception.NewNotFoundException(fmt.Sprintf("item with ID %s is not found in access review with ID %s", cmd.ItemID, cmd.ReviewID))
+	}
+
+	reviewerRole := domain.Owner
+	if !config.AppConfig.IsAdmin(cmd.User.Email) {
+		access, err := repository.Role.GetAccess(ctx, item.TeamID, cmd.User.ID, "member:access-review")
+		if err != nil {
+			return err
+		} else if !access.IsAllowed {
+			return exception.NewForbiddenException("You are not allowed to review the access of the team members")
+		}
+		reviewerRole = access.RoleName
+	}
+
+	if item.UserID == cmd.User.ID {
+		return exception.NewForbiddenException("You cannot review your own access")
+	}
+
+	err = item.Review(cmd.Decision, cmd.User.ID, cmd.Note)
+	if err != nil {
+		return err
+	}
+
+	if item.Decision == domain.AccessReviewRevoked {
+		err = revokeReviewedMembership(ctx, *item, reviewerRole, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = repository.AccessReview.UpdateItem(ctx, *item, tx)
+	if err != nil {
+		return err
+	}
+
+	review.PendingItems, err = repository.AccessReview.CountPendingItems(ctx, review.ID, tx)
+	if err != nil {
+		return err
+	} else if review.PendingItems > 0 {
+		return tx.Commit(ctx)
+	}
+
+	review.Complete()
+	err = repository.AccessReview.Update(ctx, review, tx)
+	if err != nil {
+		return err
+	}
+
+	err = notifyAccessReviewClosed(review)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SweepAccessReviews reminds the reviewers of the open reviews as their deadline gets closer and closes the reviews
+// past their deadline, it is run periodically by the task scheduler.
+func SweepAccessReviews(ctx context.Context, cmd *command.SweepAccessReviews) error {
+	reviews, err := repository.AccessReview.ListOpen(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := util.GetTimestampUTC()
+	for _, review := range reviews {
+		if review.IsOverdue(now) {
+			err := closeAccessReview(ctx, review.ID)
+			if err != nil {
+				return err
+			}
+
+			cmd.Closed++
+			continue
+		}
+
+		due := review.DueReminders(now, config.AppConfig.AccessReviewReminderOffsets)
+		if due <= review.RemindersSent {
+			continue
+		}
+
+		sent, err := remindAccessReviewers(ctx, review, due)
+		if err != nil {
+			return err
+		} else if sent {
+			cmd.Reminded++
+		}
+	}
+
+	return nil
+}
+
+// remindAccessReviewers reminds the reviewers of the teams with memberships left to review,
+// the reminder is recorded first so that a failed sweep does not remind them twice.
+func remindAccessReviewers(ctx context.Context, review domain.AccessReview, due int) (bool, error) {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return false, txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	review.RemindersSent = due
+	review.UpdatedAt = util.GetTimestampUTC()
+	err := repository.AccessReview.Update(ctx, review, tx)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if review.PendingItems == 0 {
+		return false, nil
+	}
+
+	review, err = repository.AccessReview.Get(ctx, review.ID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, notifyAccessReviewers(ctx, review, true)
+}
+
+// closeAccessReview completes the review past its deadline. The memberships left to review are revoked when
+// the review revokes them automatically, except the ones of the owners so that no team is left without one.
+func closeAccessReview(ctx context.Context, reviewID ulid.ULID) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	err := repository.AccessReview.Lock(ctx, reviewID, tx)
+	if err != nil {
+		return err
+	}
+
+	review, err := repository.AccessReview.Get(ctx, reviewID)
+	if err != nil {
+		return err
+	} else if !review.IsOpen() {
+		return nil
+	}
+
+	for i := range review.Items {
+		item := &review.Items[i]
+		if item.Decision != domain.AccessReviewPending {
+			continue
+		}
+
+		if !review.AutoRevoke {
+			item.Close(domain.AccessReviewUnreviewed, "")
+		} else {
+			err := autoRevokeMembership(ctx, item, tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = repository.AccessReview.UpdateItem(ctx, *item, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	review.PendingItems = 0
+	review.Complete()
+	err = repository.AccessReview.Update(ctx, review, tx)
+	if err != nil {
+		return err
+	}
+
+	err = notifyAccessReviewClosed(review)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func autoRevokeMembership(ctx context.Context, item *domain.AccessReviewItem, tx pgx.Tx) error {
+	membership, err := repository.Membership.Get(ctx, item.MembershipID)
+	var notFound exception.NotFoundException
+	if errors.As(err, &notFound) {
+		item.Close(domain.AccessReviewAutoRevoked, "the membership was already removed")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if membership.Role.Name == domain.Owner {
+		item.Close(domain.AccessReviewUnreviewed, "the owners are not revoked automatically")
+		return nil
+	}
+
+	err = removeReviewedMembership(ctx, membership, tx)
+	if err != nil {
+		return err
+	}
+
+	item.Close(domain.AccessReviewAutoRevoked, "")
+	return nil
+}
+
+// revokeReviewedMembership removes the membership the reviewer revoked, unless it is already gone.
+func revokeReviewedMembership(ctx context.Context, item domain.AccessReviewItem, reviewerRole domain.RoleType, tx pgx.Tx) error {
+	membership, err := repository.Membership.Get(ctx, item.MembershipID)
+	var notFound exception.NotFoundException
+	if errors.As(err, &notFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = domain.CurrentRoleHierarchy().CanRemove(reviewerRole, membership.Role.Name)
+	if err != nil {
+		return err
+	}
+
+	err = removeReviewedMembership(ctx, membership, tx)
+	if err != nil {
+		return err
+	}
+
+	return ensureTeamOwner(ctx, membership.TeamID, tx)
+}
+
+func removeReviewedMembership(ctx context.Context, membership domain.Membership, tx pgx.Tx) error {
+	err := repository.Membership.ResetLastActiveTeam(ctx, membership.UserID, membership.TeamID, tx)
+	if err != nil {
+		return err
+	}
+
+	return repository.Membership.Delete(ctx, membership.ID, tx)
+}
+
+// notifyAccessReviewers emails the owners of the teams with memberships left to review, once per owner
+// along with the names of their teams.
+func notifyAccessReviewers(ctx context.Context, review domain.AccessReview, reminder bool) error {
+	pending := make(map[uuid.UUID]string)
+	for _, item := range review.Items {
+		if item.Decision == domain.AccessReviewPending {
+			pending[item.TeamID] = item.Team.Name
+		}
+	}
+
+	reviewers := make(map[string]domain.User)
+	teams := make(map[string][]string)
+	for teamID, teamName := range pending {
+		owners, err := repository.Membership.ListByRoles(ctx, teamID, domain.RoleTypes{domain.Owner})
+		if err != nil {
+			return err
+		}
+
+		for _, owner := range owners {
+			reviewers[owner.User.Email] = owner.User
+			teams[owner.User.Email] = append(teams[owner.User.Email], teamName)
+		}
+	}
+
+	subject := fmt.Sprintf("Access review %s: please review the access to your teams", review.Name)
+	if reminder {
+		subject = fmt.Sprintf("Reminder: access review %s is due on %s", review.Name, review.Deadline.Format("January 2, 2006 15:04 MST"))
+	}
+
+	for email, reviewer := range reviewers {
+		sort.Strings(teams[email])
+		data := map[string]interface{}{
+			"ReviewerName": reviewer.FullName(),
+			"ReviewName":   review.Name,
+			"TeamNames":    teams[email],
+			"Deadline":     review.Deadline.Format("January 2, 2006 15:04 MST"),
+			"AutoRevoke":   review.AutoRevoke,
+			"Reminder":     reminder,
+			"EmailTo":      email,
+			"ReviewLink":   fmt.Sprintf("http://localhost:3000/access-reviews/%s", review.ID),
+		}
+
+		emailPayload := worker.Mailer.CreateEmailPayload(worker.AccessReviewTemplate, email, subject, data)
+		if err := worker.Mailer.SendEmail(emailPayload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyAccessReviewClosed sends the outcome of the review to the member who started it.
+func notifyAccessReviewClosed(review domain.AccessReview) error {
+	if !review.CreatorID.Valid {
+		return nil
+	}
+
+	counts := make(map[domain.AccessReviewDecision]int)
+	for _, item := range review.Items {
+		counts[item.Decision]++
+	}
+
+	data := map[string]interface{}{
+		"CreatorName": review.Creator.FullName(),
+		"ReviewName":  review.Name,
+		"Confirmed":   counts[domain.AccessReviewConfirmed],
+		"Revoked":     counts[domain.AccessReviewRevoked] + counts[domain.AccessReviewAutoRevoked],
+		"Unreviewed":  counts[domain.AccessReviewUnreviewed],
+		"EmailTo":     review.Creator.Email,
+		"ReviewLink":  fmt.Sprintf("http://localhost:3000/access-reviews/%s", review.ID),
+	}
+
+	emailPayload := worker.Mailer.CreateEmailPayload(worker.AccessReviewClosedTemplate, review.Creator.Email, fmt.Sprintf("Access review %s is completed", review.Name), data)
+	return worker.Mailer.SendEmail(emailPayload)
+}
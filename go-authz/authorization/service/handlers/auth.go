@@ -66,12 +66,25 @@ func LoginByGoogle(ctx context.Context, cmd *command.LoginByGoogle) error {
 			return err
 		}
 
+		sendInvitations, err := JoinTeamsByEmailDomain(ctx, user, tx)
+		if err != nil {
+			return err
+		}
+
 		errSendMail := sendWelcomeEmail(user)
 		if errSendMail != nil {
 			return errSendMail
 		}
 
-		tx.Commit(ctx)
+		err = tx.Commit(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = sendInvitations()
+		if err != nil {
+			return err
+		}
 	}
 
 	accessToken, refreshToken, err := user.GenerateTokens()
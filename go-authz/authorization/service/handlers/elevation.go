@@ -0,0 +1,524 @@
+package handlers
+
+import (
+	"authorization/config"
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+// RequestElevation asks for a higher role in the team for a few hours. The request is approved right away when
+// the auto-approval policies of the team allow it, the owners of the team are asked to review it otherwise.
+func RequestElevation(ctx context.Context, cmd *command.RequestElevation) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	if team.IsArchived() {
+		return exception.NewForbiddenException(fmt.Sprintf("team with ID %s is archived", team.ID))
+	}
+
+	access, err := repository.Role.GetAccess(ctx, team.ID, cmd.User.ID, "member:elevation-request")
+	if err != nil {
+		return err
+	} else if !access.IsAllowed {
+		return exception.NewForbiddenException("You are not allowed to request a higher role in the team")
+	}
+
+	// the role inherited from a parent team cannot be elevated, only a direct membership can
+	membership, err := repository.Membership.GetByUser(ctx, team.ID, cmd.User.ID)
+	if err != nil {
+		return err
+	}
+
+	hierarchy := domain.CurrentRoleHierarchy()
+	if hierarchy.IsGuest(membership.Role.Name) {
+		return exception.NewForbiddenException("guests cannot request a higher role")
+	}
+
+	// an elevation never goes beyond the roles an owner can give
+	if err := hierarchy.CanAssign(domain.Owner, cmd.Role); err != nil {
+		return err
+	} else if !hierarchy.Outranks(cmd.Role, membership.Role.Name) {
+		return exception.NewBadRequestException(fmt.Sprintf("the %s role is not higher than your %s role", cmd.Role, membership.Role.Name))
+	}
+
+	role, err := repository.Role.GetByName(ctx, cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	open, err := repository.Elevation.Count(ctx, domain.ElevationOptions{
+		MembershipID: membership.ID,
+		Statuses:     []domain.ElevationStatus{domain.ElevationPending, domain.ElevationActive},
+	})
+	if err != nil {
+		return err
+	} else if open > 0 {
+		return exception.NewBadRequestException("you already have an elevation request pending or active in the team")
+	}
+
+	request, err := domain.NewElevationRequest(membership, role, cmd.Hours, cmd.Justification, config.AppConfig.ElevationMaxDuration)
+	if err != nil {
+		return err
+	}
+	request.User = cmd.User
+	request.Team = team
+	request.Record(domain.ElevationActionRequested, uuid.NullUUID{UUID: cmd.User.ID, Valid: true}, "")
+
+	autoApproved, err := isElevationAutoApproved(ctx, request, cmd.User, team)
+	if err != nil {
+		return err
+	}
+
+	if autoApproved {
+		err = request.Approve(uuid.NullUUID{})
+		if err != nil {
+			return err
+		}
+		request.Record(domain.ElevationActionAutoApproved, uuid.NullUUID{}, "approved by the policies of the team")
+	}
+
+	_, err = repository.Elevation.Add(ctx, request, tx)
+	if err != nil {
+		return err
+	}
+
+	if autoApproved {
+		err = activateElevation(ctx, &request, membership, tx)
+		if err != nil {
+			return err
+		}
+
+		err = notifyElevationReviewed(team, request)
+	} else {
+		err = notifyElevationRequest(ctx, team, request, cmd.User)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd.RequestID = request.ID
+	return nil
+}
+
+// ApproveElevation gives the requested role to the member until the elevation ends.
+// The reviewer must be able to assign the role and cannot approve a request of their own.
+func ApproveElevation(ctx context.Context, cmd *command.ApproveElevation) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	request, team, access, err := getElevationForReview(ctx, cmd.TeamID, cmd.RequestID, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	if team.IsArchived() {
+		return exception.NewForbiddenException(fmt.Sprintf("team with ID %s is archived", team.ID))
+	}
+
+	if request.UserID == cmd.User.ID {
+		return exception.NewForbiddenException("You cannot approve your own elevation request")
+	}
+
+	if err := domain.CurrentRoleHierarchy().CanAssign(access.RoleName, request.ToRole.Name); err != nil {
+		return err
+	}
+
+	membership, err := repository.Membership.Get(ctx, request.MembershipID)
+	if err != nil {
+		return err
+	}
+
+	// the role of the member may have been changed since the request was made
+	if membership.RoleID != request.FromRoleID {
+		return exception.NewBadRequestException(fmt.Sprintf("the member does not hold the %s role anymore", request.FromRole.Name))
+	}
+
+	err = request.Approve(uuid.NullUUID{UUID: cmd.User.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	err = repository.Elevation.Update(ctx, request, tx)
+	if err != nil {
+		return err
+	}
+
+	event := request.Record(domain.ElevationActionApproved, uuid.NullUUID{UUID: cmd.User.ID, Valid: true}, "")
+	err = repository.Elevation.AddEvent(ctx, event, tx)
+	if err != nil {
+		return err
+	}
+
+	err = activateElevation(ctx, &request, membership, tx)
+	if err != nil {
+		return err
+	}
+
+	err = notifyElevationReviewed(team, request)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DenyElevation turns the request down, the member can request a higher role again.
+func DenyElevation(ctx context.Context, cmd *command.DenyElevation) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	request, team, _, err := getElevationForReview(ctx, cmd.TeamID, cmd.RequestID, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	err = request.Deny(cmd.User.ID)
+	if err != nil {
+		return err
+	}
+
+	err = repository.Elevation.Update(ctx, request, tx)
+	if err != nil {
+		return err
+	}
+
+	event := request.Record(domain.ElevationActionDenied, uuid.NullUUID{UUID: cmd.User.ID, Valid: true}, "")
+	err = repository.Elevation.AddEvent(ctx, event, tx)
+	if err != nil {
+		return err
+	}
+
+	err = notifyElevationReviewed(team, request)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CancelElevation withdraws the pending request or gives up an active elevation before it ends,
+// only the member who made the request can.
+func CancelElevation(ctx context.Context, cmd *command.CancelElevation) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	request, err := repository.Elevation.Get(ctx, cmd.RequestID)
+	if err != nil {
+		return err
+	}
+
+	if request.TeamID != cmd.TeamID || request.UserID != cmd.User.ID {
+		return exception.NewNotFoundException(fmt.Sprintf("elevation request with ID %s is not found in team with ID %s", cmd.RequestID, cmd.TeamID))
+	}
+
+	actorID := uuid.NullUUID{UUID: cmd.User.ID, Valid: true}
+	if !request.IsActive() {
+		err = request.Cancel()
+		if err != nil {
+			return err
+		}
+
+		err = repository.Elevation.Update(ctx, request, tx)
+		if err != nil {
+			return err
+		}
+
+		event := request.Record(domain.ElevationActionCancelled, actorID, "")
+		err = repository.Elevation.AddEvent(ctx, event, tx)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit(ctx)
+	}
+
+	_, err = endElevation(ctx, &request, domain.ElevationActionRelinquished, actorID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	// RevertElevation skips the elevations that are not active anymore, a task left behind is harmless
+	if err := worker.Scheduler.CancelElevationRevert(request.ID); err != nil {
+		log.Warn().Caller().Err(err).Str("request_id", request.ID.String()).Msg("Failed to cancel the revert of a relinquished elevation")
+	}
+
+	return nil
+}
+
+// RevertElevation gives the member its role back once the elevation is over, it is run by the task scheduler.
+// An elevation that is not active anymore is skipped.
+func RevertElevation(ctx context.Context, cmd *command.RevertElevation) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	request, err := repository.Elevation.Get(ctx, cmd.RequestID)
+	var notFound exception.NotFoundException
+	if errors.As(err, &notFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if !request.IsActive() {
+		return nil
+	}
+
+	membership, err := endElevation(ctx, &request, domain.ElevationActionReverted, uuid.NullUUID{}, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the member left or was removed from the team during the elevation
+	if membership == nil {
+		return nil
+	}
+
+	return notifyElevationEnded(request, *membership)
+}
+
+// activateElevation gives the requested role to the membership and schedules the end of the elevation.
+// The task is scheduled before the transaction is committed so that an active elevation always ends,
+// RevertElevation skips it when the transaction is not committed.
+func activateElevation(ctx context.Context, request *domain.ElevationRequest, membership domain.Membership, tx pgx.Tx) error {
+	membership.RoleID = request.ToRoleID
+	_, err := repository.Membership.Update(ctx, membership, tx)
+	if err != nil {
+		return err
+	}
+
+	return worker.Scheduler.ScheduleElevationRevert(request.ID, *request.EndsAt)
+}
+
+// endElevation closes the active elevation and gives the membership its role back, unless its role was changed
+// during the elevation. The membership is returned with the role it holds, nil when it does not exist anymore.
+func endElevation(ctx context.Context, request *domain.ElevationRequest, action domain.ElevationAction, actorID uuid.NullUUID, tx pgx.Tx) (*domain.Membership, error) {
+	err := request.End()
+	if err != nil {
+		return nil, err
+	}
+
+	var note string
+	membership, err := repository.Membership.Get(ctx, request.MembershipID)
+	var notFound exception.NotFoundException
+	removed := errors.As(err, &notFound)
+	if removed {
+		note = "the member is not in the team anymore"
+	} else if err != nil {
+		return nil, err
+	} else if membership.RoleID != request.ToRoleID {
+		note = fmt.Sprintf("the role was changed to %s during the elevation and is left as is", membership.Role.Name)
+	} else {
+		membership.RoleID = request.FromRoleID
+		membership.Role = request.FromRole
+		_, err = repository.Membership.Update(ctx, membership, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = repository.Elevation.Update(ctx, *request, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	event := request.Record(action, actorID, note)
+	err = repository.Elevation.AddEvent(ctx, event, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if removed {
+		return nil, nil
+	}
+	return &membership, nil
+}
+
+// isElevationAutoApproved evaluates the auto-approval policies of the team against the request.
+// The request is approved when at least one policy is written for it and every policy that applies allows it,
+// a policy that fails to evaluate leaves the request to the owners.
+func isElevationAutoApproved(ctx context.Context, request domain.ElevationRequest, user domain.User, team domain.Team) (bool, error) {
+	policies, err := repository.Policy.ListActiveByPermission(ctx, team.ID, domain.ElevationAutoApprovePermission)
+	if err != nil {
+		return false, err
+	}
+
+	// the policies on any permission alone do not approve anything
+	written := false
+	for _, policy := range policies {
+		if policy.Permission == domain.ElevationAutoApprovePermission {
+			written = true
+			break
+		}
+	}
+	if !written {
+		return false, nil
+	}
+
+	attributes := domain.ElevationPolicyAttributes(request, user, team)
+	for _, policy := range policies {
+		allowed, err := policy.Evaluate(attributes)
+		if err != nil {
+			log.Warn().Caller().Err(err).Str("policy_id", policy.ID.String()).Msg("Failed to evaluate policy")
+			return false, nil
+		} else if !allowed {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// getElevationForReview returns the request made in the team when the user can review the requests of the team.
+func getElevationForReview(ctx context.Context, teamID uuid.UUID, requestID ulid.ULID, user domain.User) (domain.ElevationRequest, domain.Team, domain.Access, error) {
+	request, err := repository.Elevation.Get(ctx, requestID)
+	if err != nil {
+		return domain.ElevationRequest{}, domain.Team{}, domain.Access{}, err
+	}
+
+	if request.TeamID != teamID {
+		return domain.ElevationRequest{}, domain.Team{}, domain.Access{}, exception.NewNotFoundException(fmt.Sprintf("elevation request with ID %s is not found in team with ID %s", requestID, teamID))
+	}
+
+	access, err := repository.Role.GetAccess(ctx, teamID, user.ID, "member:elevation-review")
+	if err != nil {
+		return domain.ElevationRequest{}, domain.Team{}, domain.Access{}, err
+	} else if !access.IsAllowed {
+		return domain.ElevationRequest{}, domain.Team{}, domain.Access{}, exception.NewForbiddenException("You are not allowed to review the elevation requests of the team")
+	}
+
+	team, err := repository.Team.Get(ctx, teamID)
+	if err != nil {
+		return domain.ElevationRequest{}, domain.Team{}, domain.Access{}, err
+	}
+
+	return request, team, access, nil
+}
+
+func notifyElevationRequest(ctx context.Context, team domain.Team, request domain.ElevationRequest, requester domain.User) error {
+	owners, err := repository.Membership.ListByRoles(ctx, team.ID, domain.RoleTypes{domain.Owner})
+	if err != nil {
+		return err
+	}
+
+	for _, owner := range owners {
+		data := map[string]interface{}{
+			"RequesterName":  requester.FullName(),
+			"RequesterEmail": requester.Email,
+			"Role":           string(request.ToRole.Name),
+			"CurrentRole":    string(request.FromRole.Name),
+			"Hours":          int(request.Duration.Hours()),
+			"Justification":  request.Justification,
+			"TeamName":       team.Name,
+			"EmailTo":        owner.User.Email,
+			"RequestLink":    fmt.Sprintf("http://localhost:3000/teams/%s/elevation-requests/%s", team.ID, request.ID),
+		}
+
+		emailPayload := worker.Mailer.CreateEmailPayload(worker.ElevationRequestTemplate, owner.User.Email, fmt.Sprintf("%s requested the %s role in the %s team", requester.FullName(), request.ToRole.Name, team.Name), data)
+		if err := worker.Mailer.SendEmail(emailPayload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func notifyElevationReviewed(team domain.Team, request domain.ElevationRequest) error {
+	data := map[string]interface{}{
+		"UserName":     request.User.FullName(),
+		"TeamName":     team.Name,
+		"Approved":     request.IsActive(),
+		"Ended":        false,
+		"Role":         string(request.ToRole.Name),
+		"PreviousRole": string(request.FromRole.Name),
+		"EmailTo":      request.User.Email,
+		"TeamLink":     fmt.Sprintf("http://localhost:3000/teams/%s", team.ID),
+	}
+	if request.EndsAt != nil {
+		data["EndsAt"] = request.EndsAt.Format("January 2, 2006 15:04 MST")
+	}
+
+	subject := fmt.Sprintf("Your request for the %s role in the %s team was denied", request.ToRole.Name, team.Name)
+	if request.IsActive() {
+		subject = fmt.Sprintf("You are %s in the %s team until %s", request.ToRole.Name, team.Name, data["EndsAt"])
+	}
+
+	emailPayload := worker.Mailer.CreateEmailPayload(worker.ElevationReviewedTemplate, request.User.Email, subject, data)
+	return worker.Mailer.SendEmail(emailPayload)
+}
+
+func notifyElevationEnded(request domain.ElevationRequest, membership domain.Membership) error {
+	data := map[string]interface{}{
+		"UserName":     request.User.FullName(),
+		"TeamName":     request.Team.Name,
+		"Approved":     true,
+		"Ended":        true,
+		"Role":         string(request.ToRole.Name),
+		"PreviousRole": string(membership.Role.Name),
+		"EmailTo":      request.User.Email,
+		"TeamLink":     fmt.Sprintf("http://localhost:3000/teams/%s", request.TeamID),
+	}
+
+	emailPayload := worker.Mailer.CreateEmailPayload(worker.ElevationReviewedTemplate, request.User.Email, fmt.Sprintf("Your %s role in the %s team has ended", request.ToRole.Name, request.Team.Name), data)
+	return worker.Mailer.SendEmail(emailPayload)
+}
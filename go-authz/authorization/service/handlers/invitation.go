@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	"authorization/config"
 	"authorization/controller/exception"
 	"authorization/domain"
 	"authorization/domain/command"
 	"authorization/infrastructure/persistence"
 	"authorization/infrastructure/worker"
 	"authorization/repository"
+	"authorization/util"
 	"context"
 	"fmt"
+	"strings"
+
+	"github.com/oklog/ulid/v2"
 )
 
 // create inviteSendInvitationMember function
@@ -30,76 +35,31 @@ func SendInvitation(ctx context.Context, cmd *command.SendInvitation) error {
 		return exception.NewForbiddenException(fmt.Sprintf("you can't invite a member to personal team with ID %s", cmd.TeamID))
 	}
 
-	membershipOpts := domain.MembershipOptions{
-		TeamID:       cmd.TeamID,
-		IsSelectUser: true,
-		IsSelectRole: true,
-	}
-	memberships, err := repository.Membership.List(ctx, membershipOpts)
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.Sender.ID, "member:invite")
 	if err != nil {
 		return err
 	}
 
-	emailSet := make(map[string]bool)
-
-	for _, membership := range memberships {
-		emailSet[membership.User.Email] = true
-	}
-
+	hierarchy := domain.CurrentRoleHierarchy()
 	for _, invitee := range cmd.Invitees {
-		if emailSet[invitee.Email] {
-			continue
-		}
-
-		role, err := repository.Role.GetByName(ctx, invitee.Role)
-		if err != nil {
+		if err := hierarchy.CanAssign(access.RoleName, invitee.Role); err != nil {
 			return err
 		}
+	}
 
-		inviteesOpts := domain.InvitationOptions{
-			Email:    invitee.Email,
-			TeamID:   cmd.TeamID,
-			RoleID:   role.ID,
-			Statuses: []domain.InvitationStatus{domain.InvitationStatusPending, domain.InvitationStatusSent},
-		}
-
-		activeInvitees, err := repository.Invitation.List(ctx, inviteesOpts)
-		if err != nil {
-			return err
-		}
-
-		if len(activeInvitees) > 0 {
-			continue
-		}
-
-		invitation := domain.NewInvitation(invitee.Email, domain.InvitationStatusPending, cmd.TeamID, cmd.Sender.ID, role.ID)
-		_, err = repository.Invitation.Add(ctx, invitation, tx)
-		if err != nil {
-			return err
-		}
-
-		data := map[string]interface{}{
-			"SenderName":     cmd.Sender.FullName(),
-			"TeamName":       team.Name,
-			"EmailTo":        invitation.Email,
-			"InvitationLink": fmt.Sprintf("http://localhost:3000/invitation/%s", invitation.ID),
-			"InvitationID":   invitation.ID,
-		}
-
-		emailPayload := worker.Mailer.CreateEmailPayload(worker.InvitationTemplate, invitation.Email, fmt.Sprintf("Invitation to join %s team", team.Name), data)
-
-		// send email
-		errSendMail := worker.Mailer.SendEmail(emailPayload)
-		if errSendMail != nil {
-			return errSendMail
-		}
+	// existing members and invitees already invited are reported as skipped
+	results, emails, err := inviteRows(ctx, team, cmd.Sender, access.RoleName, cmd.Invitees, tx)
+	if err != nil {
+		return err
 	}
 
 	err = tx.Commit(ctx)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	cmd.Results = results
+	return sendInvitationEmails(emails)
 }
 
 // create ResendInvitation function
@@ -127,25 +87,44 @@ func ResendInvitation(ctx context.Context, cmd *command.ResendInvitation) error
 		return err
 	}
 
-	err = invitation.ResendUpdate()
+	if invitation.TeamID != cmd.TeamID {
+		return exception.NewNotFoundException(fmt.Sprintf("invitation with ID %s is not found in team with ID %s", invitation.ID, cmd.TeamID))
+	}
+
+	role, err := repository.Role.Get(ctx, invitation.RoleID)
 	if err != nil {
 		return err
 	}
 
-	data := map[string]interface{}{
-		"SenderName":     cmd.Sender.FullName(),
-		"TeamName":       team.Name,
-		"EmailTo":        invitation.Email,
-		"InvitationLink": fmt.Sprintf("http://localhost:3000/invitation/%s", invitation.ID),
-		"InvitationID":   invitation.ID,
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.Sender.ID, "member:invite")
+	if err != nil {
+		return err
 	}
 
-	emailPayload := worker.Mailer.CreateEmailPayload(worker.InvitationTemplate, invitation.Email, fmt.Sprintf("Invitation to join %s team", team.Name), data)
+	err = domain.CurrentRoleHierarchy().CanAssign(access.RoleName, role.Name)
+	if err != nil {
+		return err
+	}
+
+	settings, err := repository.InvitationSettings.Get(ctx, team.ID)
+	if err != nil {
+		return err
+	}
+
+	err = invitation.ResendUpdate(settings.ExpiresIn)
+	if err != nil {
+		return err
+	}
+
+	// the invitation opened again waits for an answer along with the pending ones
+	plan, usage, err := lockTeamPlan(ctx, team.ID, tx)
+	if err != nil {
+		return err
+	}
 
-	// send email
-	errSendMail := worker.Mailer.SendEmail(emailPayload)
-	if errSendMail != nil {
-		return errSendMail
+	err = plan.CheckInvitations(usage, 1)
+	if err != nil {
+		return err
 	}
 
 	err = repository.Invitation.Update(ctx, invitation, tx)
@@ -157,7 +136,15 @@ func ResendInvitation(ctx context.Context, cmd *command.ResendInvitation) error
 	if err != nil {
 		return err
 	}
-	return nil
+
+	// the reminders of the expired invitation are replaced by the ones of its new expiry
+	err = cancelInvitationReminders(invitation)
+	if err != nil {
+		return err
+	}
+
+	// the email is sent once the invitation is committed so the mailer marks the renewed invitation sent
+	return sendInvitationEmail(cmd.Sender.FullName(), team, invitation, settings)
 }
 
 // create DeleteInvitation function
@@ -195,7 +182,7 @@ func DeleteInvitation(ctx context.Context, cmd *command.DeleteInvitation) error
 	if err != nil {
 		return err
 	}
-	return nil
+	return cancelInvitationReminders(invitation)
 }
 
 // create UpdateInvitationStatus function
@@ -221,7 +208,7 @@ func UpdateInvitationStatus(ctx context.Context, cmd *command.UpdateInvitationSt
 	}
 
 	// check if user is team owner
-	if invitation.Email != cmd.User.Email {
+	if !strings.EqualFold(invitation.Email, cmd.User.Email) {
 		return exception.NewForbiddenException(fmt.Sprintf("you are not invited to join this team with ID %s", invitation.TeamID))
 	}
 
@@ -229,22 +216,40 @@ func UpdateInvitationStatus(ctx context.Context, cmd *command.UpdateInvitationSt
 		return exception.NewForbiddenException(fmt.Sprintf("invitation with ID %s is not active anymore", invitation.ID))
 	}
 
+	if team.IsArchived() {
+		return exception.NewForbiddenException(fmt.Sprintf("team with ID %s is archived", team.ID))
+	}
+
+	// the invitee can only answer the invitation, the other statuses are set by the system
+	status := domain.InvitationStatus(cmd.Status)
+	if status != domain.InvitationStatusAccepted && status != domain.InvitationStatusDeclined {
+		return exception.NewBadRequestException(fmt.Sprintf("invalid invitation status %s, it must be accepted or declined", cmd.Status))
+	}
+
 	// update invitation
-	invitation.Status = domain.InvitationStatus(cmd.Status)
-	invitation.IsActive = false
+	err = invitation.Transition(status)
+	if err != nil {
+		return err
+	}
+
 	err = repository.Invitation.Update(ctx, invitation, tx)
 	if err != nil {
 		return err
 	}
 
 	// add team member
-	if cmd.Status == string(domain.InvitationStatusAccepted) {
+	if status == domain.InvitationStatusAccepted {
 		role, err := repository.Role.Get(ctx, invitation.RoleID)
 		if err != nil {
 			return err
 		}
 
-		team.AddMembership(invitation.TeamID, cmd.User.ID, role.ID)
+		err = reserveSeat(ctx, team.ID, tx)
+		if err != nil {
+			return err
+		}
+
+		team.AddMembership(invitation.TeamID, cmd.User.ID, role.ID, invitation.MembershipExpiresAt)
 		_, err = repository.Team.Update(ctx, team, tx)
 		if err != nil {
 			return err
@@ -256,5 +261,359 @@ func UpdateInvitationStatus(ctx context.Context, cmd *command.UpdateInvitationSt
 		return err
 	}
 
+	return cancelInvitationReminders(invitation)
+}
+
+// ClaimInvitation accepts the invitation the token was signed for on behalf of the user, whatever its email address.
+// The owners and admins of the team are told when the invitation is claimed by an address it was not sent to.
+func ClaimInvitation(ctx context.Context, cmd *command.ClaimInvitation) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	invitationID, err := util.ValidateInvitationToken(cmd.Token, config.AppConfig.AccessTokenPublicKey)
+	if err != nil {
+		return exception.NewBadRequestException("invalid or expired invitation token")
+	}
+
+	invitation, err := repository.Invitation.Get(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+
+	team, err := repository.Team.Get(ctx, invitation.TeamID)
+	if err != nil {
+		return err
+	}
+
+	if team.IsArchived() {
+		return exception.NewForbiddenException(fmt.Sprintf("team with ID %s is archived", team.ID))
+	}
+
+	if _, err := repository.Membership.GetByUser(ctx, team.ID, cmd.User.ID); err == nil {
+		return exception.NewBadRequestException(fmt.Sprintf("you are already a member of team with ID %s", team.ID))
+	}
+
+	err = invitation.Transition(domain.InvitationStatusAccepted)
+	if err != nil {
+		return err
+	}
+
+	err = repository.Invitation.Update(ctx, invitation, tx)
+	if err != nil {
+		return err
+	}
+
+	err = reserveSeat(ctx, team.ID, tx)
+	if err != nil {
+		return err
+	}
+
+	team.AddMembership(team.ID, cmd.User.ID, invitation.RoleID, invitation.MembershipExpiresAt)
+	_, err = repository.Team.Update(ctx, team, tx)
+	if err != nil {
+		return err
+	}
+
+	cmd.TeamID = team.ID
+	cmd.EmailMismatch = !strings.EqualFold(invitation.Email, cmd.User.Email)
+	if cmd.EmailMismatch {
+		err = notifyInvitationClaimed(ctx, team, invitation, cmd.User)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+	return cancelInvitationReminders(invitation)
+}
+
+// RevokeInvitations withdraws the invitations of the team that are not answered yet, all of them or none.
+// The actor must be able to assign the role of each invitation, as when sending it.
+func RevokeInvitations(ctx context.Context, cmd *command.RevokeInvitations) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.User.ID, "member:invite")
+	if err != nil {
+		return err
+	}
+
+	invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{TeamID: cmd.TeamID, IDs: cmd.InvitationIDs})
+	if err != nil {
+		return err
+	}
+
+	found := make(map[ulid.ULID]bool, len(invitations))
+	for _, invitation := range invitations {
+		found[invitation.ID] = true
+	}
+	for _, id := range cmd.InvitationIDs {
+		if !found[id] {
+			return exception.NewNotFoundException(fmt.Sprintf("invitation with ID %s is not found in team with ID %s", id, cmd.TeamID))
+		}
+	}
+
+	hierarchy := domain.CurrentRoleHierarchy()
+	for _, invitation := range invitations {
+		if invitation.Status == domain.InvitationStatusAccepted || invitation.Status == domain.InvitationStatusDeclined {
+			return exception.NewBadRequestException(fmt.Sprintf("invitation with ID %s is already %s", invitation.ID, invitation.Status))
+		}
+
+		if err := hierarchy.CanAssign(access.RoleName, invitation.Role.Name); err != nil {
+			return err
+		}
+
+		err = repository.Invitation.Delete(ctx, invitation.ID, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, invitation := range invitations {
+		if err := cancelInvitationReminders(invitation); err != nil {
+			return err
+		}
+	}
+
+	cmd.Revoked = len(invitations)
 	return nil
 }
+
+// ChangeInvitationRole changes the role an open invitation grants, the actor must be able to assign both roles.
+func ChangeInvitationRole(ctx context.Context, cmd *command.ChangeInvitationRole) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	invitation, err := repository.Invitation.Get(ctx, cmd.InvitationID)
+	if err != nil {
+		return err
+	}
+
+	if invitation.TeamID != cmd.TeamID {
+		return exception.NewNotFoundException(fmt.Sprintf("invitation with ID %s is not found in team with ID %s", invitation.ID, cmd.TeamID))
+	}
+
+	if !invitation.IsActive {
+		return exception.NewBadRequestException(fmt.Sprintf("invitation with ID %s is not active anymore", invitation.ID))
+	}
+
+	currentRole, err := repository.Role.Get(ctx, invitation.RoleID)
+	if err != nil {
+		return err
+	}
+
+	role, err := repository.Role.GetByName(ctx, cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.User.ID, "member:invite")
+	if err != nil {
+		return err
+	}
+
+	hierarchy := domain.CurrentRoleHierarchy()
+	if err := hierarchy.CanAssign(access.RoleName, currentRole.Name); err != nil {
+		return err
+	}
+	if err := hierarchy.CanAssign(access.RoleName, role.Name); err != nil {
+		return err
+	}
+
+	invitation.RoleID = role.ID
+	err = repository.Invitation.Update(ctx, invitation, tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ExpireInvitations expires the open invitations past their expiry, it is run periodically by the task scheduler.
+func ExpireInvitations(ctx context.Context, cmd *command.ExpireInvitations) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	expired, err := repository.Invitation.ExpireStale(ctx, util.GetTimestampUTC(), tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd.Expired = expired
+	return nil
+}
+
+// invitationEmail is the email of an invitation created in a transaction, it is only sent once the transaction
+// is committed so the mailer never sees an invitation that does not exist yet.
+type invitationEmail struct {
+	senderName string
+	team       domain.Team
+	invitation domain.Invitation
+	settings   domain.InvitationSettings
+}
+
+func sendInvitationEmails(emails []invitationEmail) error {
+	for _, email := range emails {
+		if err := sendInvitationEmail(email.senderName, email.team, email.invitation, email.settings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendInvitationEmail emails the invitation, its link carries a token signed for the invitation so the invitee
+// can claim it whatever the address of the account it signs in with.
+// The reminders of the team settings are scheduled along with it.
+func sendInvitationEmail(senderName string, team domain.Team, invitation domain.Invitation, settings domain.InvitationSettings) error {
+	token, err := util.CreateInvitationToken(invitation.ID, invitation.ExpiresAt, config.AppConfig.AccessTokenPrivateKey)
+	if err != nil {
+		return err
+	}
+	link := fmt.Sprintf("http://localhost:3000/invitation/%s?token=%s", invitation.ID, token)
+
+	data := map[string]interface{}{
+		"SenderName":     senderName,
+		"TeamName":       team.Name,
+		"EmailTo":        invitation.Email,
+		"InvitationLink": link,
+		"InvitationID":   invitation.ID,
+	}
+
+	emailPayload := worker.Mailer.CreateEmailPayload(worker.InvitationTemplate, invitation.Email, fmt.Sprintf("Invitation to join %s team", team.Name), data)
+	if err := worker.Mailer.SendEmail(emailPayload); err != nil {
+		return err
+	}
+
+	for i, at := range settings.ReminderTimes(invitation.ExpiresAt) {
+		data := map[string]interface{}{
+			"SenderName":     senderName,
+			"TeamName":       team.Name,
+			"EmailTo":        invitation.Email,
+			"InvitationLink": link,
+			"ExpiresAt":      invitation.ExpiresAt.Format("January 2, 2006 15:04 MST"),
+		}
+
+		emailPayload := worker.Mailer.CreateEmailPayload(worker.InvitationReminderTemplate, invitation.Email, fmt.Sprintf("Your invitation to join %s team expires soon", team.Name), data)
+		if err := worker.Mailer.ScheduleEmail(emailPayload, at, invitationReminderTaskID(invitation, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cancelInvitationReminders cancels the reminders of the invitation not sent yet, it is called once the invitation
+// is answered or deleted. The reminders are cancelled up to the maximum since the team settings may have changed.
+func cancelInvitationReminders(invitation domain.Invitation) error {
+	for i := 0; i < domain.MaxInvitationReminders; i++ {
+		if err := worker.Mailer.CancelEmail(invitationReminderTaskID(invitation, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func invitationReminderTaskID(invitation domain.Invitation, n int) string {
+	return fmt.Sprintf("invitation:remind:%s:%d", invitation.ID, n)
+}
+
+func notifyInvitationClaimed(ctx context.Context, team domain.Team, invitation domain.Invitation, claimer domain.User) error {
+	managers, err := repository.Membership.ListByRoles(ctx, team.ID, domain.RoleTypes{domain.Owner, domain.Admin})
+	if err != nil {
+		return err
+	}
+
+	for _, manager := range managers {
+		data := map[string]interface{}{
+			"ClaimerName":  claimer.FullName(),
+			"ClaimerEmail": claimer.Email,
+			"InvitedEmail": invitation.Email,
+			"TeamName":     team.Name,
+			"EmailTo":      manager.User.Email,
+			"TeamLink":     fmt.Sprintf("http://localhost:3000/teams/%s", team.ID),
+		}
+
+		emailPayload := worker.Mailer.CreateEmailPayload(worker.InvitationClaimedTemplate, manager.User.Email, fmt.Sprintf("The invitation of %s to the %s team was claimed by %s", invitation.Email, team.Name, claimer.Email), data)
+		if err := worker.Mailer.SendEmail(emailPayload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateInvitationSettings sets how long the invitations of the team are valid and when their reminders are sent.
+// The invitations already sent keep their expiry and reminders.
+func UpdateInvitationSettings(ctx context.Context, cmd *command.UpdateInvitationSettings) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	} else if team.IsPersonal {
+		return exception.NewForbiddenException(fmt.Sprintf("you can't invite a member to personal team with ID %s", cmd.TeamID))
+	}
+
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.User.ID, "team:update")
+	if err != nil {
+		return err
+	}
+
+	if !access.IsAllowed {
+		return exception.NewForbiddenException("You are not allowed to update the invitation settings of the team")
+	}
+
+	settings, err := domain.NewInvitationSettings(cmd.TeamID, cmd.ExpiresInHours, cmd.ReminderOffsetsHours)
+	if err != nil {
+		return err
+	}
+
+	err = repository.InvitationSettings.Save(ctx, settings, tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
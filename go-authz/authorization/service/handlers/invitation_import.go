@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"authorization/config"
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/badoux/checkmail"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// ImportInvitations invites the rows of a bulk upload. Up to the sync limit the rows are processed within the request
+// and the report is ready once it returns, the larger uploads are processed by a background task.
+func ImportInvitations(ctx context.Context, cmd *command.ImportInvitations) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	} else if team.IsPersonal {
+		return exception.NewForbiddenException(fmt.Sprintf("you can't invite a member to personal team with ID %s", cmd.TeamID))
+	}
+
+	if len(cmd.Invitees) == 0 {
+		return exception.NewBadRequestException("there is no invitee to invite")
+	} else if len(cmd.Invitees) > config.AppConfig.InvitationImportMaxRows {
+		return exception.NewBadRequestException(fmt.Sprintf("an import can't have more than %d invitees", config.AppConfig.InvitationImportMaxRows))
+	}
+
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.Sender.ID, "member:invite")
+	if err != nil {
+		return err
+	} else if !access.IsAllowed {
+		return exception.NewForbiddenException("you are not allowed to invite members to the team")
+	}
+
+	invitationImport := domain.NewInvitationImport(cmd.TeamID, cmd.Sender.ID, cmd.Invitees)
+	cmd.ImportID = invitationImport.ID
+	cmd.Async = len(cmd.Invitees) > config.AppConfig.InvitationImportSyncLimit
+
+	var emails []invitationEmail
+	if !cmd.Async {
+		var results []domain.InvitationRowResult
+		results, emails, err = inviteRows(ctx, team, cmd.Sender, access.RoleName, invitationImport.Invitees, tx)
+		if err != nil {
+			return err
+		}
+		invitationImport.Complete(results)
+	}
+
+	_, err = repository.InvitationImport.Add(ctx, invitationImport, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Async {
+		return worker.Scheduler.EnqueueInvitationImport(invitationImport.ID)
+	}
+
+	return sendInvitationEmails(emails)
+}
+
+// ProcessInvitationImport processes a bulk invitation left to the background task, a completed import is left alone
+// so a task delivered twice invites nobody twice. An import that cannot be processed is marked failed.
+func ProcessInvitationImport(ctx context.Context, cmd *command.ProcessInvitationImport) error {
+	invitationImport, err := repository.InvitationImport.Get(ctx, cmd.ImportID)
+	if err != nil {
+		return err
+	} else if invitationImport.Status != domain.InvitationImportPending {
+		return nil
+	}
+
+	emails, err := processInvitationImport(ctx, invitationImport)
+	if err != nil {
+		if failErr := failInvitationImport(ctx, invitationImport); failErr != nil {
+			log.Error().Caller().Err(failErr).Str("import_id", invitationImport.ID.String()).Msg("Failed to mark the invitation import failed")
+		}
+		return err
+	}
+
+	return sendInvitationEmails(emails)
+}
+
+func processInvitationImport(ctx context.Context, invitationImport domain.InvitationImport) ([]invitationEmail, error) {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return nil, txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, invitationImport.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	sender, err := repository.User.Get(ctx, invitationImport.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the rows are checked against the role the sender holds now, not when the file was uploaded
+	access, err := repository.Role.GetAccess(ctx, team.ID, sender.ID, "member:invite")
+	if err != nil {
+		return nil, err
+	}
+
+	results, emails, err := inviteRows(ctx, team, sender, access.RoleName, invitationImport.Invitees, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitationImport.Complete(results)
+	err = repository.InvitationImport.Update(ctx, invitationImport, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// failInvitationImport marks the import failed in a transaction of its own, the one processing it is rolled back.
+func failInvitationImport(ctx context.Context, invitationImport domain.InvitationImport) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	invitationImport.Fail()
+	err := repository.InvitationImport.Update(ctx, invitationImport, tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// inviteRows invites each row the sender, holding the actor role, can invite and reports what became of every row.
+// The members of the team, the invitees with an invitation pending and the rows repeating an email are skipped,
+// so are the rows beyond the pending invitations the plan of the team allows.
+// The emails of the invitations are returned to be sent once the transaction is committed.
+func inviteRows(ctx context.Context, team domain.Team, sender domain.User, actorRole domain.RoleType, rows []domain.InvitationRow, tx pgx.Tx) ([]domain.InvitationRowResult, []invitationEmail, error) {
+	plan, usage, err := lockTeamPlan(ctx, team.ID, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	memberships, err := repository.Membership.List(ctx, domain.MembershipOptions{TeamID: team.ID, IsSelectUser: true})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	members := make(map[string]bool, len(memberships))
+	for _, membership := range memberships {
+		members[strings.ToLower(membership.User.Email)] = true
+	}
+
+	pending, err := repository.Invitation.List(ctx, domain.InvitationOptions{
+		TeamID:   team.ID,
+		Statuses: domain.InvitationStatusesOpen,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invited := make(map[string]bool, len(pending))
+	for _, invitation := range pending {
+		invited[strings.ToLower(invitation.Email)] = true
+	}
+
+	settings, err := repository.InvitationSettings.Get(ctx, team.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hierarchy := domain.CurrentRoleHierarchy()
+	roles := make(map[domain.RoleType]domain.Role)
+	results := make([]domain.InvitationRowResult, 0, len(rows))
+	emails := make([]invitationEmail, 0)
+
+	for i, row := range rows {
+		result := domain.InvitationRowResult{Row: i + 1, Email: strings.TrimSpace(row.Email), Role: row.Role}
+		email := strings.ToLower(result.Email)
+
+		var roleErr error
+		role, known := roles[row.Role]
+		if !known {
+			role, roleErr = repository.Role.GetByName(ctx, row.Role)
+			var notFound exception.NotFoundException
+			if roleErr != nil && !errors.As(roleErr, &notFound) {
+				return nil, nil, roleErr
+			} else if roleErr == nil {
+				roles[row.Role] = role
+			}
+		}
+
+		switch {
+		case checkmail.ValidateFormat(result.Email) != nil:
+			result.Status, result.Reason = domain.InvitationRowInvalid, "email address is not valid"
+		case roleErr != nil:
+			result.Status, result.Reason = domain.InvitationRowInvalid, fmt.Sprintf("role %s does not exist", row.Role)
+		case members[email]:
+			result.Status = domain.InvitationRowSkippedMember
+		case invited[email]:
+			result.Status = domain.InvitationRowSkippedInvited
+		case domain.ValidateMembershipExpiry(row.MembershipExpiresAt) != nil:
+			result.Status, result.Reason = domain.InvitationRowInvalid, "membership expiry must be in the future"
+		default:
+			if err := hierarchy.CanAssign(actorRole, row.Role); err != nil {
+				result.Status, result.Reason = domain.InvitationRowInvalid, err.Error()
+				break
+			}
+
+			if err := plan.CheckInvitations(usage, 1); err != nil {
+				result.Status, result.Reason = domain.InvitationRowSkippedQuota, err.Error()
+				break
+			}
+
+			invitation := domain.NewInvitation(result.Email, domain.InvitationStatusPending, team.ID, sender.ID, role.ID, settings.ExpiresIn)
+			invitation.MembershipExpiresAt = row.MembershipExpiresAt
+			_, err = repository.Invitation.Add(ctx, invitation, tx)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			emails = append(emails, invitationEmail{senderName: sender.FullName(), team: team, invitation: invitation, settings: settings})
+			invited[email] = true
+			usage.PendingInvitations++
+			result.Status = domain.InvitationRowCreated
+		}
+
+		results = append(results, result)
+	}
+
+	return results, emails, nil
+}
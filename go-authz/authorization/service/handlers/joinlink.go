@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/repository"
+	"authorization/util"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// joinLinkDefaultTTL is how long a join link created without expiry stays valid
+const joinLinkDefaultTTL = time.Hour * 24 * 7
+
+// CreateJoinLink creates a shareable link to join the team, the creator can only hand out the roles it may assign.
+func CreateJoinLink(ctx context.Context, cmd *command.CreateJoinLink) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	} else if team.IsPersonal {
+		return exception.NewForbiddenException(fmt.Sprintf("you can't create a join link to personal team with ID %s", cmd.TeamID))
+	}
+
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.User.ID, "member:invite")
+	if err != nil {
+		return err
+	} else if !access.IsAllowed {
+		return exception.NewForbiddenException("you are not allowed to create join links to the team")
+	}
+
+	err = domain.CurrentRoleHierarchy().CanAssign(access.RoleName, cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	role, err := repository.Role.GetByName(ctx, cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := util.GetTimestampUTC().Add(joinLinkDefaultTTL)
+	if cmd.ExpiresAt != nil {
+		expiresAt = cmd.ExpiresAt.UTC()
+	}
+
+	link, err := domain.NewJoinLink(cmd.TeamID, cmd.User.ID, role.ID, cmd.MaxUses, expiresAt, cmd.EmailDomain)
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.JoinLink.Add(ctx, link, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd.LinkID = link.ID
+	return nil
+}
+
+// RevokeJoinLink stops a join link from being redeemed, the members who already joined through it stay.
+func RevokeJoinLink(ctx context.Context, cmd *command.RevokeJoinLink) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	link, err := repository.JoinLink.Get(ctx, cmd.LinkID)
+	if err != nil {
+		return err
+	}
+
+	if link.TeamID != cmd.TeamID {
+		return exception.NewNotFoundException(fmt.Sprintf("join link with ID %s is not found in team with ID %s", link.ID, cmd.TeamID))
+	}
+
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.User.ID, "member:invite")
+	if err != nil {
+		return err
+	} else if !access.IsAllowed {
+		return exception.NewForbiddenException("you are not allowed to revoke the join links of the team")
+	}
+
+	err = link.Revoke()
+	if err != nil {
+		return err
+	}
+
+	err = repository.JoinLink.Revoke(ctx, link, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RedeemJoinLink makes the user a member of the team of the link with the role of the link.
+func RedeemJoinLink(ctx context.Context, cmd *command.RedeemJoinLink) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	link, err := repository.JoinLink.GetByToken(ctx, cmd.Token)
+	if err != nil {
+		return err
+	}
+
+	err = link.CanRedeem(cmd.User)
+	if err != nil {
+		return err
+	}
+
+	team, err := repository.Team.Get(ctx, link.TeamID)
+	if err != nil {
+		return err
+	}
+
+	if team.IsArchived() {
+		return exception.NewForbiddenException(fmt.Sprintf("team with ID %s is archived", team.ID))
+	}
+
+	_, err = repository.Membership.GetByUser(ctx, link.TeamID, cmd.User.ID)
+	if err == nil {
+		return exception.NewBadRequestException("you are already a member of the team")
+	}
+	var notFound exception.NotFoundException
+	if !errors.As(err, &notFound) {
+		return err
+	}
+
+	err = reserveSeat(ctx, team.ID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = repository.JoinLink.Use(ctx, link.ID, tx)
+	if err != nil {
+		return err
+	}
+
+	team.AddMembership(link.TeamID, cmd.User.ID, link.RoleID, nil)
+	_, err = repository.Team.Update(ctx, team, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd.TeamID = team.ID
+	return nil
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// RequestToJoin asks to join a discoverable team, the owners and admins of the team are told of the request.
+func RequestToJoin(ctx context.Context, cmd *command.RequestToJoin) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	// a team that is not discoverable is not found by the users who are not members
+	if !team.IsDiscoverable || team.IsPersonal {
+		return exception.NewNotFoundException("team not found")
+	}
+
+	if team.IsArchived() {
+		return exception.NewForbiddenException(fmt.Sprintf("team with ID %s is archived", team.ID))
+	}
+
+	_, err = repository.Membership.GetByUser(ctx, team.ID, cmd.User.ID)
+	if err == nil {
+		return exception.NewBadRequestException("you are already a member of the team")
+	}
+	var notFound exception.NotFoundException
+	if !errors.As(err, &notFound) {
+		return err
+	}
+
+	pending, err := repository.JoinRequest.Count(ctx, domain.JoinRequestOptions{
+		TeamID:   team.ID,
+		UserID:   cmd.User.ID,
+		Statuses: []domain.JoinRequestStatus{domain.JoinRequestPending},
+	})
+	if err != nil {
+		return err
+	} else if pending > 0 {
+		return exception.NewBadRequestException("you already requested to join the team")
+	}
+
+	request, err := domain.NewJoinRequest(team.ID, cmd.User.ID, cmd.Message)
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.JoinRequest.Add(ctx, request, tx)
+	if err != nil {
+		return err
+	}
+
+	err = notifyJoinRequest(ctx, team, request, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd.RequestID = request.ID
+	return nil
+}
+
+// ApproveJoinRequest adds the user of the request to the team with the role, a member when no role is given.
+// The reviewer must be able to assign the role, as when inviting.
+func ApproveJoinRequest(ctx context.Context, cmd *command.ApproveJoinRequest) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	request, team, access, err := getJoinRequestForReview(ctx, cmd.TeamID, cmd.RequestID, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	if team.IsArchived() {
+		return exception.NewForbiddenException(fmt.Sprintf("team with ID %s is archived", team.ID))
+	}
+
+	roleName := cmd.Role
+	if roleName == "" {
+		roleName = domain.Member
+	}
+
+	if err := domain.CurrentRoleHierarchy().CanAssign(access.RoleName, roleName); err != nil {
+		return err
+	}
+
+	role, err := repository.Role.GetByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	// the user may have joined the team in the meantime, through an invitation or a join link
+	_, err = repository.Membership.GetByUser(ctx, team.ID, request.UserID)
+	if err == nil {
+		return exception.NewBadRequestException(fmt.Sprintf("user with ID %s is already a member of the team", request.UserID))
+	}
+	var notFound exception.NotFoundException
+	if !errors.As(err, &notFound) {
+		return err
+	}
+
+	err = request.Approve(cmd.User.ID, role)
+	if err != nil {
+		return err
+	}
+
+	err = repository.JoinRequest.Update(ctx, request, tx)
+	if err != nil {
+		return err
+	}
+
+	err = reserveSeat(ctx, team.ID, tx)
+	if err != nil {
+		return err
+	}
+
+	team.AddMembership(team.ID, request.UserID, role.ID, nil)
+	_, err = repository.Team.Update(ctx, team, tx)
+	if err != nil {
+		return err
+	}
+
+	err = notifyJoinRequestReviewed(team, request)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DenyJoinRequest turns the request down, the user can request to join the team again.
+func DenyJoinRequest(ctx context.Context, cmd *command.DenyJoinRequest) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	request, team, _, err := getJoinRequestForReview(ctx, cmd.TeamID, cmd.RequestID, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	err = request.Deny(cmd.User.ID)
+	if err != nil {
+		return err
+	}
+
+	err = repository.JoinRequest.Update(ctx, request, tx)
+	if err != nil {
+		return err
+	}
+
+	err = notifyJoinRequestReviewed(team, request)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CancelJoinRequest withdraws the pending request, only the user who made it can.
+func CancelJoinRequest(ctx context.Context, cmd *command.CancelJoinRequest) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	request, err := repository.JoinRequest.Get(ctx, cmd.RequestID)
+	if err != nil {
+		return err
+	}
+
+	if request.TeamID != cmd.TeamID || request.UserID != cmd.User.ID {
+		return exception.NewNotFoundException(fmt.Sprintf("join request with ID %s is not found in team with ID %s", cmd.RequestID, cmd.TeamID))
+	}
+
+	err = request.Cancel()
+	if err != nil {
+		return err
+	}
+
+	err = repository.JoinRequest.Update(ctx, request, tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// getJoinRequestForReview returns the request made to the team when the user can review the requests of the team.
+func getJoinRequestForReview(ctx context.Context, teamID uuid.UUID, requestID ulid.ULID, user domain.User) (domain.JoinRequest, domain.Team, domain.Access, error) {
+	request, err := repository.JoinRequest.Get(ctx, requestID)
+	if err != nil {
+		return domain.JoinRequest{}, domain.Team{}, domain.Access{}, err
+	}
+
+	if request.TeamID != teamID {
+		return domain.JoinRequest{}, domain.Team{}, domain.Access{}, exception.NewNotFoundException(fmt.Sprintf("join request with ID %s is not found in team with ID %s", requestID, teamID))
+	}
+
+	access, err := repository.Role.GetAccess(ctx, teamID, user.ID, "member:invite")
+	if err != nil {
+		return domain.JoinRequest{}, domain.Team{}, domain.Access{}, err
+	} else if !access.IsAllowed {
+		return domain.JoinRequest{}, domain.Team{}, domain.Access{}, exception.NewForbiddenException("You are not allowed to review the join requests of the team")
+	}
+
+	team, err := repository.Team.Get(ctx, teamID)
+	if err != nil {
+		return domain.JoinRequest{}, domain.Team{}, domain.Access{}, err
+	}
+
+	return request, team, access, nil
+}
+
+func notifyJoinRequest(ctx context.Context, team domain.Team, request domain.JoinRequest, requester domain.User) error {
+	managers, err := repository.Membership.ListByRoles(ctx, team.ID, domain.RoleTypes{domain.Owner, domain.Admin})
+	if err != nil {
+		return err
+	}
+
+	for _, manager := range managers {
+		data := map[string]interface{}{
+			"RequesterName":  requester.FullName(),
+			"RequesterEmail": requester.Email,
+			"Message":        request.Message,
+			"TeamName":       team.Name,
+			"EmailTo":        manager.User.Email,
+			"RequestLink":    fmt.Sprintf("http://localhost:3000/teams/%s/join-requests", team.ID),
+		}
+
+		emailPayload := worker.Mailer.CreateEmailPayload(worker.JoinRequestTemplate, manager.User.Email, fmt.Sprintf("%s requested to join the %s team", requester.FullName(), team.Name), data)
+		if err := worker.Mailer.SendEmail(emailPayload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func notifyJoinRequestReviewed(team domain.Team, request domain.JoinRequest) error {
+	data := map[string]interface{}{
+		"UserName": request.User.FullName(),
+		"TeamName": team.Name,
+		"Approved": request.Status == domain.JoinRequestApproved,
+		"Role":     string(request.Role.Name),
+		"EmailTo":  request.User.Email,
+		"TeamLink": fmt.Sprintf("http://localhost:3000/teams/%s", team.ID),
+	}
+
+	emailPayload := worker.Mailer.CreateEmailPayload(worker.JoinRequestReviewedTemplate, request.User.Email, fmt.Sprintf("Your request to join the %s team was %s", team.Name, request.Status), data)
+	return worker.Mailer.SendEmail(emailPayload)
+}
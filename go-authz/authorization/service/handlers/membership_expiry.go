@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/util"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+// membershipExpiryBatch is the number of expired memberships the sweeper loads at once.
+const membershipExpiryBatch = 100
+
+// SetMembershipExpiry makes a membership time-bound or permanent again, only a member who could remove the membership can.
+func SetMembershipExpiry(ctx context.Context, cmd *command.SetMembershipExpiry) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	membership, err := repository.Membership.Get(ctx, cmd.MembershipID)
+	if err != nil {
+		return err
+	}
+
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.User.ID, "member:delete")
+	if err != nil {
+		return err
+	} else if !access.IsAllowed {
+		return exception.NewForbiddenException("you are not allowed to manage the members of the team")
+	}
+
+	err = membership.Validation(cmd.User.ID, cmd.TeamID, access.RoleName, "")
+	if err != nil {
+		return err
+	}
+
+	err = membership.SetExpiry(cmd.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.Membership.Update(ctx, membership, tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ExpireMemberships removes the memberships past their expiry and lets both the former members and the owners
+// and admins of their team know, it is run periodically by the task scheduler.
+// The expired memberships grant nothing already, the sweep only cleans them up along with their relation tuples.
+func ExpireMemberships(ctx context.Context, cmd *command.ExpireMemberships) error {
+	for {
+		memberships, err := repository.Membership.ListExpired(ctx, util.GetTimestampUTC(), membershipExpiryBatch)
+		if err != nil {
+			return err
+		}
+
+		removedAny := false
+		for _, membership := range memberships {
+			removed, err := removeExpiredMembership(ctx, membership)
+			if err != nil {
+				return err
+			} else if !removed {
+				continue
+			}
+
+			removedAny = true
+			cmd.Expired++
+			err = notifyMembershipExpired(ctx, membership)
+			if err != nil {
+				return err
+			}
+		}
+
+		// the memberships left behind are listed again, a batch where none could be removed would loop forever
+		if len(memberships) < membershipExpiryBatch || !removedAny {
+			return nil
+		}
+	}
+}
+
+// removeExpiredMembership deletes the membership unless it is already gone, e.g. removed while the sweep was running.
+// The membership of the last owner of the team is kept and flagged so the team is never left without an owner.
+func removeExpiredMembership(ctx context.Context, membership domain.Membership) (bool, error) {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return false, txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	err := repository.Membership.ResetLastActiveTeam(ctx, membership.UserID, membership.TeamID, tx)
+	if err != nil {
+		return false, err
+	}
+
+	err = repository.Membership.Delete(ctx, membership.ID, tx)
+	var notFound exception.NotFoundException
+	if errors.As(err, &notFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if membership.Role.Name == domain.Owner {
+		err = ensureTeamOwner(ctx, membership.TeamID, tx)
+		var forbidden exception.ForbiddenException
+		if errors.As(err, &forbidden) {
+			log.Warn().Str("team_id", membership.TeamID.String()).Str("membership_id", membership.ID.String()).Msg("Expired membership of the last owner of the team is kept")
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+func notifyMembershipExpired(ctx context.Context, membership domain.Membership) error {
+	expiresAt := membership.ExpiresAt.Format("January 2, 2006 15:04 MST")
+
+	data := map[string]interface{}{
+		"MemberName": membership.User.FullName(),
+		"Role":       string(membership.Role.Name),
+		"TeamName":   membership.Team.Name,
+		"ExpiresAt":  expiresAt,
+		"EmailTo":    membership.User.Email,
+	}
+
+	emailPayload := worker.Mailer.CreateEmailPayload(worker.MembershipEndedTemplate, membership.User.Email, fmt.Sprintf("Your access to the %s team has ended", membership.Team.Name), data)
+	if err := worker.Mailer.SendEmail(emailPayload); err != nil {
+		return err
+	}
+
+	managers, err := repository.Membership.ListByRoles(ctx, membership.TeamID, domain.RoleTypes{domain.Owner, domain.Admin})
+	if err != nil {
+		return err
+	}
+
+	for _, manager := range managers {
+		data := map[string]interface{}{
+			"MemberName":  membership.User.FullName(),
+			"MemberEmail": membership.User.Email,
+			"Role":        string(membership.Role.Name),
+			"TeamName":    membership.Team.Name,
+			"ExpiresAt":   expiresAt,
+			"EmailTo":     manager.User.Email,
+			"TeamLink":    fmt.Sprintf("http://localhost:3000/teams/%s", membership.TeamID),
+		}
+
+		emailPayload := worker.Mailer.CreateEmailPayload(worker.MembershipExpiredTemplate, manager.User.Email, fmt.Sprintf("The membership of %s in the %s team has expired", membership.User.FullName(), membership.Team.Name), data)
+		if err := worker.Mailer.SendEmail(emailPayload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/repository"
+	"context"
+	"errors"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func CreateOrganization(ctx context.Context, cmd *command.CreateOrganization) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	ownerRole, err := repository.Role.GetByName(ctx, domain.Owner)
+	if err != nil {
+		return err
+	}
+
+	organization := domain.NewOrganization(cmd.User, ownerRole.ID, cmd.Name, cmd.Description)
+	_, err = repository.Organization.Add(ctx, organization, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd.OrganizationID = organization.ID
+	return nil
+}
+
+func UpdateOrganization(ctx context.Context, cmd *command.UpdateOrganization) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	organization, err := repository.Organization.Get(ctx, cmd.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	organization.Update(map[string]any{
+		"name":        cmd.Name,
+		"description": cmd.Description,
+	})
+
+	_, err = repository.Organization.Update(ctx, organization, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func AddOrganizationMember(ctx context.Context, cmd *command.AddOrganizationMember) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	if cmd.Role == domain.Owner {
+		return exception.NewForbiddenException("You cannot add a member as owner")
+	}
+
+	user, err := repository.User.GetByEmail(ctx, cmd.Email)
+	if err != nil {
+		return exception.NewNotFoundException("user with the email is not found")
+	}
+
+	_, err = repository.Organization.GetMembershipByUser(ctx, cmd.OrganizationID, user.ID)
+	if err == nil {
+		return exception.NewBadRequestException("user is already a member of the organization")
+	}
+	var notFound exception.NotFoundException
+	if !errors.As(err, &notFound) {
+		return err
+	}
+
+	role, err := repository.Role.GetByName(ctx, cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	membership := domain.NewOrganizationMembership(cmd.OrganizationID, user.ID, role.ID)
+	_, err = repository.Organization.AddMembership(ctx, membership, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ChangeOrganizationMemberRole(ctx context.Context, cmd *command.ChangeOrganizationMemberRole) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	membership, err := repository.Organization.GetMembership(ctx, cmd.MembershipID)
+	if err != nil {
+		return err
+	}
+
+	err = membership.Validation(cmd.User.ID, cmd.OrganizationID, cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	role, err := repository.Role.GetByName(ctx, cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	membership.RoleID = role.ID
+	membership.Role = role
+
+	_, err = repository.Organization.UpdateMembership(ctx, membership, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DeleteOrganizationMember(ctx context.Context, cmd *command.DeleteOrganizationMember) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	membership, err := repository.Organization.GetMembership(ctx, cmd.MembershipID)
+	if err != nil {
+		return err
+	}
+
+	err = membership.Validation(cmd.User.ID, cmd.OrganizationID, "")
+	if err != nil {
+		return err
+	}
+
+	err = repository.Organization.DeleteMembership(ctx, cmd.MembershipID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AttachOrganizationTeam moves a team under the organization, only the team owner can hand the team over.
+func AttachOrganizationTeam(ctx context.Context, cmd *command.AttachOrganizationTeam) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	if team.IsPersonal {
+		return exception.NewBadRequestException("personal team cannot belong to an organization")
+	}
+
+	if team.OrganizationID.Valid {
+		return exception.NewBadRequestException("team already belongs to an organization")
+	}
+
+	access, err := repository.Role.GetAccess(ctx, team.ID, cmd.User.ID, "team:update")
+	if err != nil {
+		return err
+	}
+
+	if access.RoleName != domain.Owner {
+		return exception.NewForbiddenException("only the team owner can move the team to an organization")
+	}
+
+	err = repository.Organization.SetTeamOrganization(ctx, team.ID, uuid.NullUUID{UUID: cmd.OrganizationID, Valid: true}, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DetachOrganizationTeam(ctx context.Context, cmd *command.DetachOrganizationTeam) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	if !team.OrganizationID.Valid || team.OrganizationID.UUID != cmd.OrganizationID {
+		return exception.NewNotFoundException("team does not belong to the organization")
+	}
+
+	err = repository.Organization.SetTeamOrganization(ctx, team.ID, uuid.NullUUID{}, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
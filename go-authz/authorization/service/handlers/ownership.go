@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ProposeOwnershipTransfer asks a member to become an owner of the team, the member confirms it through the emailed link.
+func ProposeOwnershipTransfer(ctx context.Context, cmd *command.ProposeOwnershipTransfer) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	} else if team.IsPersonal {
+		return exception.NewForbiddenException(fmt.Sprintf("you can't transfer the ownership of personal team with ID %s", cmd.TeamID))
+	}
+
+	proposer, err := repository.Membership.GetByUser(ctx, cmd.TeamID, cmd.User.ID)
+	if err != nil || proposer.Role.Name != domain.Owner {
+		return exception.NewForbiddenException("only an owner of the team can transfer its ownership")
+	}
+
+	target, err := repository.Membership.Get(ctx, cmd.MembershipID)
+	if err != nil {
+		return err
+	}
+
+	if target.TeamID != cmd.TeamID {
+		return exception.NewForbiddenException(fmt.Sprintf("Team with ID %s is not match with membership-team ID", cmd.TeamID))
+	}
+
+	if target.Role.Name == domain.Owner {
+		return exception.NewBadRequestException("the member is already an owner of the team")
+	}
+
+	// a pending transfer left unanswered past its expiry is closed instead of blocking the new one
+	pending, err := repository.OwnershipTransfer.GetPendingByTeam(ctx, cmd.TeamID)
+	var notFound exception.NotFoundException
+	if err == nil {
+		if pending.Expire() != nil {
+			return exception.NewBadRequestException("the team already has a pending ownership transfer")
+		}
+
+		err = repository.OwnershipTransfer.Update(ctx, pending, tx)
+		if err != nil {
+			return err
+		}
+	} else if !errors.As(err, &notFound) {
+		return err
+	}
+
+	targetUser, err := repository.User.Get(ctx, target.UserID)
+	if err != nil {
+		return err
+	}
+
+	transfer := domain.NewOwnershipTransfer(cmd.TeamID, cmd.User.ID, target.UserID, cmd.KeepOwnership)
+	_, err = repository.OwnershipTransfer.Add(ctx, transfer, tx)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"SenderName":    cmd.User.FullName(),
+		"TeamName":      team.Name,
+		"EmailTo":       targetUser.Email,
+		"TransferLink":  fmt.Sprintf("http://localhost:3000/ownership-transfer/%s", transfer.ID),
+		"KeepOwnership": transfer.KeepOwnership,
+	}
+
+	emailPayload := worker.Mailer.CreateEmailPayload(worker.OwnershipTransferTemplate, targetUser.Email, fmt.Sprintf("You are invited to own the %s team", team.Name), data)
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd.TransferID = transfer.ID
+
+	// the email links to the transfer, it is only sent once the transfer is committed
+	return worker.Mailer.SendEmail(emailPayload)
+}
+
+// RespondOwnershipTransfer accepts or declines a transfer, on acceptance the proposed member becomes an owner
+// and the proposer becomes an admin unless the ownership is kept.
+func RespondOwnershipTransfer(ctx context.Context, cmd *command.RespondOwnershipTransfer) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	transfer, err := repository.OwnershipTransfer.Get(ctx, cmd.TransferID)
+	if err != nil {
+		return err
+	}
+
+	err = transfer.Respond(cmd.User.ID, cmd.Accept)
+	if err != nil {
+		return err
+	}
+
+	err = repository.OwnershipTransfer.Update(ctx, transfer, tx)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Accept {
+		target, err := repository.Membership.GetByUser(ctx, transfer.TeamID, transfer.ToUserID)
+		if err != nil {
+			return exception.NewBadRequestException("you are not a member of the team anymore")
+		}
+
+		err = setMembershipRole(ctx, target, domain.Owner, tx)
+		if err != nil {
+			return err
+		}
+
+		proposer, err := repository.Membership.GetByUser(ctx, transfer.TeamID, transfer.FromUserID)
+		if err == nil && !transfer.KeepOwnership && proposer.Role.Name == domain.Owner {
+			err = setMembershipRole(ctx, proposer, domain.Admin, tx)
+		}
+		var notFound exception.NotFoundException
+		if err != nil && !errors.As(err, &notFound) {
+			return err
+		}
+	}
+
+	err = ensureTeamOwner(ctx, transfer.TeamID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func CancelOwnershipTransfer(ctx context.Context, cmd *command.CancelOwnershipTransfer) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	transfer, err := repository.OwnershipTransfer.Get(ctx, cmd.TransferID)
+	if err != nil {
+		return err
+	}
+
+	if transfer.TeamID != cmd.TeamID {
+		return exception.NewNotFoundException("ownership transfer not found")
+	}
+
+	membership, err := repository.Membership.GetByUser(ctx, cmd.TeamID, cmd.User.ID)
+	if err != nil || membership.Role.Name != domain.Owner {
+		return exception.NewForbiddenException("only an owner of the team can cancel its ownership transfer")
+	}
+
+	err = transfer.Cancel()
+	if err != nil {
+		return err
+	}
+
+	err = repository.OwnershipTransfer.Update(ctx, transfer, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RelinquishOwnership turns the owner into an admin, it is only possible while the team has another owner.
+func RelinquishOwnership(ctx context.Context, cmd *command.RelinquishOwnership) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	membership, err := repository.Membership.GetByUser(ctx, cmd.TeamID, cmd.User.ID)
+	if err != nil {
+		return err
+	}
+
+	if membership.Role.Name != domain.Owner {
+		return exception.NewBadRequestException("you are not an owner of the team")
+	}
+
+	err = setMembershipRole(ctx, membership, domain.Admin, tx)
+	if err != nil {
+		return err
+	}
+
+	err = ensureTeamOwner(ctx, cmd.TeamID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setMembershipRole changes the role of the membership, a membership becoming an owner is made permanent
+// so the team cannot lose its owners when the membership expires.
+func setMembershipRole(ctx context.Context, membership domain.Membership, roleName domain.RoleType, tx pgx.Tx) error {
+	role, err := repository.Role.GetByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	membership.RoleID = role.ID
+	membership.Role = role
+	if roleName == domain.Owner {
+		membership.ExpiresAt = nil
+	}
+
+	_, err = repository.Membership.Update(ctx, membership, tx)
+	return err
+}
+
+// ensureTeamOwner keeps the invariant that every non-personal team has at least one owner,
+// it must run in the transaction that changes the memberships before it is committed.
+// The team is locked first so that owners removed by concurrent transactions are counted once they are committed.
+func ensureTeamOwner(ctx context.Context, teamID uuid.UUID, tx pgx.Tx) error {
+	err := repository.Team.Lock(ctx, teamID, tx)
+	if err != nil {
+		return err
+	}
+
+	owners, err := repository.Membership.CountByRole(ctx, teamID, domain.Owner, tx)
+	if err != nil {
+		return err
+	}
+
+	if owners == 0 {
+		return exception.NewForbiddenException("the team must keep at least one owner")
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"authorization/config"
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/repository"
+	"authorization/util"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ChangeTeamPlan moves the team to another plan, only administrators can. The members and invitations beyond
+// the quotas of the new plan are kept, the team cannot add more of them until it is back under its quotas.
+func ChangeTeamPlan(ctx context.Context, cmd *command.ChangeTeamPlan) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	if !config.AppConfig.IsAdmin(cmd.User.Email) {
+		return exception.NewForbiddenException("You are not allowed to change the plan of the team")
+	}
+
+	plan, err := domain.CurrentPlans().Get(cmd.Plan)
+	if err != nil {
+		return err
+	}
+
+	// the plan is changed under the lock taken by the paths checking its quotas
+	_, err = repository.Team.LockUsage(ctx, cmd.TeamID, tx)
+	if err != nil {
+		return err
+	}
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	} else if team.IsPersonal {
+		return exception.NewForbiddenException(fmt.Sprintf("personal team with ID %s has no plan", cmd.TeamID))
+	}
+
+	team.Plan = plan.Name
+	team.UpdatedAt = util.GetTimestampUTC()
+	err = repository.Team.SetPlan(ctx, team, tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockTeamPlan locks the team until the transaction ends and returns its plan along with its usage,
+// every path adding members or invitations to a team checks the quotas of its plan through it.
+func lockTeamPlan(ctx context.Context, teamID uuid.UUID, tx pgx.Tx) (domain.Plan, domain.TeamUsage, error) {
+	usage, err := repository.Team.LockUsage(ctx, teamID, tx)
+	if err != nil {
+		return domain.Plan{}, domain.TeamUsage{}, err
+	}
+
+	team, err := repository.Team.Get(ctx, teamID)
+	if err != nil {
+		return domain.Plan{}, domain.TeamUsage{}, err
+	}
+
+	return domain.CurrentPlans().Of(team), usage, nil
+}
+
+// reserveSeat checks that the team has a seat left for one more member, the seat is held until the transaction ends.
+func reserveSeat(ctx context.Context, teamID uuid.UUID, tx pgx.Tx) error {
+	plan, usage, err := lockTeamPlan(ctx, teamID, tx)
+	if err != nil {
+		return err
+	}
+
+	return plan.CheckMembers(usage, 1)
+}
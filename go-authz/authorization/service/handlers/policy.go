@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/repository"
+	"context"
+	"errors"
+	"fmt"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func CreatePolicy(ctx context.Context, cmd *command.CreatePolicy) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	_, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	policy := domain.NewPolicy(cmd.TeamID, cmd.User.ID, cmd.Name, cmd.Description, cmd.Permission, cmd.Expression)
+	err = validatePolicy(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.Policy.Add(ctx, policy, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd.PolicyID = policy.ID
+	return nil
+}
+
+func UpdatePolicy(ctx context.Context, cmd *command.UpdatePolicy) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	policy, err := getTeamPolicy(ctx, cmd.TeamID, cmd.PolicyID)
+	if err != nil {
+		return err
+	}
+
+	policy.Update(map[string]any{
+		"name":        cmd.Name,
+		"description": cmd.Description,
+		"permission":  cmd.Permission,
+		"expression":  cmd.Expression,
+		"isActive":    cmd.IsActive,
+	})
+
+	err = validatePolicy(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.Policy.Update(ctx, policy, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DeletePolicy(ctx context.Context, cmd *command.DeletePolicy) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	_, err := getTeamPolicy(ctx, cmd.TeamID, cmd.PolicyID)
+	if err != nil {
+		return err
+	}
+
+	err = repository.Policy.Delete(ctx, cmd.PolicyID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func getTeamPolicy(ctx context.Context, teamID, policyID uuid.UUID) (domain.Policy, error) {
+	policy, err := repository.Policy.Get(ctx, policyID)
+	if err != nil {
+		return domain.Policy{}, err
+	}
+
+	if policy.TeamID != teamID {
+		return domain.Policy{}, exception.NewNotFoundException("policy not found")
+	}
+
+	return policy, nil
+}
+
+// validatePolicy compiles the expression and makes sure the permission is known.
+func validatePolicy(ctx context.Context, policy domain.Policy) error {
+	err := policy.Validate()
+	if err != nil {
+		return err
+	}
+
+	if policy.Permission == domain.AnyPermission {
+		return nil
+	}
+
+	_, err = repository.Permission.GetByName(ctx, policy.Permission)
+	if err != nil {
+		var notFound exception.NotFoundException
+		if errors.As(err, &notFound) {
+			return exception.NewBadRequestException(fmt.Sprintf("permission %s does not exist", policy.Permission))
+		}
+		return err
+	}
+
+	return nil
+}
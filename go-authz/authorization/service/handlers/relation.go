@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/repository"
+	"authorization/view"
+	"context"
+	"fmt"
+	"strings"
+)
+
+func WriteRelation(ctx context.Context, cmd *command.WriteRelation) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	tuple, err := domain.ParseRelationTuple(cmd.Tuple)
+	if err != nil {
+		return err
+	}
+
+	err = validateRelationWrite(ctx, tuple, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	canManage, err := canManageObject(ctx, tuple.ObjectType, tuple.ObjectID, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	if !canManage {
+		// an object that has no relations yet can be claimed by a manager of its owning team,
+		// e.g. a team admin attaching a new application to the team
+		canClaim, err := canClaimObject(ctx, tuple, cmd.User)
+		if err != nil {
+			return err
+		}
+		if !canClaim {
+			return exception.NewForbiddenException(fmt.Sprintf("you are not allowed to manage relations of %s", tuple.Object()))
+		}
+	}
+
+	err = repository.Relation.Add(ctx, tuple, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DeleteRelation(ctx context.Context, cmd *command.DeleteRelation) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	tuple, err := domain.ParseRelationTuple(cmd.Tuple)
+	if err != nil {
+		return err
+	}
+
+	err = validateRelationWrite(ctx, tuple, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	canManage, err := canManageObject(ctx, tuple.ObjectType, tuple.ObjectID, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	if !canManage {
+		return exception.NewForbiddenException(fmt.Sprintf("you are not allowed to manage relations of %s", tuple.Object()))
+	}
+
+	err = repository.Relation.Delete(ctx, tuple, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateRelationWrite(ctx context.Context, tuple domain.RelationTuple, user domain.User) error {
+	namespace, err := view.Namespace(tuple.ObjectType)
+	if err != nil {
+		return err
+	}
+
+	if namespace.Mirrored {
+		return exception.NewForbiddenException(fmt.Sprintf("relations of %s are managed by the service and cannot be changed directly", tuple.ObjectType))
+	}
+
+	if _, ok := namespace.Relation(tuple.Relation); !ok {
+		return exception.NewBadRequestException(fmt.Sprintf("relation %s is not defined on %s", tuple.Relation, tuple.ObjectType))
+	}
+
+	if _, err := view.Namespace(tuple.SubjectType); err != nil && tuple.SubjectType != domain.UserNamespace {
+		return err
+	}
+
+	return nil
+}
+
+func canManageObject(ctx context.Context, objectType, objectID string, user domain.User) (bool, error) {
+	namespace, err := view.Namespace(objectType)
+	if err != nil {
+		return false, err
+	}
+
+	if namespace.Manage == "" {
+		return false, nil
+	}
+
+	return view.Check(ctx, objectType+":"+objectID, namespace.Manage, domain.UserNamespace+":"+user.ID.String())
+}
+
+// canClaimObject lets a manager of a team attach an object that has no relations yet to the team, only through the
+// owner relation of the namespace and only for object IDs scoped by the team so a team cannot claim another's objects.
+func canClaimObject(ctx context.Context, tuple domain.RelationTuple, user domain.User) (bool, error) {
+	if tuple.IsUserset() || tuple.SubjectType != domain.TeamNamespace {
+		return false, nil
+	}
+
+	namespace, err := view.Namespace(tuple.ObjectType)
+	if err != nil {
+		return false, err
+	}
+
+	if namespace.Owner == "" || tuple.Relation != namespace.Owner || !strings.HasPrefix(tuple.ObjectID, tuple.SubjectID+"/") {
+		return false, nil
+	}
+
+	existing, err := repository.Relation.List(ctx, domain.RelationTupleOptions{
+		ObjectType: tuple.ObjectType,
+		ObjectID:   tuple.ObjectID,
+		Limit:      1,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if len(existing) > 0 {
+		return false, nil
+	}
+
+	return canManageObject(ctx, tuple.SubjectType, tuple.SubjectID, user)
+}
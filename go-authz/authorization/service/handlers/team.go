@@ -6,14 +6,19 @@ import (
 	"authorization/domain"
 	"authorization/domain/command"
 	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
 	"authorization/repository"
 	"authorization/util"
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
 )
 
 func CreateTeam(ctx context.Context, cmd *command.CreateTeam) error {
@@ -61,10 +66,19 @@ func UpdateTeam(ctx context.Context, cmd *command.UpdateTeam) error {
 		return err
 	}
 
-	team.Update(map[string]interface{}{
+	payload := map[string]interface{}{
 		"name":        cmd.Name,
 		"description": cmd.Description,
-	})
+	}
+
+	if cmd.IsDiscoverable != nil {
+		if *cmd.IsDiscoverable && team.IsPersonal {
+			return exception.NewBadRequestException("personal team cannot be discoverable")
+		}
+		payload["isDiscoverable"] = *cmd.IsDiscoverable
+	}
+
+	team.Update(payload)
 
 	_, err = repository.Team.Update(ctx, team, tx)
 	if err != nil {
@@ -136,7 +150,12 @@ func DeleteTeamMember(ctx context.Context, cmd *command.DeleteTeamMember) error
 		return err
 	}
 
-	err = membership.Validation(cmd.User.ID, cmd.TeamID, "")
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.User.ID, "member:delete")
+	if err != nil {
+		return err
+	}
+
+	err = membership.Validation(cmd.User.ID, cmd.TeamID, access.RoleName, "")
 	if err != nil {
 		return err
 	}
@@ -146,11 +165,100 @@ func DeleteTeamMember(ctx context.Context, cmd *command.DeleteTeamMember) error
 		return err
 	}
 
+	err = ensureTeamOwner(ctx, cmd.TeamID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LeaveTeam removes the membership of the user from the team and lets the owners and admins know about it.
+func LeaveTeam(ctx context.Context, cmd *command.LeaveTeam) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	if team.IsPersonal {
+		return exception.NewBadRequestException("you cannot leave your personal team")
+	}
+
+	membership, err := repository.Membership.GetByUser(ctx, cmd.TeamID, cmd.User.ID)
+	if err != nil {
+		return err
+	}
+
+	err = repository.Membership.ResetLastActiveTeam(ctx, cmd.User.ID, cmd.TeamID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = repository.Membership.Delete(ctx, membership.ID, tx)
+	if err != nil {
+		return err
+	}
+
+	if membership.Role.Name == domain.Owner {
+		err = ensureTeamOwner(ctx, cmd.TeamID, tx)
+		var forbidden exception.ForbiddenException
+		if errors.As(err, &forbidden) {
+			return exception.NewForbiddenException("you are the last owner of the team, transfer the ownership before leaving it")
+		} else if err != nil {
+			return err
+		}
+	}
+
+	managers, err := repository.Membership.ListByRoles(ctx, cmd.TeamID, domain.RoleTypes{domain.Owner, domain.Admin})
+	if err != nil {
+		return err
+	}
+
+	emailPayloads := make([]*worker.EmailPayload, 0, len(managers))
+	for _, manager := range managers {
+		if manager.UserID == cmd.User.ID {
+			continue
+		}
+
+		data := map[string]interface{}{
+			"MemberName":  cmd.User.FullName(),
+			"MemberEmail": cmd.User.Email,
+			"Role":        string(membership.Role.Name),
+			"TeamName":    team.Name,
+			"EmailTo":     manager.User.Email,
+			"TeamLink":    fmt.Sprintf("http://localhost:3000/teams/%s", team.ID),
+		}
+
+		emailPayload := worker.Mailer.CreateEmailPayload(worker.MemberLeftTemplate, manager.User.Email, fmt.Sprintf("%s has left the %s team", cmd.User.FullName(), team.Name), data)
+		emailPayloads = append(emailPayloads, emailPayload)
+	}
+
 	err = tx.Commit(ctx)
 	if err != nil {
 		return err
 	}
 
+	// the member has left once committed, a failed notification does not undo it
+	for _, emailPayload := range emailPayloads {
+		if err := worker.Mailer.SendEmail(emailPayload); err != nil {
+			log.Warn().Caller().Err(err).Str("team_id", team.ID.String()).Str("email", emailPayload.To).Msg("Failed to notify a manager that a member left the team")
+		}
+	}
+
 	return nil
 }
 
@@ -169,7 +277,12 @@ func ChangeMemberRole(ctx context.Context, cmd *command.ChangeMemberRole) error
 		return err
 	}
 
-	err = membership.Validation(cmd.User.ID, cmd.TeamID, cmd.Role)
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.User.ID, "member:update-role")
+	if err != nil {
+		return err
+	}
+
+	err = membership.Validation(cmd.User.ID, cmd.TeamID, access.RoleName, cmd.Role)
 	if err != nil {
 		return err
 	}
@@ -186,6 +299,11 @@ func ChangeMemberRole(ctx context.Context, cmd *command.ChangeMemberRole) error
 		return err
 	}
 
+	err = ensureTeamOwner(ctx, cmd.TeamID, tx)
+	if err != nil {
+		return err
+	}
+
 	err = tx.Commit(ctx)
 	if err != nil {
 		return err
@@ -288,3 +406,248 @@ func DeleteTeamAvatar(ctx context.Context, cmd *command.DeleteTeamAvatar) error
 
 	return nil
 }
+
+// SetTeamParent nests the team under another team, the caller must be allowed to manage the hierarchy of both teams.
+func SetTeamParent(ctx context.Context, cmd *command.SetTeamParent) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	inheritance := cmd.Inheritance
+	if inheritance == "" {
+		inheritance = domain.InheritAdmins
+	}
+	if err := inheritance.Validate(); err != nil {
+		return err
+	}
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	parent, err := repository.Team.Get(ctx, cmd.ParentID)
+	if err != nil {
+		return err
+	}
+
+	access, err := repository.Role.GetAccess(ctx, parent.ID, cmd.User.ID, "team:hierarchy-manage")
+	if err != nil {
+		return err
+	}
+
+	if !access.IsAllowed {
+		return exception.NewForbiddenException("You are not allowed to manage the hierarchy of the parent team")
+	}
+
+	ancestors, err := repository.Team.Ancestors(ctx, parent.ID)
+	if err != nil {
+		return err
+	}
+
+	height, err := repository.Team.Height(ctx, team.ID)
+	if err != nil {
+		return err
+	}
+
+	err = team.ValidateParent(parent, ancestors, height)
+	if err != nil {
+		return err
+	}
+
+	team.ParentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+	team.Inheritance = inheritance
+	team.UpdatedAt = util.GetTimestampUTC()
+
+	err = repository.Team.SetParent(ctx, team, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func RemoveTeamParent(ctx context.Context, cmd *command.RemoveTeamParent) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	if !team.ParentID.Valid {
+		return exception.NewNotFoundException("team does not have a parent team")
+	}
+
+	team.ParentID = uuid.NullUUID{}
+	team.UpdatedAt = util.GetTimestampUTC()
+
+	err = repository.Team.SetParent(ctx, team, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ArchiveTeam makes the team read-only and schedules its deletion once the grace period is over.
+func ArchiveTeam(ctx context.Context, cmd *command.ArchiveTeam) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	err = archiveTeam(ctx, team, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RestoreTeam brings an archived team back during its grace period.
+func RestoreTeam(ctx context.Context, cmd *command.RestoreTeam) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	err = team.Restore()
+	if err != nil {
+		return err
+	}
+
+	err = repository.Team.Archive(ctx, team, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	// PurgeTeam skips the teams that are not archived anymore, a task left behind is harmless
+	if err := worker.Scheduler.CancelPurgeTeam(team.ID); err != nil {
+		log.Warn().Caller().Err(err).Str("team_id", team.ID.String()).Msg("Failed to cancel the deletion of a restored team")
+	}
+
+	return nil
+}
+
+// PurgeTeam deletes an archived team whose grace period is over, it is run by the scheduled task.
+func PurgeTeam(ctx context.Context, cmd *command.PurgeTeam) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		var notFound exception.NotFoundException
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !team.IsArchived() || team.PurgeAt == nil || util.GetTimestampUTC().Before(*team.PurgeAt) {
+		log.Info().Str("team_id", team.ID.String()).Msg("Team was restored or its grace period is not over, skipping deletion")
+		return nil
+	}
+
+	// the invitations go along with the team, their reminders must not be sent
+	invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{TeamID: team.ID, Statuses: domain.InvitationStatusesOpen})
+	if err != nil {
+		return err
+	}
+
+	err = repository.Team.Delete(ctx, team.ID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, invitation := range invitations {
+		if err := cancelInvitationReminders(invitation); err != nil {
+			return err
+		}
+	}
+
+	if team.AvatarURL != "" {
+		paths := strings.Split(team.AvatarURL, "/")
+		path := filepath.Join(config.StorageConfig.StaticRoot, config.StorageConfig.StaticAvatarPath, paths[len(paths)-1])
+		if err := util.DeleteFileInLocal(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func archiveTeam(ctx context.Context, team domain.Team, tx pgx.Tx) error {
+	err := team.Archive(config.AppConfig.TeamDeletionGracePeriod)
+	if err != nil {
+		return err
+	}
+
+	err = repository.Team.Archive(ctx, team, tx)
+	if err != nil {
+		return err
+	}
+
+	return worker.Scheduler.SchedulePurgeTeam(team.ID, *team.PurgeAt)
+}
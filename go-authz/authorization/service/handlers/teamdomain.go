@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/dns"
+	"authorization/infrastructure/persistence"
+	"authorization/repository"
+	"authorization/util"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ClaimTeamDomain claims an email domain for the team, the claim has no effect until the domain is verified.
+func ClaimTeamDomain(ctx context.Context, cmd *command.ClaimTeamDomain) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	team, err := repository.Team.Get(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	} else if team.IsPersonal {
+		return exception.NewForbiddenException(fmt.Sprintf("you can't claim a domain for personal team with ID %s", cmd.TeamID))
+	}
+
+	access, err := repository.Role.GetAccess(ctx, cmd.TeamID, cmd.User.ID, "team:domain-manage")
+	if err != nil {
+		return err
+	} else if !access.IsAllowed {
+		return exception.NewForbiddenException("you are not allowed to manage the domains of the team")
+	}
+
+	err = domain.CurrentRoleHierarchy().CanAssign(access.RoleName, cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	role, err := repository.Role.GetByName(ctx, cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	teamDomain, err := domain.NewTeamDomain(cmd.TeamID, cmd.User.ID, cmd.Domain, role.ID, cmd.JoinMode)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := repository.TeamDomain.ListByTeam(ctx, cmd.TeamID)
+	if err != nil {
+		return err
+	}
+
+	for _, claim := range claimed {
+		if claim.Domain == teamDomain.Domain {
+			return exception.NewBadRequestException(fmt.Sprintf("domain %s is already claimed by the team", teamDomain.Domain))
+		}
+	}
+
+	_, err = repository.TeamDomain.Add(ctx, teamDomain, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd.DomainID = teamDomain.ID
+	return nil
+}
+
+// VerifyTeamDomain looks up the verification TXT record of the domain, once verified the users
+// already signed up with a verified email address of the domain join the team or are invited to it.
+func VerifyTeamDomain(ctx context.Context, cmd *command.VerifyTeamDomain) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	teamDomain, err := getTeamDomain(ctx, cmd.TeamID, cmd.DomainID, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	records, err := dns.Resolver.LookupTXT(ctx, teamDomain.VerificationName())
+	if err != nil {
+		log.Info().Caller().Err(err).Str("domain", teamDomain.Domain).Msg("Failed to look up domain verification record")
+		return exception.NewBadRequestException(fmt.Sprintf("TXT record %s is not found on %s", teamDomain.VerificationRecord(), teamDomain.VerificationName()))
+	}
+
+	err = teamDomain.Verify(records)
+	if err != nil {
+		return err
+	}
+
+	err = repository.TeamDomain.Verify(ctx, teamDomain, tx)
+	if err != nil {
+		return err
+	}
+
+	users, err := repository.User.ListVerifiedByEmailDomain(ctx, teamDomain.Domain)
+	if err != nil {
+		return err
+	}
+
+	emails := make([]invitationEmail, 0)
+	for _, user := range users {
+		email, err := joinTeamByDomain(ctx, teamDomain, user, tx)
+		if err != nil {
+			return err
+		} else if email != nil {
+			emails = append(emails, *email)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return sendInvitationEmails(emails)
+}
+
+// RemoveTeamDomain drops the claim of the team on the domain, the members who joined through it stay.
+func RemoveTeamDomain(ctx context.Context, cmd *command.RemoveTeamDomain) error {
+	tx, txErr := persistence.Pool.Begin(ctx)
+	if txErr != nil {
+		return txErr
+	}
+
+	defer func() {
+		tx.Rollback(ctx)
+	}()
+
+	teamDomain, err := getTeamDomain(ctx, cmd.TeamID, cmd.DomainID, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	err = repository.TeamDomain.Delete(ctx, teamDomain.ID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// JoinTeamsByEmailDomain adds a newly signed up user to the teams that verified the domain of its email address,
+// or invites the user to them. It runs in the transaction creating the user and must be called by every signup path,
+// the returned send emails the invitations and must be called once the transaction is committed.
+func JoinTeamsByEmailDomain(ctx context.Context, user domain.User, tx pgx.Tx) (send func() error, err error) {
+	emails := make([]invitationEmail, 0)
+	send = func() error { return sendInvitationEmails(emails) }
+
+	if !user.Verified || user.EmailDomain() == "" {
+		return send, nil
+	}
+
+	teamDomains, err := repository.TeamDomain.ListVerifiedByDomain(ctx, user.EmailDomain())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, teamDomain := range teamDomains {
+		email, err := joinTeamByDomain(ctx, teamDomain, user, tx)
+		if err != nil {
+			return nil, err
+		} else if email != nil {
+			emails = append(emails, *email)
+		}
+	}
+
+	return send, nil
+}
+
+// joinTeamByDomain makes the user a member of the team of the verified domain, or sends an invitation to the team
+// when the domain only proposes to join or the team has no seat left. Members and users already invited are left alone,
+// so is the user when the team cannot have more invitations pending. The email of the invitation is returned to be sent
+// once the transaction is committed.
+func joinTeamByDomain(ctx context.Context, teamDomain domain.TeamDomain, user domain.User, tx pgx.Tx) (*invitationEmail, error) {
+	_, err := repository.Membership.GetByUser(ctx, teamDomain.TeamID, user.ID)
+	if err == nil {
+		return nil, nil
+	}
+	var notFound exception.NotFoundException
+	if !errors.As(err, &notFound) {
+		return nil, err
+	}
+
+	plan, usage, err := lockTeamPlan(ctx, teamDomain.TeamID, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if teamDomain.JoinMode == domain.DomainJoinAuto && plan.CheckMembers(usage, 1) == nil {
+		now := util.GetTimestampUTC()
+		_, err = repository.Membership.Add(ctx, domain.Membership{
+			ID:           uuid.NewV4(),
+			TeamID:       teamDomain.TeamID,
+			UserID:       user.ID,
+			RoleID:       teamDomain.RoleID,
+			LastActiveAt: now,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}, tx)
+		return nil, err
+	}
+
+	pending, err := repository.Invitation.List(ctx, domain.InvitationOptions{
+		Email:    user.Email,
+		TeamID:   teamDomain.TeamID,
+		Statuses: domain.InvitationStatusesOpen,
+	})
+	if err != nil {
+		return nil, err
+	} else if len(pending) > 0 || plan.CheckInvitations(usage, 1) != nil {
+		return nil, nil
+	}
+
+	settings, err := repository.InvitationSettings.Get(ctx, teamDomain.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := domain.NewInvitation(user.Email, domain.InvitationStatusPending, teamDomain.TeamID, teamDomain.CreatorID, teamDomain.RoleID, settings.ExpiresIn)
+	_, err = repository.Invitation.Add(ctx, invitation, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	senderName := teamDomain.Team.Name
+	if creator, err := repository.User.Get(ctx, teamDomain.CreatorID); err == nil {
+		senderName = creator.FullName()
+	}
+
+	return &invitationEmail{senderName: senderName, team: teamDomain.Team, invitation: invitation, settings: settings}, nil
+}
+
+// getTeamDomain returns the domain claimed by the team when the user can manage the domains of the team.
+func getTeamDomain(ctx context.Context, teamID uuid.UUID, domainID ulid.ULID, user domain.User) (domain.TeamDomain, error) {
+	teamDomain, err := repository.TeamDomain.Get(ctx, domainID)
+	if err != nil {
+		return domain.TeamDomain{}, err
+	}
+
+	if teamDomain.TeamID != teamID {
+		return domain.TeamDomain{}, exception.NewNotFoundException(fmt.Sprintf("domain with ID %s is not found in team with ID %s", domainID, teamID))
+	}
+
+	access, err := repository.Role.GetAccess(ctx, teamID, user.ID, "team:domain-manage")
+	if err != nil {
+		return domain.TeamDomain{}, err
+	} else if !access.IsAllowed {
+		return domain.TeamDomain{}, exception.NewForbiddenException("you are not allowed to manage the domains of the team")
+	}
+
+	return teamDomain, nil
+}
@@ -63,6 +63,19 @@ func DeleteUser(ctx context.Context, cmd *command.DeleteUser) error {
 		return err
 	}
 
+	// nobody would be left to manage the teams the user owns alone, they are archived and deleted after the grace period
+	teams, err := repository.Team.ListSoleOwned(ctx, cmd.User.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, team := range teams {
+		err = archiveTeam(ctx, team, tx)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = tx.Commit(ctx)
 	if err != nil {
 		return err
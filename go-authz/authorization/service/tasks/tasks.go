@@ -0,0 +1,113 @@
+package tasks
+
+import (
+	"authorization/domain/command"
+	"authorization/infrastructure/worker"
+	"authorization/service/handlers"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// NewServeMux routes the tasks of the authorization queue to their handlers.
+func NewServeMux() *asynq.ServeMux {
+	mux := asynq.NewServeMux()
+
+	// Define a task handler for the deletion of archived teams.
+	mux.HandleFunc(
+		worker.TypePurgeTeam, // task type
+		HandlePurgeTeamTask,  // handler function
+	)
+
+	// Define a task handler for the bulk invitations processed in the background.
+	mux.HandleFunc(
+		worker.TypeInvitationImport, // task type
+		HandleInvitationImportTask,  // handler function
+	)
+
+	// Define a task handler for the periodic sweep of the expired invitations.
+	mux.HandleFunc(
+		worker.TypeExpireInvitations, // task type
+		HandleExpireInvitationsTask,  // handler function
+	)
+
+	// Define a task handler for the periodic sweep of the expired memberships.
+	mux.HandleFunc(
+		worker.TypeExpireMemberships, // task type
+		HandleExpireMembershipsTask,  // handler function
+	)
+
+	// Define a task handler for the periodic sweep of the open access reviews.
+	mux.HandleFunc(
+		worker.TypeSweepAccessReviews, // task type
+		HandleSweepAccessReviewsTask,  // handler function
+	)
+
+	// Define a task handler for the end of the role elevations.
+	mux.HandleFunc(
+		worker.TypeRevertElevation, // task type
+		HandleRevertElevationTask,  // handler function
+	)
+
+	return mux
+}
+
+func HandlePurgeTeamTask(ctx context.Context, task *asynq.Task) error {
+	var payload worker.PurgeTeamPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return handlers.PurgeTeam(ctx, &command.PurgeTeam{TeamID: payload.TeamID})
+}
+
+func HandleInvitationImportTask(ctx context.Context, task *asynq.Task) error {
+	var payload worker.InvitationImportPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return handlers.ProcessInvitationImport(ctx, &command.ProcessInvitationImport{ImportID: payload.ImportID})
+}
+
+func HandleExpireInvitationsTask(ctx context.Context, task *asynq.Task) error {
+	cmd := command.ExpireInvitations{}
+	if err := handlers.ExpireInvitations(ctx, &cmd); err != nil {
+		return err
+	}
+
+	log.Info().Int64("expired", cmd.Expired).Msg("Expired the invitations past their expiry")
+	return nil
+}
+
+func HandleExpireMembershipsTask(ctx context.Context, task *asynq.Task) error {
+	cmd := command.ExpireMemberships{}
+	if err := handlers.ExpireMemberships(ctx, &cmd); err != nil {
+		return err
+	}
+
+	log.Info().Int("expired", cmd.Expired).Msg("Removed the memberships past their expiry")
+	return nil
+}
+
+func HandleSweepAccessReviewsTask(ctx context.Context, task *asynq.Task) error {
+	cmd := command.SweepAccessReviews{}
+	if err := handlers.SweepAccessReviews(ctx, &cmd); err != nil {
+		return err
+	}
+
+	log.Info().Int("reminded", cmd.Reminded).Int("closed", cmd.Closed).Msg("Swept the open access reviews")
+	return nil
+}
+
+func HandleRevertElevationTask(ctx context.Context, task *asynq.Task) error {
+	var payload worker.ElevationRevertPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return handlers.RevertElevation(ctx, &command.RevertElevation{RequestID: payload.RequestID})
+}
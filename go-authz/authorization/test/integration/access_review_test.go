@@ -0,0 +1,258 @@
+package integration
+
+import (
+	"authorization/config"
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/view"
+	"context"
+	"encoding/csv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+)
+
+var _ = Describe("Access Review Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john    domain.User
+		jane    domain.User
+		bob     domain.User
+		alice   domain.User
+		cmdTeam *command.CreateTeam
+		cmdB    *command.CreateTeam
+		client  *worker.ClientMock
+	)
+
+	sentTo := func(template worker.EmailTemplate) []string {
+		var emails []string
+		for _, payload := range client.Sent {
+			if payload.TemplateName == template {
+				emails = append(emails, payload.To)
+			}
+		}
+		return emails
+	}
+
+	isMember := func(teamID uuid.UUID, user domain.User) bool {
+		_, err := repository.Membership.GetByUser(ctx, teamID, user.ID)
+		return err == nil
+	}
+
+	itemOf := func(review domain.AccessReview, teamID uuid.UUID, user domain.User) domain.AccessReviewItem {
+		for _, item := range review.Items {
+			if item.TeamID == teamID && item.UserID == user.ID {
+				return item
+			}
+		}
+		Fail("no item for " + user.Email)
+		return domain.AccessReviewItem{}
+	}
+
+	start := func(user domain.User, autoRevoke bool, teamIDs ...uuid.UUID) domain.AccessReview {
+		cmd := &command.StartAccessReview{
+			Name:       "Quarterly admin review",
+			TeamIDs:    teamIDs,
+			Roles:      []domain.RoleType{domain.Owner, domain.Admin},
+			Deadline:   time.Now().Add(7 * 24 * time.Hour),
+			AutoRevoke: autoRevoke,
+			User:       user,
+		}
+		Ω(handlers.StartAccessReview(ctx, cmd)).To(Succeed())
+
+		review, err := repository.AccessReview.Get(ctx, cmd.ReviewID)
+		Ω(err).To(Succeed())
+		return review
+	}
+
+	moveDeadline := func(review domain.AccessReview, deadline time.Time) {
+		_, err := persistence.Pool.Exec(ctx, `UPDATE access_reviews SET deadline = $2 WHERE id = $1`, review.ID, deadline.UTC())
+		Ω(err).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		client = worker.CreateMailerClientMock()
+		worker.CreateMailerMock(client)
+		worker.CreateSchedulerMock()
+
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		bob = domain.NewUser("Bob", "Doe", "bobdoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, bob)).To(Succeed())
+
+		alice = domain.NewUser("Alice", "Doe", "alicedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, alice)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Admin)
+		addMember(ctx, cmdTeam.TeamID, bob, domain.Member)
+
+		cmdB = &command.CreateTeam{
+			Name:        "Team B",
+			Description: "Team B Description",
+			User:        alice,
+		}
+		createTeam(ctx, cmdB, alice)
+		addMember(ctx, cmdB.TeamID, bob, domain.Admin)
+	})
+	It("Lets the owners of every team and the administrators start a review", func() {
+		cmd := &command.StartAccessReview{
+			Name:     "Quarterly admin review",
+			TeamIDs:  []uuid.UUID{cmdTeam.TeamID},
+			Deadline: time.Now().Add(24 * time.Hour),
+			User:     jane,
+		}
+		Ω(handlers.StartAccessReview(ctx, cmd)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		// john does not own team B
+		cmd.User = john
+		cmd.TeamIDs = []uuid.UUID{cmdTeam.TeamID, cmdB.TeamID}
+		Ω(handlers.StartAccessReview(ctx, cmd)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		cmd.TeamIDs = []uuid.UUID{cmdTeam.TeamID}
+		cmd.Deadline = time.Now().Add(-time.Hour)
+		Ω(handlers.StartAccessReview(ctx, cmd)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		admins := config.AppConfig.AppAdminEmails
+		config.AppConfig.AppAdminEmails = []string{jane.Email}
+		DeferCleanup(func() { config.AppConfig.AppAdminEmails = admins })
+
+		review := start(jane, false, cmdTeam.TeamID, cmdB.TeamID)
+		Ω(review.Status).To(Equal(domain.AccessReviewOpen))
+		Ω(review.Items).To(HaveLen(4))
+		Ω(review.PendingItems).To(Equal(4))
+		Ω(sentTo(worker.AccessReviewTemplate)).To(ConsistOf(john.Email, alice.Email))
+	})
+	It("Removes the revoked memberships and completes the review once every one is reviewed", func() {
+		review := start(john, false, cmdTeam.TeamID)
+		Ω(review.Items).To(HaveLen(2))
+
+		// nobody reviews their own access
+		own := &command.ReviewAccess{ReviewID: review.ID, ItemID: itemOf(review, cmdTeam.TeamID, jane).ID, Decision: domain.AccessReviewConfirmed, User: jane}
+		Ω(handlers.ReviewAccess(ctx, own)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		// bob is not in the review
+		bobReview := &command.ReviewAccess{ReviewID: review.ID, ItemID: itemOf(review, cmdTeam.TeamID, john).ID, Decision: domain.AccessReviewConfirmed, User: bob}
+		Ω(handlers.ReviewAccess(ctx, bobReview)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		revoke := &command.ReviewAccess{ReviewID: review.ID, ItemID: itemOf(review, cmdTeam.TeamID, jane).ID, Decision: domain.AccessReviewRevoked, Note: "Left the project", User: john}
+		Ω(handlers.ReviewAccess(ctx, revoke)).To(Succeed())
+		Ω(isMember(cmdTeam.TeamID, jane)).To(BeFalse())
+		Ω(handlers.ReviewAccess(ctx, revoke)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		Ω(sentTo(worker.AccessReviewClosedTemplate)).To(BeEmpty())
+
+		admins := config.AppConfig.AppAdminEmails
+		config.AppConfig.AppAdminEmails = []string{alice.Email}
+		DeferCleanup(func() { config.AppConfig.AppAdminEmails = admins })
+
+		confirm := &command.ReviewAccess{ReviewID: review.ID, ItemID: itemOf(review, cmdTeam.TeamID, john).ID, Decision: domain.AccessReviewConfirmed, User: alice}
+		Ω(handlers.ReviewAccess(ctx, confirm)).To(Succeed())
+		Ω(isMember(cmdTeam.TeamID, john)).To(BeTrue())
+
+		review, err := repository.AccessReview.Get(ctx, review.ID)
+		Ω(err).To(Succeed())
+		Ω(review.Status).To(Equal(domain.AccessReviewCompleted))
+		Ω(review.CompletedAt).ToNot(BeNil())
+		Ω(review.PendingItems).To(BeZero())
+		Ω(itemOf(review, cmdTeam.TeamID, jane).Reviewer.Email).To(Equal(john.Email))
+		Ω(sentTo(worker.AccessReviewClosedTemplate)).To(ConsistOf(john.Email))
+	})
+	It("Reminds the reviewers as the deadline gets closer", func() {
+		review := start(john, false, cmdTeam.TeamID)
+		client.Sent = nil
+
+		cmd := &command.SweepAccessReviews{}
+		Ω(handlers.SweepAccessReviews(ctx, cmd)).To(Succeed())
+		Ω(cmd.Reminded).To(BeZero())
+
+		moveDeadline(review, time.Now().Add(48*time.Hour))
+		cmd = &command.SweepAccessReviews{}
+		Ω(handlers.SweepAccessReviews(ctx, cmd)).To(Succeed())
+		Ω(cmd.Reminded).To(Equal(1))
+		Ω(sentTo(worker.AccessReviewTemplate)).To(ConsistOf(john.Email))
+
+		// the reminder is sent once
+		cmd = &command.SweepAccessReviews{}
+		Ω(handlers.SweepAccessReviews(ctx, cmd)).To(Succeed())
+		Ω(cmd.Reminded).To(BeZero())
+
+		moveDeadline(review, time.Now().Add(12*time.Hour))
+		cmd = &command.SweepAccessReviews{}
+		Ω(handlers.SweepAccessReviews(ctx, cmd)).To(Succeed())
+		Ω(cmd.Reminded).To(Equal(1))
+		Ω(sentTo(worker.AccessReviewTemplate)).To(HaveLen(2))
+	})
+	It("Revokes the memberships left unreviewed at the deadline except the owners", func() {
+		admins := config.AppConfig.AppAdminEmails
+		config.AppConfig.AppAdminEmails = []string{john.Email}
+		DeferCleanup(func() { config.AppConfig.AppAdminEmails = admins })
+
+		review := start(john, true, cmdTeam.TeamID, cmdB.TeamID)
+
+		moveDeadline(review, time.Now().Add(-time.Minute))
+		cmd := &command.SweepAccessReviews{}
+		Ω(handlers.SweepAccessReviews(ctx, cmd)).To(Succeed())
+		Ω(cmd.Closed).To(Equal(1))
+
+		Ω(isMember(cmdTeam.TeamID, jane)).To(BeFalse())
+		Ω(isMember(cmdB.TeamID, bob)).To(BeFalse())
+		Ω(isMember(cmdTeam.TeamID, john)).To(BeTrue())
+		Ω(isMember(cmdB.TeamID, alice)).To(BeTrue())
+
+		review, err := repository.AccessReview.Get(ctx, review.ID)
+		Ω(err).To(Succeed())
+		Ω(review.Status).To(Equal(domain.AccessReviewCompleted))
+		Ω(itemOf(review, cmdTeam.TeamID, jane).Decision).To(Equal(domain.AccessReviewAutoRevoked))
+		Ω(itemOf(review, cmdTeam.TeamID, john).Decision).To(Equal(domain.AccessReviewUnreviewed))
+		Ω(sentTo(worker.AccessReviewClosedTemplate)).To(ConsistOf(john.Email))
+	})
+	It("Exports the decisions the user can see", func() {
+		admins := config.AppConfig.AppAdminEmails
+		config.AppConfig.AppAdminEmails = []string{jane.Email}
+		DeferCleanup(func() { config.AppConfig.AppAdminEmails = admins })
+
+		review := start(jane, false, cmdTeam.TeamID, cmdB.TeamID)
+		revoke := &command.ReviewAccess{ReviewID: review.ID, ItemID: itemOf(review, cmdB.TeamID, bob).ID, Decision: domain.AccessReviewRevoked, Note: "Left the project", User: alice}
+		Ω(handlers.ReviewAccess(ctx, revoke)).To(Succeed())
+
+		data, err := view.AccessReviewCSV(ctx, review.ID, jane)
+		Ω(err).To(Succeed())
+		records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+		Ω(err).To(Succeed())
+		Ω(records).To(HaveLen(5))
+		Ω(records[0][2]).To(Equal("user_email"))
+		Ω(records).To(ContainElement(ContainElements("Team B", bob.Email, "revoked", alice.Email, "Left the project")))
+
+		// alice only sees the memberships of team B
+		schema, err := view.AccessReview(ctx, review.ID, alice)
+		Ω(err).To(Succeed())
+		Ω(schema.Items).To(HaveLen(2))
+		Ω(schema.PendingItems).To(Equal(1))
+
+		_, err = view.AccessReview(ctx, review.ID, bob)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+
+		reviews, err := view.AccessReviews(ctx, domain.AccessReviewOptions{TeamID: cmdB.TeamID})
+		Ω(err).To(Succeed())
+		Ω(reviews.TotalData).To(BeEquivalentTo(1))
+	})
+})
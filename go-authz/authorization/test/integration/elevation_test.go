@@ -0,0 +1,233 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/view"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Elevation Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john      domain.User
+		jane      domain.User
+		bob       domain.User
+		cmdTeam   *command.CreateTeam
+		client    *worker.ClientMock
+		scheduler *worker.SchedulerMock
+	)
+
+	sentTo := func(template worker.EmailTemplate) []string {
+		var emails []string
+		for _, payload := range client.Sent {
+			if payload.TemplateName == template {
+				emails = append(emails, payload.To)
+			}
+		}
+		return emails
+	}
+
+	roleOf := func(user domain.User) domain.RoleType {
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, user.ID)
+		Ω(err).To(Succeed())
+		return membership.Role.Name
+	}
+
+	actions := func(request domain.ElevationRequest) []domain.ElevationAction {
+		var actions []domain.ElevationAction
+		for _, event := range request.Events {
+			actions = append(actions, event.Action)
+		}
+		return actions
+	}
+
+	request := func(user domain.User, role domain.RoleType, hours int) *command.RequestElevation {
+		cmd := &command.RequestElevation{TeamID: cmdTeam.TeamID, Role: role, Hours: hours, Justification: "Rotating the team keys", User: user}
+		Ω(handlers.RequestElevation(ctx, cmd)).To(Succeed())
+		return cmd
+	}
+
+	BeforeEach(func() {
+		client = worker.CreateMailerClientMock()
+		worker.CreateMailerMock(client)
+		scheduler = worker.CreateSchedulerMock()
+
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		bob = domain.NewUser("Bob", "Doe", "bobdoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, bob)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Admin)
+		addMember(ctx, cmdTeam.TeamID, bob, domain.Member)
+	})
+	It("Checks the requested role and duration", func() {
+		cmd := &command.RequestElevation{TeamID: cmdTeam.TeamID, Role: domain.Owner, Hours: 2, Justification: "Billing", User: bob}
+		Ω(handlers.RequestElevation(ctx, cmd)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		cmd = &command.RequestElevation{TeamID: cmdTeam.TeamID, Role: domain.Finance, Hours: 2, Justification: "Billing", User: bob}
+		Ω(handlers.RequestElevation(ctx, cmd)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		cmd = &command.RequestElevation{TeamID: cmdTeam.TeamID, Role: domain.Admin, Hours: 1000, Justification: "Billing", User: bob}
+		Ω(handlers.RequestElevation(ctx, cmd)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		cmd = &command.RequestElevation{TeamID: cmdTeam.TeamID, Role: domain.Admin, Hours: 2, Justification: " ", User: bob}
+		Ω(handlers.RequestElevation(ctx, cmd)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		request(bob, domain.Admin, 2)
+		Ω(sentTo(worker.ElevationRequestTemplate)).To(ConsistOf(john.Email))
+
+		// a single request can be open at a time
+		cmd = &command.RequestElevation{TeamID: cmdTeam.TeamID, Role: domain.Admin, Hours: 2, Justification: "Billing", User: bob}
+		Ω(handlers.RequestElevation(ctx, cmd)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+	})
+	It("Elevates the member once approved and reverts the role when it ends", func() {
+		cmd := request(bob, domain.Admin, 4)
+		Ω(roleOf(bob)).To(Equal(domain.Member))
+
+		// only the owners review the requests
+		approve := &command.ApproveElevation{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: jane}
+		Ω(handlers.ApproveElevation(ctx, approve)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		approve.User = john
+		Ω(handlers.ApproveElevation(ctx, approve)).To(Succeed())
+		Ω(roleOf(bob)).To(Equal(domain.Admin))
+		Ω(sentTo(worker.ElevationReviewedTemplate)).To(ConsistOf(bob.Email))
+
+		elevation, err := repository.Elevation.Get(ctx, cmd.RequestID)
+		Ω(err).To(Succeed())
+		Ω(elevation.Status).To(Equal(domain.ElevationActive))
+		Ω(elevation.EndsAt).ToNot(BeNil())
+		Ω(elevation.EndsAt.Sub(*elevation.ActivatedAt)).To(Equal(4 * time.Hour))
+		Ω(scheduler.ElevationReverts).To(HaveKey(cmd.RequestID))
+
+		access, err := repository.Role.GetAccess(ctx, cmdTeam.TeamID, bob.ID, "member:invite")
+		Ω(err).To(Succeed())
+		Ω(access.IsAllowed).To(BeTrue())
+
+		Ω(handlers.RevertElevation(ctx, &command.RevertElevation{RequestID: cmd.RequestID})).To(Succeed())
+		Ω(roleOf(bob)).To(Equal(domain.Member))
+
+		elevation, err = repository.Elevation.Get(ctx, cmd.RequestID)
+		Ω(err).To(Succeed())
+		Ω(elevation.Status).To(Equal(domain.ElevationEnded))
+		Ω(elevation.EndedAt).ToNot(BeNil())
+		Ω(actions(elevation)).To(Equal([]domain.ElevationAction{
+			domain.ElevationActionRequested,
+			domain.ElevationActionApproved,
+			domain.ElevationActionReverted,
+		}))
+		Ω(elevation.Events[1].Actor.Email).To(Equal(john.Email))
+		Ω(elevation.Events[2].ActorID.Valid).To(BeFalse())
+
+		// a task run twice changes nothing
+		Ω(handlers.RevertElevation(ctx, &command.RevertElevation{RequestID: cmd.RequestID})).To(Succeed())
+		Ω(roleOf(bob)).To(Equal(domain.Member))
+	})
+	It("Leaves a role changed during the elevation as is", func() {
+		cmd := request(bob, domain.Admin, 4)
+		Ω(handlers.ApproveElevation(ctx, &command.ApproveElevation{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: john})).To(Succeed())
+
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, bob.ID)
+		Ω(err).To(Succeed())
+		change := &command.ChangeMemberRole{TeamID: cmdTeam.TeamID, MembershipID: membership.ID, Role: domain.Finance, User: john}
+		Ω(handlers.ChangeMemberRole(ctx, change)).To(Succeed())
+
+		Ω(handlers.RevertElevation(ctx, &command.RevertElevation{RequestID: cmd.RequestID})).To(Succeed())
+		Ω(roleOf(bob)).To(Equal(domain.Finance))
+
+		elevation, err := repository.Elevation.Get(ctx, cmd.RequestID)
+		Ω(err).To(Succeed())
+		Ω(elevation.Status).To(Equal(domain.ElevationEnded))
+		Ω(elevation.Events[len(elevation.Events)-1].Note).ToNot(BeEmpty())
+	})
+	It("Lets the owners deny a request and the requester withdraw it", func() {
+		cmd := request(bob, domain.Admin, 2)
+		Ω(handlers.DenyElevation(ctx, &command.DenyElevation{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: john})).To(Succeed())
+		Ω(roleOf(bob)).To(Equal(domain.Member))
+		Ω(sentTo(worker.ElevationReviewedTemplate)).To(ConsistOf(bob.Email))
+
+		approve := &command.ApproveElevation{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: john}
+		Ω(handlers.ApproveElevation(ctx, approve)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		cmd = request(bob, domain.Admin, 2)
+		cancel := &command.CancelElevation{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: jane}
+		Ω(handlers.CancelElevation(ctx, cancel)).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+
+		cancel.User = bob
+		Ω(handlers.CancelElevation(ctx, cancel)).To(Succeed())
+
+		elevation, err := repository.Elevation.Get(ctx, cmd.RequestID)
+		Ω(err).To(Succeed())
+		Ω(elevation.Status).To(Equal(domain.ElevationCancelled))
+
+		requests, err := view.TeamElevations(ctx, domain.ElevationOptions{TeamID: cmdTeam.TeamID, Statuses: []domain.ElevationStatus{domain.ElevationDenied, domain.ElevationCancelled}})
+		Ω(err).To(Succeed())
+		Ω(requests.TotalData).To(BeEquivalentTo(2))
+	})
+	It("Ends an active elevation early when the requester gives it up", func() {
+		cmd := request(bob, domain.Admin, 8)
+		Ω(handlers.ApproveElevation(ctx, &command.ApproveElevation{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: john})).To(Succeed())
+		Ω(scheduler.ElevationReverts).To(HaveKey(cmd.RequestID))
+
+		Ω(handlers.CancelElevation(ctx, &command.CancelElevation{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: bob})).To(Succeed())
+		Ω(roleOf(bob)).To(Equal(domain.Member))
+		Ω(scheduler.ElevationReverts).ToNot(HaveKey(cmd.RequestID))
+
+		elevation, err := view.Elevation(ctx, cmdTeam.TeamID, cmd.RequestID, bob)
+		Ω(err).To(Succeed())
+		Ω(elevation.Status).To(Equal(string(domain.ElevationEnded)))
+		Ω(elevation.Events).To(HaveLen(3))
+		Ω(elevation.Events[2].Action).To(Equal(string(domain.ElevationActionRelinquished)))
+
+		// the other members cannot see the request
+		_, err = view.Elevation(ctx, cmdTeam.TeamID, cmd.RequestID, jane)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+	})
+	It("Approves the requests allowed by the auto-approval policies", func() {
+		policy := &command.CreatePolicy{
+			TeamID:     cmdTeam.TeamID,
+			Name:       "short admin elevations",
+			Permission: domain.ElevationAutoApprovePermission,
+			Expression: `role.name == "member" && request.role == "admin" && request.hours <= 4`,
+			User:       john,
+		}
+		Ω(handlers.CreatePolicy(ctx, policy)).To(Succeed())
+
+		long := request(bob, domain.Admin, 8)
+		Ω(roleOf(bob)).To(Equal(domain.Member))
+		Ω(handlers.CancelElevation(ctx, &command.CancelElevation{TeamID: cmdTeam.TeamID, RequestID: long.RequestID, User: bob})).To(Succeed())
+
+		short := request(bob, domain.Admin, 2)
+		Ω(roleOf(bob)).To(Equal(domain.Admin))
+		Ω(scheduler.ElevationReverts).To(HaveKey(short.RequestID))
+
+		elevation, err := repository.Elevation.Get(ctx, short.RequestID)
+		Ω(err).To(Succeed())
+		Ω(elevation.AutoApproved).To(BeTrue())
+		Ω(elevation.ReviewerID.Valid).To(BeFalse())
+		Ω(actions(elevation)).To(Equal([]domain.ElevationAction{
+			domain.ElevationActionRequested,
+			domain.ElevationActionAutoApproved,
+		}))
+	})
+})
@@ -0,0 +1,132 @@
+package integration
+
+import (
+	"authorization/config"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/util"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Invitation Claim Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john       domain.User
+		jane       domain.User
+		james      domain.User
+		cmdTeam    *command.CreateTeam
+		client     *worker.ClientMock
+		invitation domain.Invitation
+	)
+
+	sentTo := func(template worker.EmailTemplate) []string {
+		var emails []string
+		for _, payload := range client.Sent {
+			if payload.TemplateName == template {
+				emails = append(emails, payload.To)
+			}
+		}
+		return emails
+	}
+
+	token := func(invitation domain.Invitation) string {
+		token, err := util.CreateInvitationToken(invitation.ID, invitation.ExpiresAt, config.AppConfig.AccessTokenPrivateKey)
+		Ω(err).To(Succeed())
+		return token
+	}
+
+	BeforeEach(func() {
+		client = worker.CreateMailerClientMock()
+		worker.CreateMailerMock(client)
+
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		james = domain.NewUser("James", "Doe", "james.doe@gmail.com", "", "Google", true)
+		Ω(createUser(ctx, james)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Admin)
+
+		cmd := &command.SendInvitation{
+			TeamID:   cmdTeam.TeamID,
+			Invitees: []command.Invitee{{Email: "james@work.com", Role: domain.Member}},
+			Sender:   john,
+		}
+		Ω(handlers.SendInvitation(ctx, cmd)).To(Succeed())
+
+		invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID})
+		Ω(err).To(Succeed())
+		Ω(invitations).To(HaveLen(1))
+		invitation = invitations[0]
+	})
+	It("Signs the link of the invitation email", func() {
+		Ω(sentTo(worker.InvitationTemplate)).To(Equal([]string{"james@work.com"}))
+		Ω(client.Sent[0].Data["InvitationLink"]).To(ContainSubstring("?token="))
+
+		id, err := util.ValidateInvitationToken(token(invitation), config.AppConfig.AccessTokenPublicKey)
+		Ω(err).To(Succeed())
+		Ω(id).To(Equal(invitation.ID))
+
+		// an access token is not an invitation token
+		accessToken, err := util.CreateToken(james.ID, time.Minute, config.AppConfig.AccessTokenPrivateKey)
+		Ω(err).To(Succeed())
+		_, err = util.ValidateInvitationToken(*accessToken.Token, config.AppConfig.AccessTokenPublicKey)
+		Ω(err).To(HaveOccurred())
+	})
+	It("Claims the invitation with another address and tells the managers", func() {
+		cmd := &command.ClaimInvitation{Token: token(invitation), User: james}
+		Ω(handlers.ClaimInvitation(ctx, cmd)).To(Succeed())
+		Ω(cmd.TeamID).To(Equal(cmdTeam.TeamID))
+		Ω(cmd.EmailMismatch).To(BeTrue())
+
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, james.ID)
+		Ω(err).To(Succeed())
+		Ω(membership.Role.Name).To(Equal(domain.Member))
+
+		invitation, err = repository.Invitation.Get(ctx, invitation.ID)
+		Ω(err).To(Succeed())
+		Ω(invitation.Status).To(Equal(domain.InvitationStatusAccepted))
+
+		Ω(sentTo(worker.InvitationClaimedTemplate)).To(ConsistOf(john.Email, jane.Email))
+
+		// the token can't be used twice
+		other := domain.NewUser("Jim", "Doe", "jim@mail.com", "", "Google", true)
+		Ω(createUser(ctx, other)).To(Succeed())
+		Ω(handlers.ClaimInvitation(ctx, &command.ClaimInvitation{Token: cmd.Token, User: other})).NotTo(Succeed())
+	})
+	It("Claims the invitation with the invited address silently", func() {
+		invited := domain.NewUser("James", "Work", "james@work.com", "", "Google", true)
+		Ω(createUser(ctx, invited)).To(Succeed())
+
+		cmd := &command.ClaimInvitation{Token: token(invitation), User: invited}
+		Ω(handlers.ClaimInvitation(ctx, cmd)).To(Succeed())
+		Ω(cmd.EmailMismatch).To(BeFalse())
+		Ω(sentTo(worker.InvitationClaimedTemplate)).To(BeEmpty())
+	})
+	It("Refuses an invalid claim", func() {
+		Ω(handlers.ClaimInvitation(ctx, &command.ClaimInvitation{Token: "not-a-token", User: james})).NotTo(Succeed())
+
+		// a member of the team can't claim an invitation to it
+		Ω(handlers.ClaimInvitation(ctx, &command.ClaimInvitation{Token: token(invitation), User: jane})).NotTo(Succeed())
+
+		invitation.ExpiresAt = util.GetTimestampUTC().Add(-time.Minute)
+		Ω(handlers.ClaimInvitation(ctx, &command.ClaimInvitation{Token: token(invitation), User: james})).NotTo(Succeed())
+	})
+})
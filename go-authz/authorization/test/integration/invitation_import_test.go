@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"authorization/config"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Invitation Import Testing", Ordered, func() {
+	ctx := context.Background()
+	client := worker.CreateMailerClientMock()
+	worker.CreateMailerMock(client)
+
+	var (
+		john      domain.User
+		jane      domain.User
+		cmdTeam   *command.CreateTeam
+		scheduler *worker.SchedulerMock
+	)
+
+	statuses := func(results []domain.InvitationRowResult) []domain.InvitationRowStatus {
+		statuses := make([]domain.InvitationRowStatus, 0, len(results))
+		for _, result := range results {
+			statuses = append(statuses, result.Status)
+		}
+		return statuses
+	}
+
+	BeforeEach(func() {
+		scheduler = worker.CreateSchedulerMock()
+
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Admin)
+
+		cmd := &command.SendInvitation{
+			TeamID:   cmdTeam.TeamID,
+			Invitees: []command.Invitee{{Email: "james@mail.com", Role: domain.Member}},
+			Sender:   john,
+		}
+		Ω(handlers.SendInvitation(ctx, cmd)).To(Succeed())
+	})
+	It("Reads the invitees of a CSV file", func() {
+		rows, err := domain.ParseInvitationCSV(strings.NewReader("email,role\nalice@mail.com,Admin\nbob@mail.com\n"), 10)
+		Ω(err).To(Succeed())
+		Ω(rows).To(Equal([]domain.InvitationRow{
+			{Email: "alice@mail.com", Role: domain.Admin},
+			{Email: "bob@mail.com", Role: domain.Member},
+		}))
+
+		_, err = domain.ParseInvitationCSV(strings.NewReader("a@mail.com\nb@mail.com\nc@mail.com\n"), 2)
+		Ω(err).To(HaveOccurred())
+	})
+	It("Reports every row of a small import", func() {
+		cmd := &command.ImportInvitations{
+			TeamID: cmdTeam.TeamID,
+			Invitees: []command.Invitee{
+				{Email: "alice@mail.com", Role: domain.Member},
+				{Email: "JaneDoe@example.com", Role: domain.Member},
+				{Email: "james@mail.com", Role: domain.Member},
+				{Email: "not-an-email", Role: domain.Member},
+				{Email: "bob@mail.com", Role: "pilot"},
+				{Email: "carol@mail.com", Role: domain.Owner},
+				{Email: "ALICE@mail.com", Role: domain.Member},
+			},
+			Sender: jane,
+		}
+		Ω(handlers.ImportInvitations(ctx, cmd)).To(Succeed())
+		Ω(cmd.Async).To(BeFalse())
+		Ω(scheduler.InvitationImports).To(BeEmpty())
+
+		invitationImport, err := repository.InvitationImport.Get(ctx, cmd.ImportID)
+		Ω(err).To(Succeed())
+		Ω(invitationImport.Status).To(Equal(domain.InvitationImportCompleted))
+		Ω(statuses(invitationImport.Results)).To(Equal([]domain.InvitationRowStatus{
+			domain.InvitationRowCreated,
+			domain.InvitationRowSkippedMember,
+			domain.InvitationRowSkippedInvited,
+			domain.InvitationRowInvalid,
+			domain.InvitationRowInvalid,
+			domain.InvitationRowInvalid,
+			domain.InvitationRowSkippedInvited,
+		}))
+		Ω(invitationImport.Results[5].Reason).NotTo(BeEmpty())
+
+		summary := invitationImport.Parse().Summary
+		Ω(summary[string(domain.InvitationRowCreated)]).To(Equal(1))
+		Ω(summary[string(domain.InvitationRowInvalid)]).To(Equal(3))
+	})
+	It("Leaves a large import to the background task", func() {
+		limit := config.AppConfig.InvitationImportSyncLimit
+		config.AppConfig.InvitationImportSyncLimit = 1
+		DeferCleanup(func() { config.AppConfig.InvitationImportSyncLimit = limit })
+
+		cmd := &command.ImportInvitations{
+			TeamID: cmdTeam.TeamID,
+			Invitees: []command.Invitee{
+				{Email: "alice@mail.com", Role: domain.Member},
+				{Email: "bob@mail.com", Role: domain.Finance},
+			},
+			Sender: john,
+		}
+		Ω(handlers.ImportInvitations(ctx, cmd)).To(Succeed())
+		Ω(cmd.Async).To(BeTrue())
+		Ω(scheduler.InvitationImports).To(ConsistOf(cmd.ImportID))
+
+		invitationImport, err := repository.InvitationImport.Get(ctx, cmd.ImportID)
+		Ω(err).To(Succeed())
+		Ω(invitationImport.Status).To(Equal(domain.InvitationImportPending))
+		Ω(invitationImport.Results).To(BeEmpty())
+
+		process := &command.ProcessInvitationImport{ImportID: cmd.ImportID}
+		Ω(handlers.ProcessInvitationImport(ctx, process)).To(Succeed())
+
+		invitationImport, err = repository.InvitationImport.Get(ctx, cmd.ImportID)
+		Ω(err).To(Succeed())
+		Ω(invitationImport.Status).To(Equal(domain.InvitationImportCompleted))
+		Ω(statuses(invitationImport.Results)).To(Equal([]domain.InvitationRowStatus{
+			domain.InvitationRowCreated,
+			domain.InvitationRowCreated,
+		}))
+
+		// a task delivered twice leaves the report as it is
+		Ω(handlers.ProcessInvitationImport(ctx, process)).To(Succeed())
+		again, err := repository.InvitationImport.Get(ctx, cmd.ImportID)
+		Ω(err).To(Succeed())
+		Ω(again.Results).To(Equal(invitationImport.Results))
+	})
+})
@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/util"
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Invitation Lifecycle Testing", Ordered, func() {
+	ctx := context.Background()
+	client := worker.CreateMailerClientMock()
+	worker.CreateMailerMock(client)
+
+	var (
+		john    domain.User
+		james   domain.User
+		cmdTeam *command.CreateTeam
+	)
+
+	invite := func(email string) domain.Invitation {
+		cmd := &command.SendInvitation{
+			TeamID:   cmdTeam.TeamID,
+			Invitees: []command.Invitee{{Email: email, Role: domain.Member}},
+			Sender:   john,
+		}
+		Ω(handlers.SendInvitation(ctx, cmd)).To(Succeed())
+
+		invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID, Email: email})
+		Ω(err).To(Succeed())
+		Ω(invitations).To(HaveLen(1))
+		return invitations[0]
+	}
+
+	// backdate moves the expiry of the invitation to the past, as if it was sent long ago
+	backdate := func(invitation domain.Invitation) {
+		invitation.ExpiresAt = util.GetTimestampUTC().Add(-time.Hour)
+
+		tx, err := persistence.Pool.Begin(ctx)
+		Ω(err).To(Succeed())
+		defer tx.Rollback(ctx)
+
+		Ω(repository.Invitation.Update(ctx, invitation, tx)).To(Succeed())
+		Ω(tx.Commit(ctx)).To(Succeed())
+	}
+
+	answer := func(id ulid.ULID, status string) error {
+		return handlers.UpdateInvitationStatus(ctx, &command.UpdateInvitationStatus{InvitationID: id, Status: status, User: james})
+	}
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		james = domain.NewUser("James", "Doe", "james@mail.com", "", "Google", true)
+		Ω(createUser(ctx, james)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+	})
+	It("Allows only the transitions of the state machine", func() {
+		invitation := domain.NewInvitation("james@mail.com", domain.InvitationStatusPending, cmdTeam.TeamID, john.ID, ulid.Make(), 7*24*time.Hour)
+		Ω(invitation.Transition(domain.InvitationStatusSent)).To(Succeed())
+		Ω(invitation.Transition(domain.InvitationStatusPending)).NotTo(Succeed())
+		Ω(invitation.Transition(domain.InvitationStatusDeclined)).To(Succeed())
+		Ω(invitation.IsActive).To(BeFalse())
+		Ω(invitation.Transition(domain.InvitationStatusAccepted)).NotTo(Succeed())
+
+		invitation = domain.NewInvitation("james@mail.com", domain.InvitationStatusPending, cmdTeam.TeamID, john.ID, ulid.Make(), 7*24*time.Hour)
+		invitation.ExpiresAt = util.GetTimestampUTC().Add(-time.Minute)
+		Ω(invitation.Transition(domain.InvitationStatusAccepted)).NotTo(Succeed())
+		Ω(invitation.Transition(domain.InvitationStatusExpired)).To(Succeed())
+		Ω(invitation.ResendUpdate(7 * 24 * time.Hour)).To(Succeed())
+		Ω(invitation.Status).To(Equal(domain.InvitationStatusPending))
+		Ω(invitation.IsActive).To(BeTrue())
+		Ω(invitation.IsExpired()).To(BeFalse())
+	})
+	It("Accepts only an answer to the invitation", func() {
+		invitation := invite("james@mail.com")
+
+		Ω(answer(invitation.ID, string(domain.InvitationStatusSent))).NotTo(Succeed())
+		Ω(answer(invitation.ID, "joined")).NotTo(Succeed())
+		Ω(answer(invitation.ID, string(domain.InvitationStatusAccepted))).To(Succeed())
+
+		invitation, err := repository.Invitation.Get(ctx, invitation.ID)
+		Ω(err).To(Succeed())
+		Ω(invitation.Status).To(Equal(domain.InvitationStatusAccepted))
+		Ω(invitation.IsActive).To(BeFalse())
+	})
+	It("Expires the invitations past their expiry", func() {
+		stale := invite("james@mail.com")
+		fresh := invite("jane@mail.com")
+		backdate(stale)
+
+		cmd := &command.ExpireInvitations{}
+		Ω(handlers.ExpireInvitations(ctx, cmd)).To(Succeed())
+		Ω(cmd.Expired).To(Equal(int64(1)))
+
+		stale, err := repository.Invitation.Get(ctx, stale.ID)
+		Ω(err).To(Succeed())
+		Ω(stale.Status).To(Equal(domain.InvitationStatusExpired))
+		Ω(stale.IsActive).To(BeFalse())
+
+		fresh, err = repository.Invitation.Get(ctx, fresh.ID)
+		Ω(err).To(Succeed())
+		Ω(fresh.Status).To(Equal(domain.InvitationStatusPending))
+
+		// an expired invitation can't be accepted, it can be sent again
+		Ω(answer(stale.ID, string(domain.InvitationStatusAccepted))).NotTo(Succeed())
+
+		resend := &command.ResendInvitation{TeamID: cmdTeam.TeamID, InvitationID: stale.ID, Sender: john}
+		Ω(handlers.ResendInvitation(ctx, resend)).To(Succeed())
+		Ω(answer(stale.ID, string(domain.InvitationStatusAccepted))).To(Succeed())
+
+		// the sweep is idempotent
+		Ω(handlers.ExpireInvitations(ctx, cmd)).To(Succeed())
+		Ω(cmd.Expired).To(BeZero())
+	})
+	It("Resends an invitation past its expiry before the sweep", func() {
+		invitation := invite("james@mail.com")
+
+		resend := &command.ResendInvitation{TeamID: cmdTeam.TeamID, InvitationID: invitation.ID, Sender: john}
+		Ω(handlers.ResendInvitation(ctx, resend)).NotTo(Succeed())
+
+		backdate(invitation)
+		Ω(handlers.ResendInvitation(ctx, resend)).To(Succeed())
+
+		invitation, err := repository.Invitation.Get(ctx, invitation.ID)
+		Ω(err).To(Succeed())
+		Ω(invitation.Status).To(Equal(domain.InvitationStatusPending))
+		Ω(invitation.IsExpired()).To(BeFalse())
+	})
+})
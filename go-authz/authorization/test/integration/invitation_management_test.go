@@ -0,0 +1,178 @@
+package integration
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/domain/dto"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/view"
+	"context"
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Invitation Management Testing", Ordered, func() {
+	ctx := context.Background()
+	client := worker.CreateMailerClientMock()
+	worker.CreateMailerMock(client)
+
+	var (
+		john    domain.User
+		jane    domain.User
+		james   domain.User
+		cmdTeam *command.CreateTeam
+	)
+
+	invite := func(sender domain.User, email string, role domain.RoleType) ulid.ULID {
+		cmd := &command.SendInvitation{
+			TeamID:   cmdTeam.TeamID,
+			Invitees: []command.Invitee{{Email: email, Role: role}},
+			Sender:   sender,
+		}
+		Ω(handlers.SendInvitation(ctx, cmd)).To(Succeed())
+
+		invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID, Email: email})
+		Ω(err).To(Succeed())
+		Ω(invitations).To(HaveLen(1))
+		return invitations[0].ID
+	}
+
+	emails := func(page dto.CursorPagination) []string {
+		emails := make([]string, 0, len(page.Data))
+		for _, data := range page.Data {
+			emails = append(emails, data.(dto.InvitationSchema).Email)
+		}
+		return emails
+	}
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		james = domain.NewUser("James", "Doe", "james@mail.com", "", "Google", true)
+		Ω(createUser(ctx, james)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Admin)
+	})
+	It("Lists the invitations of the team with filters", func() {
+		for i := 1; i <= 5; i++ {
+			invite(john, fmt.Sprintf("guest%d@mail.com", i), domain.Member)
+		}
+		acceptedID := invite(john, "james@mail.com", domain.Member)
+		Ω(handlers.UpdateInvitationStatus(ctx, &command.UpdateInvitationStatus{InvitationID: acceptedID, Status: "accepted", User: james})).To(Succeed())
+
+		first, err := view.TeamInvitations(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID, Limit: 4, Descending: true})
+		Ω(err).To(Succeed())
+		Ω(int(first.TotalData)).To(Equal(6))
+		Ω(emails(first)).To(Equal([]string{"james@mail.com", "guest5@mail.com", "guest4@mail.com", "guest3@mail.com"}))
+		Ω(first.HasNext).To(BeTrue())
+
+		cursor, err := domain.DecodeCursor(first.Next)
+		Ω(err).To(Succeed())
+		second, err := view.TeamInvitations(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID, Limit: 4, Descending: true, Cursor: cursor})
+		Ω(err).To(Succeed())
+		Ω(emails(second)).To(Equal([]string{"guest2@mail.com", "guest1@mail.com"}))
+
+		accepted, err := view.TeamInvitations(ctx, domain.InvitationOptions{
+			TeamID:   cmdTeam.TeamID,
+			Statuses: []domain.InvitationStatus{domain.InvitationStatusAccepted},
+			Limit:    10,
+		})
+		Ω(err).To(Succeed())
+		Ω(emails(accepted)).To(Equal([]string{"james@mail.com"}))
+		Ω(accepted.Data[0].(dto.InvitationSchema).Role).To(Equal(string(domain.Member)))
+		Ω(accepted.Data[0].(dto.InvitationSchema).SenderName).To(Equal(john.FullName()))
+
+		search, err := view.TeamInvitations(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID, Query: "GUEST3", Limit: 10})
+		Ω(err).To(Succeed())
+		Ω(emails(search)).To(Equal([]string{"guest3@mail.com"}))
+
+		// the wildcards are matched literally
+		for _, query := range []string{"%", "guest_@"} {
+			search, err := view.TeamInvitations(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID, Query: query, Limit: 10})
+			Ω(err).To(Succeed())
+			Ω(search.Data).To(BeEmpty())
+		}
+	})
+	It("Shows the open invitations in the inbox of the invitee", func() {
+		otherTeam := &command.CreateTeam{Name: "Team B", Description: "Team B Description", User: jane}
+		createTeam(ctx, otherTeam, jane)
+
+		invite(john, "James@Mail.com", domain.Member)
+		Ω(handlers.SendInvitation(ctx, &command.SendInvitation{
+			TeamID:   otherTeam.TeamID,
+			Invitees: []command.Invitee{{Email: "james@mail.com", Role: domain.Admin}},
+			Sender:   jane,
+		})).To(Succeed())
+
+		inbox, err := view.UserInvitations(ctx, james, 10, nil)
+		Ω(err).To(Succeed())
+		Ω(inbox.Data).To(HaveLen(2))
+		Ω(inbox.Data[0].(dto.InvitationSchema).TeamName).To(Equal("Team B"))
+		Ω(inbox.Data[1].(dto.InvitationSchema).TeamName).To(Equal("Team A"))
+
+		// an answered invitation leaves the inbox
+		invitationID := inbox.Data[1].(dto.InvitationSchema).ID
+		Ω(handlers.UpdateInvitationStatus(ctx, &command.UpdateInvitationStatus{InvitationID: invitationID, Status: "declined", User: james})).To(Succeed())
+
+		inbox, err = view.UserInvitations(ctx, james, 10, nil)
+		Ω(err).To(Succeed())
+		Ω(inbox.Data).To(HaveLen(1))
+	})
+	It("Revokes invitations all or none", func() {
+		member := invite(john, "guest1@mail.com", domain.Member)
+		admin := invite(john, "guest2@mail.com", domain.Admin)
+
+		// an admin can't revoke the invitation of another admin
+		cmd := &command.RevokeInvitations{TeamID: cmdTeam.TeamID, InvitationIDs: []ulid.ULID{member, admin}, User: jane}
+		Ω(handlers.RevokeInvitations(ctx, cmd)).NotTo(Succeed())
+
+		count, err := repository.Invitation.Count(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID})
+		Ω(err).To(Succeed())
+		Ω(count).To(Equal(int64(2)))
+
+		cmd = &command.RevokeInvitations{TeamID: cmdTeam.TeamID, InvitationIDs: []ulid.ULID{ulid.Make()}, User: john}
+		Ω(handlers.RevokeInvitations(ctx, cmd)).NotTo(Succeed())
+
+		cmd = &command.RevokeInvitations{TeamID: cmdTeam.TeamID, InvitationIDs: []ulid.ULID{member, admin}, User: john}
+		Ω(handlers.RevokeInvitations(ctx, cmd)).To(Succeed())
+		Ω(cmd.Revoked).To(Equal(2))
+
+		count, err = repository.Invitation.Count(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID})
+		Ω(err).To(Succeed())
+		Ω(count).To(BeZero())
+	})
+	It("Changes the role of an open invitation", func() {
+		invitationID := invite(john, "james@mail.com", domain.Member)
+
+		cmd := &command.ChangeInvitationRole{TeamID: cmdTeam.TeamID, InvitationID: invitationID, Role: domain.Admin, User: jane}
+		Ω(handlers.ChangeInvitationRole(ctx, cmd)).NotTo(Succeed())
+
+		cmd.User = john
+		Ω(handlers.ChangeInvitationRole(ctx, cmd)).To(Succeed())
+
+		invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{IDs: []ulid.ULID{invitationID}})
+		Ω(err).To(Succeed())
+		Ω(invitations).To(HaveLen(1))
+		Ω(invitations[0].Role.Name).To(Equal(domain.Admin))
+
+		Ω(handlers.UpdateInvitationStatus(ctx, &command.UpdateInvitationStatus{InvitationID: invitationID, Status: "accepted", User: james})).To(Succeed())
+
+		cmd.Role = domain.Member
+		Ω(handlers.ChangeInvitationRole(ctx, cmd)).NotTo(Succeed())
+	})
+})
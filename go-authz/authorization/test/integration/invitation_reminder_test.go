@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Invitation Reminder Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john    domain.User
+		james   domain.User
+		cmdTeam *command.CreateTeam
+		client  *worker.ClientMock
+	)
+
+	invite := func(email string) domain.Invitation {
+		cmd := &command.SendInvitation{
+			TeamID:   cmdTeam.TeamID,
+			Invitees: []command.Invitee{{Email: email, Role: domain.Member}},
+			Sender:   john,
+		}
+		Ω(handlers.SendInvitation(ctx, cmd)).To(Succeed())
+
+		invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID, Email: email})
+		Ω(err).To(Succeed())
+		Ω(invitations).To(HaveLen(1))
+		return invitations[0]
+	}
+
+	reminders := func(invitation domain.Invitation) []time.Time {
+		var times []time.Time
+		for i := 0; i < domain.MaxInvitationReminders; i++ {
+			if scheduled, ok := client.Scheduled[fmt.Sprintf("invitation:remind:%s:%d", invitation.ID, i)]; ok {
+				Ω(scheduled.Payload.TemplateName).To(Equal(worker.InvitationReminderTemplate))
+				Ω(scheduled.Payload.To).To(Equal(invitation.Email))
+				times = append(times, scheduled.At)
+			}
+		}
+		return times
+	}
+
+	BeforeEach(func() {
+		client = worker.CreateMailerClientMock()
+		worker.CreateMailerMock(client)
+
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		james = domain.NewUser("James", "Doe", "james@mail.com", "", "Google", true)
+		Ω(createUser(ctx, james)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+	})
+	It("Validates the invitation settings of the team", func() {
+		_, err := domain.NewInvitationSettings(cmdTeam.TeamID, 0, nil)
+		Ω(err).To(HaveOccurred())
+
+		_, err = domain.NewInvitationSettings(cmdTeam.TeamID, 24, []int{24})
+		Ω(err).To(HaveOccurred())
+
+		_, err = domain.NewInvitationSettings(cmdTeam.TeamID, 48, []int{12, 12})
+		Ω(err).To(HaveOccurred())
+
+		_, err = domain.NewInvitationSettings(cmdTeam.TeamID, 240, []int{1, 2, 3, 4, 5, 6})
+		Ω(err).To(HaveOccurred())
+
+		settings, err := domain.NewInvitationSettings(cmdTeam.TeamID, 48, []int{1, 24})
+		Ω(err).To(Succeed())
+		Ω(settings.ReminderOffsets).To(Equal([]time.Duration{24 * time.Hour, time.Hour}))
+	})
+	It("Sends the invitations with the expiry and reminders of the team", func() {
+		settings, err := repository.InvitationSettings.Get(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(settings).To(Equal(domain.DefaultInvitationSettings(cmdTeam.TeamID)))
+
+		invitation := invite("guest1@mail.com")
+		Ω(invitation.ExpiresAt).To(BeTemporally("~", invitation.CreatedAt.Add(settings.ExpiresIn), time.Second))
+		Ω(reminders(invitation)).To(HaveLen(len(settings.ReminderOffsets)))
+
+		update := &command.UpdateInvitationSettings{TeamID: cmdTeam.TeamID, ExpiresInHours: 48, ReminderOffsetsHours: []int{24}, User: james}
+		Ω(handlers.UpdateInvitationSettings(ctx, update)).NotTo(Succeed())
+
+		update.User = john
+		Ω(handlers.UpdateInvitationSettings(ctx, update)).To(Succeed())
+
+		invitation = invite("guest2@mail.com")
+		Ω(invitation.ExpiresAt).To(BeTemporally("~", invitation.CreatedAt.Add(48*time.Hour), time.Second))
+		Ω(reminders(invitation)).To(ConsistOf(BeTemporally("~", invitation.ExpiresAt.Add(-24*time.Hour), time.Second)))
+	})
+	It("Cancels the reminders of an answered or deleted invitation", func() {
+		accepted := invite("james@mail.com")
+		deleted := invite("guest@mail.com")
+		Ω(reminders(accepted)).NotTo(BeEmpty())
+		Ω(reminders(deleted)).NotTo(BeEmpty())
+
+		Ω(handlers.UpdateInvitationStatus(ctx, &command.UpdateInvitationStatus{InvitationID: accepted.ID, Status: "accepted", User: james})).To(Succeed())
+		Ω(reminders(accepted)).To(BeEmpty())
+
+		Ω(handlers.DeleteInvitation(ctx, &command.DeleteInvitation{InvitationID: deleted.ID, User: john})).To(Succeed())
+		Ω(reminders(deleted)).To(BeEmpty())
+	})
+})
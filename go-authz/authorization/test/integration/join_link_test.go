@@ -0,0 +1,104 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/util"
+	"authorization/view"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Join Link Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john    domain.User
+		jane    domain.User
+		bob     domain.User
+		cmdTeam *command.CreateTeam
+	)
+
+	createLink := func(cmd *command.CreateJoinLink) domain.JoinLink {
+		cmd.TeamID = cmdTeam.TeamID
+		cmd.User = john
+		Ω(handlers.CreateJoinLink(ctx, cmd)).To(Succeed())
+
+		link, err := repository.JoinLink.Get(ctx, cmd.LinkID)
+		Ω(err).To(Succeed())
+		return link
+	}
+
+	redeem := func(link domain.JoinLink, user domain.User) error {
+		return handlers.RedeemJoinLink(ctx, &command.RedeemJoinLink{Token: link.Token, User: user})
+	}
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@acme.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		bob = domain.NewUser("Bob", "Doe", "bobdoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, bob)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+	})
+	It("Joins the team with the role of the link until it is used up", func() {
+		link := createLink(&command.CreateJoinLink{Role: domain.Admin, MaxUses: 1})
+
+		Ω(redeem(link, jane)).To(Succeed())
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(Succeed())
+		Ω(membership.Role.Name).To(Equal(domain.Admin))
+
+		Ω(redeem(link, jane)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+		Ω(redeem(link, bob)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		links, err := view.JoinLinks(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(links).To(HaveLen(1))
+		Ω(links[0].Uses).To(Equal(1))
+	})
+	It("Restricts the link to an email domain", func() {
+		link := createLink(&command.CreateJoinLink{Role: domain.Member, EmailDomain: "@Acme.com"})
+		Ω(link.EmailDomain).To(Equal("acme.com"))
+
+		Ω(redeem(link, bob)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+		Ω(redeem(link, jane)).To(Succeed())
+	})
+	It("Refuses revoked and expired links", func() {
+		link := createLink(&command.CreateJoinLink{Role: domain.Member})
+		err := handlers.RevokeJoinLink(ctx, &command.RevokeJoinLink{TeamID: cmdTeam.TeamID, LinkID: link.ID, User: john})
+		Ω(err).To(Succeed())
+		Ω(redeem(link, jane)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		past := util.GetTimestampUTC().Add(-time.Hour)
+		err = handlers.CreateJoinLink(ctx, &command.CreateJoinLink{TeamID: cmdTeam.TeamID, Role: domain.Member, ExpiresAt: &past, User: john})
+		Ω(err).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		_, err = repository.Membership.GetByUser(ctx, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+	})
+	It("Only hands out the roles the creator can assign", func() {
+		addMember(ctx, cmdTeam.TeamID, bob, domain.Member)
+
+		err := handlers.CreateJoinLink(ctx, &command.CreateJoinLink{TeamID: cmdTeam.TeamID, Role: domain.Owner, User: bob})
+		Ω(err).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		err = handlers.CreateJoinLink(ctx, &command.CreateJoinLink{TeamID: cmdTeam.TeamID, Role: domain.Member, User: jane})
+		Ω(err).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+	})
+})
@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/view"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Join Request Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john    domain.User
+		jane    domain.User
+		bob     domain.User
+		cmdTeam *command.CreateTeam
+		client  *worker.ClientMock
+	)
+
+	sentTo := func(template worker.EmailTemplate) []string {
+		var emails []string
+		for _, payload := range client.Sent {
+			if payload.TemplateName == template {
+				emails = append(emails, payload.To)
+			}
+		}
+		return emails
+	}
+
+	discoverable := func(isDiscoverable bool) {
+		cmd := &command.UpdateTeam{TeamID: cmdTeam.TeamID, IsDiscoverable: &isDiscoverable, User: john}
+		Ω(handlers.UpdateTeam(ctx, cmd)).To(Succeed())
+	}
+
+	request := func(user domain.User) *command.RequestToJoin {
+		cmd := &command.RequestToJoin{TeamID: cmdTeam.TeamID, Message: "Let me in", User: user}
+		Ω(handlers.RequestToJoin(ctx, cmd)).To(Succeed())
+		return cmd
+	}
+
+	BeforeEach(func() {
+		client = worker.CreateMailerClientMock()
+		worker.CreateMailerMock(client)
+
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		bob = domain.NewUser("Bob", "Doe", "bobdoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, bob)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Admin)
+	})
+	It("Accepts requests to a discoverable team only", func() {
+		cmd := &command.RequestToJoin{TeamID: cmdTeam.TeamID, User: bob}
+		Ω(handlers.RequestToJoin(ctx, cmd)).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+
+		discoverable(true)
+		team, err := repository.Team.Get(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(team.IsDiscoverable).To(BeTrue())
+
+		request(bob)
+		Ω(sentTo(worker.JoinRequestTemplate)).To(ConsistOf(john.Email, jane.Email))
+
+		// one pending request at a time, and none from a member
+		Ω(handlers.RequestToJoin(ctx, &command.RequestToJoin{TeamID: cmdTeam.TeamID, User: bob})).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+		Ω(handlers.RequestToJoin(ctx, &command.RequestToJoin{TeamID: cmdTeam.TeamID, User: jane})).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		requests, err := view.TeamJoinRequests(ctx, domain.JoinRequestOptions{TeamID: cmdTeam.TeamID, Limit: 10})
+		Ω(err).To(Succeed())
+		Ω(requests.Data).To(HaveLen(1))
+	})
+	It("Approves a request with a role the reviewer can assign", func() {
+		discoverable(true)
+		cmd := request(bob)
+
+		// an admin can't grant the admin role
+		approve := &command.ApproveJoinRequest{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, Role: domain.Admin, User: jane}
+		Ω(handlers.ApproveJoinRequest(ctx, approve)).NotTo(Succeed())
+
+		// nor can a user outside the team
+		approve = &command.ApproveJoinRequest{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: bob}
+		Ω(handlers.ApproveJoinRequest(ctx, approve)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		approve = &command.ApproveJoinRequest{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: jane}
+		Ω(handlers.ApproveJoinRequest(ctx, approve)).To(Succeed())
+
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, bob.ID)
+		Ω(err).To(Succeed())
+		Ω(membership.Role.Name).To(Equal(domain.Member))
+
+		joinRequest, err := repository.JoinRequest.Get(ctx, cmd.RequestID)
+		Ω(err).To(Succeed())
+		Ω(joinRequest.Status).To(Equal(domain.JoinRequestApproved))
+		Ω(joinRequest.Role.Name).To(Equal(domain.Member))
+		Ω(joinRequest.ReviewerID.UUID).To(Equal(jane.ID))
+		Ω(sentTo(worker.JoinRequestReviewedTemplate)).To(Equal([]string{bob.Email}))
+
+		// a reviewed request can't be reviewed again
+		deny := &command.DenyJoinRequest{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: john}
+		Ω(handlers.DenyJoinRequest(ctx, deny)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+	})
+	It("Denies and cancels requests", func() {
+		discoverable(true)
+		cmd := request(bob)
+
+		deny := &command.DenyJoinRequest{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: john}
+		Ω(handlers.DenyJoinRequest(ctx, deny)).To(Succeed())
+
+		_, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, bob.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+
+		// a denied user can ask again, and withdraw the request
+		cmd = request(bob)
+		Ω(handlers.CancelJoinRequest(ctx, &command.CancelJoinRequest{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: jane})).NotTo(Succeed())
+		Ω(handlers.CancelJoinRequest(ctx, &command.CancelJoinRequest{TeamID: cmdTeam.TeamID, RequestID: cmd.RequestID, User: bob})).To(Succeed())
+
+		requests, err := view.TeamJoinRequests(ctx, domain.JoinRequestOptions{TeamID: cmdTeam.TeamID, Limit: 10})
+		Ω(err).To(Succeed())
+		Ω(requests.Data).To(BeEmpty())
+
+		requests, err = view.TeamJoinRequests(ctx, domain.JoinRequestOptions{
+			TeamID:   cmdTeam.TeamID,
+			Statuses: []domain.JoinRequestStatus{domain.JoinRequestDenied, domain.JoinRequestCancelled},
+			Limit:    10,
+		})
+		Ω(err).To(Succeed())
+		Ω(requests.Data).To(HaveLen(2))
+	})
+})
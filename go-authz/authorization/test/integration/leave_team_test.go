@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+)
+
+var _ = Describe("Leave Team Testing", Ordered, func() {
+	ctx := context.Background()
+	client := worker.CreateMailerClientMock()
+	worker.CreateMailerMock(client)
+
+	var (
+		john    domain.User
+		jane    domain.User
+		cmdTeam *command.CreateTeam
+	)
+
+	lastActiveTeam := func(user domain.User) uuid.UUID {
+		var teamID uuid.UUID
+		err := persistence.Pool.QueryRow(ctx, `
+			SELECT team_id FROM memberships WHERE user_id = $1 ORDER BY last_active_at DESC NULLS LAST LIMIT 1
+		`, user.ID).Scan(&teamID)
+		Ω(err).To(Succeed())
+		return teamID
+	}
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Member)
+	})
+	It("Lets a member leave the team", func() {
+		_, err := persistence.Pool.Exec(ctx, `
+			UPDATE memberships SET last_active_at = NOW() + INTERVAL '1 hour' WHERE team_id = $1 AND user_id = $2
+		`, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(Succeed())
+		Ω(lastActiveTeam(jane)).To(Equal(cmdTeam.TeamID))
+
+		err = handlers.LeaveTeam(ctx, &command.LeaveTeam{TeamID: cmdTeam.TeamID, User: jane})
+		Ω(err).To(Succeed())
+
+		_, err = repository.Membership.GetByUser(ctx, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+		Ω(lastActiveTeam(jane)).NotTo(Equal(cmdTeam.TeamID))
+
+		err = handlers.LeaveTeam(ctx, &command.LeaveTeam{TeamID: cmdTeam.TeamID, User: jane})
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+	})
+	It("Keeps the last owner in the team", func() {
+		err := handlers.LeaveTeam(ctx, &command.LeaveTeam{TeamID: cmdTeam.TeamID, User: john})
+		Ω(err).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		james := domain.NewUser("James", "Doe", "jamesdoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, james)).To(Succeed())
+		addMember(ctx, cmdTeam.TeamID, james, domain.Owner)
+
+		err = handlers.LeaveTeam(ctx, &command.LeaveTeam{TeamID: cmdTeam.TeamID, User: john})
+		Ω(err).To(Succeed())
+
+		owners, err := repository.Membership.ListByRoles(ctx, cmdTeam.TeamID, domain.RoleTypes{domain.Owner})
+		Ω(err).To(Succeed())
+		Ω(owners).To(HaveLen(1))
+		Ω(owners[0].User.Email).To(Equal(james.Email))
+	})
+	It("Does not let a user leave the personal team", func() {
+		personal, err := repository.Team.ListByUser(ctx, domain.TeamOptions{UserID: jane.ID})
+		Ω(err).To(Succeed())
+
+		for _, membership := range personal {
+			if membership.Team.IsPersonal {
+				err = handlers.LeaveTeam(ctx, &command.LeaveTeam{TeamID: membership.TeamID, User: jane})
+				Ω(err).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+			}
+		}
+	})
+})
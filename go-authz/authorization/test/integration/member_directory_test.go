@@ -0,0 +1,135 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/domain/dto"
+	"authorization/view"
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+)
+
+var _ = Describe("Member Directory Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john    domain.User
+		cmdTeam *command.CreateTeam
+	)
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+
+		for i := 1; i <= 5; i++ {
+			user := domain.NewUser(fmt.Sprintf("Member%d", i), "Doe", fmt.Sprintf("member%d@example.com", i), "", "Google", true)
+			Ω(createUser(ctx, user)).To(Succeed())
+
+			role := domain.Member
+			if i == 5 {
+				role = domain.Admin
+			}
+			addMember(ctx, cmdTeam.TeamID, user, role)
+		}
+	})
+	It("Pages through the members with a cursor", func() {
+		seen := make(map[uuid.UUID]bool)
+		opts := domain.MembershipOptions{TeamID: cmdTeam.TeamID, SortBy: domain.SortByJoinedAt, Limit: 2}
+
+		for pages := 0; pages < 5; pages++ {
+			page, err := view.Members(ctx, opts)
+			Ω(err).To(Succeed())
+			Ω(page.TotalData).To(Equal(int64(6)))
+
+			for _, data := range page.Data {
+				member := data.(dto.MembershipRetrievalSchema)
+				Ω(seen).NotTo(HaveKey(member.ID))
+				seen[member.ID] = true
+			}
+
+			if !page.HasNext {
+				break
+			}
+
+			opts.Cursor, err = domain.DecodeCursor(page.Next)
+			Ω(err).To(Succeed())
+		}
+
+		Ω(seen).To(HaveLen(6))
+
+		_, err := domain.DecodeCursor("not-a-cursor")
+		Ω(err).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+	})
+	It("Walks back with the prev cursor", func() {
+		opts := domain.MembershipOptions{TeamID: cmdTeam.TeamID, Limit: 2}
+		first, err := view.Members(ctx, opts)
+		Ω(err).To(Succeed())
+		Ω(first.HasPrev).To(BeFalse())
+
+		opts.Cursor, err = domain.DecodeCursor(first.Next)
+		Ω(err).To(Succeed())
+		second, err := view.Members(ctx, opts)
+		Ω(err).To(Succeed())
+		Ω(second.HasPrev).To(BeTrue())
+
+		opts.Cursor, err = domain.DecodeCursor(second.Prev)
+		Ω(err).To(Succeed())
+		back, err := view.Members(ctx, opts)
+		Ω(err).To(Succeed())
+		Ω(back.Data).To(Equal(first.Data))
+		Ω(back.HasPrev).To(BeFalse())
+		Ω(back.HasNext).To(BeTrue())
+	})
+	It("Sorts the members by role", func() {
+		page, err := view.Members(ctx, domain.MembershipOptions{TeamID: cmdTeam.TeamID, SortBy: domain.SortByRole, Limit: 2})
+		Ω(err).To(Succeed())
+		Ω(page.Data).To(HaveLen(2))
+		Ω(page.Data[0].(dto.MembershipRetrievalSchema).Role).To(Equal(string(domain.Owner)))
+		Ω(page.Data[1].(dto.MembershipRetrievalSchema).Role).To(Equal(string(domain.Admin)))
+
+		opts := domain.MembershipOptions{TeamID: cmdTeam.TeamID, SortBy: domain.SortByRole, Limit: 10}
+		opts.Cursor, err = domain.DecodeCursor(page.Next)
+		Ω(err).To(Succeed())
+
+		page, err = view.Members(ctx, opts)
+		Ω(err).To(Succeed())
+		Ω(page.Data).To(HaveLen(4))
+		Ω(page.HasNext).To(BeFalse())
+	})
+	It("Searches and filters the members", func() {
+		page, err := view.Members(ctx, domain.MembershipOptions{TeamID: cmdTeam.TeamID, Name: "member3@", Limit: 10})
+		Ω(err).To(Succeed())
+		Ω(page.TotalData).To(Equal(int64(1)))
+
+		page, err = view.Members(ctx, domain.MembershipOptions{TeamID: cmdTeam.TeamID, Name: "Member", Role: domain.Member, Limit: 10})
+		Ω(err).To(Succeed())
+		Ω(page.TotalData).To(Equal(int64(4)))
+
+		// the wildcards of the search are matched literally
+		page, err = view.Members(ctx, domain.MembershipOptions{TeamID: cmdTeam.TeamID, Name: "%", Limit: 10})
+		Ω(err).To(Succeed())
+		Ω(page.TotalData).To(BeZero())
+
+		page, err = view.Members(ctx, domain.MembershipOptions{TeamID: cmdTeam.TeamID, Name: "member_@", Limit: 10})
+		Ω(err).To(Succeed())
+		Ω(page.TotalData).To(BeZero())
+
+		team, err := view.Team(ctx, cmdTeam.TeamID, john)
+		Ω(err).To(Succeed())
+		Ω(team.NumOfMembers).To(Equal(int64(6)))
+		Ω(team.NumOfRoles).To(HaveKeyWithValue(string(domain.Member), int64(4)))
+		Ω(len(team.Memberships)).To(BeNumerically("<", 6))
+	})
+})
@@ -0,0 +1,295 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/util"
+	"authorization/view"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+)
+
+var _ = Describe("Membership Expiry Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john    domain.User
+		jane    domain.User
+		bob     domain.User
+		cmdTeam *command.CreateTeam
+		client  *worker.ClientMock
+	)
+
+	sentTo := func(template worker.EmailTemplate) []string {
+		var emails []string
+		for _, payload := range client.Sent {
+			if payload.TemplateName == template {
+				emails = append(emails, payload.To)
+			}
+		}
+		return emails
+	}
+
+	// expireAt stores the expiry as is, the handlers refuse an expiry in the past
+	expireAt := func(user domain.User, at time.Time) {
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, user.ID)
+		Ω(err).To(Succeed())
+
+		tx, err := persistence.Pool.Begin(ctx)
+		Ω(err).To(Succeed())
+		defer tx.Rollback(ctx)
+
+		membership.ExpiresAt = &at
+		_, err = repository.Membership.Update(ctx, membership, tx)
+		Ω(err).To(Succeed())
+		Ω(tx.Commit(ctx)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		client = worker.CreateMailerClientMock()
+		worker.CreateMailerMock(client)
+
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		bob = domain.NewUser("Bob", "Doe", "bobdoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, bob)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Admin)
+		addMember(ctx, cmdTeam.TeamID, bob, domain.Member)
+	})
+	It("Ignores the memberships past their expiry", func() {
+		access, err := repository.Role.GetAccess(ctx, cmdTeam.TeamID, bob.ID, "team:read")
+		Ω(err).To(Succeed())
+		Ω(access.IsAllowed).To(BeTrue())
+
+		expireAt(bob, util.GetTimestampUTC().Add(-time.Minute))
+
+		access, err = repository.Role.GetAccess(ctx, cmdTeam.TeamID, bob.ID, "team:read")
+		Ω(err).To(Succeed())
+		Ω(access.RoleName).To(BeEmpty())
+		Ω(access.IsAllowed).To(BeFalse())
+
+		teams, err := view.Teams(ctx, bob, "Team A", 10, nil)
+		Ω(err).To(Succeed())
+		Ω(teams.TotalData).To(BeZero())
+
+		// the membership is gone from every query before the sweep removes it
+		_, err = repository.Membership.GetByUser(ctx, cmdTeam.TeamID, bob.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+
+		members, err := view.Members(ctx, domain.MembershipOptions{TeamID: cmdTeam.TeamID, Limit: 10})
+		Ω(err).To(Succeed())
+		Ω(members.TotalData).To(Equal(int64(2)))
+
+		usage, err := view.TeamUsage(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(usage.Members.Used).To(BeEquivalentTo(2))
+
+		allowed, err := view.Check(ctx, domain.TeamNamespace+":"+cmdTeam.TeamID.String(), string(domain.Member), domain.UserNamespace+":"+bob.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeFalse())
+
+		// adding the member again replaces the membership past its expiry
+		addMember(ctx, cmdTeam.TeamID, bob, domain.Member)
+		cmd := &command.ExpireMemberships{}
+		Ω(handlers.ExpireMemberships(ctx, cmd)).To(Succeed())
+		Ω(cmd.Expired).To(BeZero())
+
+		allowed, err = view.Check(ctx, domain.TeamNamespace+":"+cmdTeam.TeamID.String(), string(domain.Member), domain.UserNamespace+":"+bob.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+	})
+	It("Keeps the access of a member who rejoined after its membership expired", func() {
+		expireAt(bob, util.GetTimestampUTC().Add(-time.Minute))
+
+		cmd := &command.SendInvitation{
+			TeamID:   cmdTeam.TeamID,
+			Invitees: []command.Invitee{{Email: bob.Email, Role: domain.Member}},
+			Sender:   jane,
+		}
+		Ω(handlers.SendInvitation(ctx, cmd)).To(Succeed())
+		Ω(cmd.Results[0].Status).To(Equal(domain.InvitationRowCreated))
+
+		invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID, Email: bob.Email})
+		Ω(err).To(Succeed())
+		Ω(invitations).To(HaveLen(1))
+
+		accept := &command.UpdateInvitationStatus{InvitationID: invitations[0].ID, Status: "accepted", User: bob}
+		Ω(handlers.UpdateInvitationStatus(ctx, accept)).To(Succeed())
+
+		// the membership past its expiry is replaced, the sweep has nothing left to remove
+		sweep := &command.ExpireMemberships{}
+		Ω(handlers.ExpireMemberships(ctx, sweep)).To(Succeed())
+		Ω(sweep.Expired).To(BeZero())
+
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, bob.ID)
+		Ω(err).To(Succeed())
+		Ω(membership.ExpiresAt).To(BeNil())
+
+		access, err := repository.Role.GetAccess(ctx, cmdTeam.TeamID, bob.ID, "team:read")
+		Ω(err).To(Succeed())
+		Ω(access.IsAllowed).To(BeTrue())
+
+		allowed, err := view.Check(ctx, domain.TeamNamespace+":"+cmdTeam.TeamID.String(), string(domain.Member), domain.UserNamespace+":"+bob.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+	})
+	It("Keeps the expired membership of the last owner of the team", func() {
+		expireAt(john, util.GetTimestampUTC().Add(-time.Minute))
+
+		cmd := &command.ExpireMemberships{}
+		Ω(handlers.ExpireMemberships(ctx, cmd)).To(Succeed())
+		Ω(cmd.Expired).To(BeZero())
+
+		expired, err := repository.Membership.ListExpired(ctx, util.GetTimestampUTC(), 100)
+		Ω(err).To(Succeed())
+
+		var kept []uuid.UUID
+		for _, membership := range expired {
+			if membership.TeamID == cmdTeam.TeamID {
+				kept = append(kept, membership.UserID)
+			}
+		}
+		Ω(kept).To(Equal([]uuid.UUID{john.ID}))
+	})
+	It("Lets the members who can remove a member set its expiry", func() {
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, bob.ID)
+		Ω(err).To(Succeed())
+
+		expiresAt := util.GetTimestampUTC().Add(24 * time.Hour).Truncate(time.Second)
+		cmd := &command.SetMembershipExpiry{TeamID: cmdTeam.TeamID, MembershipID: membership.ID, ExpiresAt: &expiresAt, User: bob}
+		Ω(handlers.SetMembershipExpiry(ctx, cmd)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		past := util.GetTimestampUTC().Add(-time.Hour)
+		cmd = &command.SetMembershipExpiry{TeamID: cmdTeam.TeamID, MembershipID: membership.ID, ExpiresAt: &past, User: jane}
+		Ω(handlers.SetMembershipExpiry(ctx, cmd)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		cmd.ExpiresAt = &expiresAt
+		Ω(handlers.SetMembershipExpiry(ctx, cmd)).To(Succeed())
+
+		membership, err = repository.Membership.Get(ctx, membership.ID)
+		Ω(err).To(Succeed())
+		Ω(membership.ExpiresAt).ToNot(BeNil())
+		Ω(membership.ExpiresAt.Equal(expiresAt)).To(BeTrue())
+
+		// an admin cannot put an end to the membership of an owner
+		owner, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, john.ID)
+		Ω(err).To(Succeed())
+		cmd = &command.SetMembershipExpiry{TeamID: cmdTeam.TeamID, MembershipID: owner.ID, ExpiresAt: &expiresAt, User: jane}
+		Ω(handlers.SetMembershipExpiry(ctx, cmd)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		cmd = &command.SetMembershipExpiry{TeamID: cmdTeam.TeamID, MembershipID: membership.ID, User: jane}
+		Ω(handlers.SetMembershipExpiry(ctx, cmd)).To(Succeed())
+
+		membership, err = repository.Membership.Get(ctx, membership.ID)
+		Ω(err).To(Succeed())
+		Ω(membership.ExpiresAt).To(BeNil())
+	})
+	It("Grants a time-bound membership through an invitation", func() {
+		carol := domain.NewUser("Carol", "Doe", "caroldoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, carol)).To(Succeed())
+
+		expiresAt := util.GetTimestampUTC().Add(30 * 24 * time.Hour).Truncate(time.Second)
+		past := util.GetTimestampUTC().Add(-time.Hour)
+		cmd := &command.SendInvitation{
+			TeamID: cmdTeam.TeamID,
+			Invitees: []command.Invitee{
+				{Email: carol.Email, Role: domain.Guest, MembershipExpiresAt: &expiresAt},
+				{Email: "late@example.com", Role: domain.Guest, MembershipExpiresAt: &past},
+			},
+			Sender: jane,
+		}
+		Ω(handlers.SendInvitation(ctx, cmd)).To(Succeed())
+		Ω(cmd.Results[0].Status).To(Equal(domain.InvitationRowCreated))
+		Ω(cmd.Results[1].Status).To(Equal(domain.InvitationRowInvalid))
+
+		invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID, Email: carol.Email})
+		Ω(err).To(Succeed())
+		Ω(invitations).To(HaveLen(1))
+		Ω(invitations[0].MembershipExpiresAt).ToNot(BeNil())
+
+		accept := &command.UpdateInvitationStatus{InvitationID: invitations[0].ID, Status: "accepted", User: carol}
+		Ω(handlers.UpdateInvitationStatus(ctx, accept)).To(Succeed())
+
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, carol.ID)
+		Ω(err).To(Succeed())
+		Ω(membership.Role.Name).To(Equal(domain.Guest))
+		Ω(membership.ExpiresAt).ToNot(BeNil())
+		Ω(membership.ExpiresAt.Equal(expiresAt)).To(BeTrue())
+	})
+	It("Restricts the guests to the endpoints open to them", func() {
+		carol := domain.NewUser("Carol", "Doe", "caroldoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, carol)).To(Succeed())
+		addMember(ctx, cmdTeam.TeamID, carol, domain.Guest)
+
+		get := domain.NewEndpoint("get-team", "/auth/v1/teams/:id", "GET", "team:read")
+		get.AllowGuests = true
+		members := domain.NewEndpoint("get-members-team", "/auth/v1/teams/:id/members", "GET", "team:read")
+		endpoints := map[string]domain.Endpoint{get.Key(): get, members.Key(): members}
+
+		request := domain.AccessRequest{
+			UserID: carol.ID.String(),
+			Method: "GET",
+			Path:   "/auth/v1/teams/" + cmdTeam.TeamID.String(),
+		}
+		decision, err := view.Decide(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(decision.Allowed).To(BeTrue())
+
+		request.Path += "/members"
+		decision, err = view.Decide(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(decision.Allowed).To(BeFalse())
+		Ω(decision.Reason).To(Equal(domain.DecisionGuestRestricted))
+
+		// the members are not restricted
+		request.UserID = bob.ID.String()
+		decision, err = view.Decide(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(decision.Allowed).To(BeTrue())
+	})
+	It("Removes the expired memberships and lets everyone know", func() {
+		expireAt(bob, util.GetTimestampUTC().Add(-time.Minute))
+		expireAt(jane, util.GetTimestampUTC().Add(time.Hour))
+
+		cmd := &command.ExpireMemberships{}
+		Ω(handlers.ExpireMemberships(ctx, cmd)).To(Succeed())
+		Ω(cmd.Expired).To(Equal(1))
+
+		_, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, bob.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+		_, err = repository.Membership.GetByUser(ctx, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(Succeed())
+
+		allowed, err := view.Check(ctx, domain.TeamNamespace+":"+cmdTeam.TeamID.String(), string(domain.Member), domain.UserNamespace+":"+bob.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeFalse())
+
+		Ω(sentTo(worker.MembershipEndedTemplate)).To(ConsistOf(bob.Email))
+		Ω(sentTo(worker.MembershipExpiredTemplate)).To(ConsistOf(john.Email, jane.Email))
+
+		// a second sweep finds nothing left to remove
+		cmd = &command.ExpireMemberships{}
+		Ω(handlers.ExpireMemberships(ctx, cmd)).To(Succeed())
+		Ω(cmd.Expired).To(BeZero())
+	})
+})
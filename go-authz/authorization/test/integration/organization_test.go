@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/view"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Organization Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john    domain.User
+		jane    domain.User
+		cmdOrg  *command.CreateOrganization
+		cmdTeam *command.CreateTeam
+	)
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		err := createUser(ctx, john)
+		Ω(err).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		err = createUser(ctx, jane)
+		Ω(err).To(Succeed())
+
+		cmdOrg = &command.CreateOrganization{
+			Name:        "Company",
+			Description: "Company Description",
+			User:        john,
+		}
+		err = handlers.CreateOrganization(ctx, cmdOrg)
+		Ω(err).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+
+		err = handlers.AttachOrganizationTeam(ctx, &command.AttachOrganizationTeam{
+			OrganizationID: cmdOrg.OrganizationID,
+			TeamID:         cmdTeam.TeamID,
+			User:           john,
+		})
+		Ω(err).To(Succeed())
+	})
+	It("Get organization", func() {
+		organization, err := view.Organization(ctx, cmdOrg.OrganizationID)
+		Ω(err).To(Succeed())
+		Ω(organization.Name).To(Equal("Company"))
+		Ω(organization.Memberships).To(HaveLen(1))
+		Ω(organization.Teams).To(HaveLen(1))
+
+		organizations, err := view.Organizations(ctx, john)
+		Ω(err).To(Succeed())
+		Ω(organizations).To(HaveLen(1))
+		Ω(organizations[0].Role).To(Equal(string(domain.Owner)))
+	})
+	It("Organization admin inherits team admin", func() {
+		access, err := repository.Role.GetAccess(ctx, cmdTeam.TeamID, jane.ID, "member:invite")
+		Ω(err).To(Succeed())
+		Ω(access.IsAllowed).To(BeFalse())
+
+		err = handlers.AddOrganizationMember(ctx, &command.AddOrganizationMember{
+			OrganizationID: cmdOrg.OrganizationID,
+			Email:          jane.Email,
+			Role:           domain.Admin,
+			User:           john,
+		})
+		Ω(err).To(Succeed())
+
+		access, err = repository.Role.GetAccess(ctx, cmdTeam.TeamID, jane.ID, "member:invite")
+		Ω(err).To(Succeed())
+		Ω(access.IsAllowed).To(BeTrue())
+		Ω(access.RoleName).To(Equal(domain.Admin))
+		Ω(access.Source).To(Equal(domain.AccessSourceOrganization))
+
+		allowed, err := view.Check(ctx, "team:"+cmdTeam.TeamID.String(), "admin", "user:"+jane.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+
+		access, err = repository.Role.GetAccess(ctx, cmdTeam.TeamID, john.ID, "member:invite")
+		Ω(err).To(Succeed())
+		Ω(access.RoleName).To(Equal(domain.Owner))
+		Ω(access.Source).To(Equal(domain.AccessSourceTeam))
+	})
+	It("Organization member does not inherit team access", func() {
+		err := handlers.AddOrganizationMember(ctx, &command.AddOrganizationMember{
+			OrganizationID: cmdOrg.OrganizationID,
+			Email:          jane.Email,
+			Role:           domain.Member,
+			User:           john,
+		})
+		Ω(err).To(Succeed())
+
+		access, err := repository.Role.GetAccess(ctx, cmdTeam.TeamID, jane.ID, "team:read")
+		Ω(err).To(Succeed())
+		Ω(access.IsAllowed).To(BeFalse())
+	})
+	It("Detach team", func() {
+		err := handlers.DetachOrganizationTeam(ctx, &command.DetachOrganizationTeam{
+			OrganizationID: cmdOrg.OrganizationID,
+			TeamID:         cmdTeam.TeamID,
+			User:           john,
+		})
+		Ω(err).To(Succeed())
+
+		team, err := repository.Team.Get(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(team.OrganizationID.Valid).To(BeFalse())
+	})
+})
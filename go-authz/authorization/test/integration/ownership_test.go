@@ -0,0 +1,180 @@
+package integration
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/util"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ownership Transfer Testing", Ordered, func() {
+	ctx := context.Background()
+	client := worker.CreateMailerClientMock()
+	worker.CreateMailerMock(client)
+
+	var (
+		john    domain.User
+		jane    domain.User
+		cmdTeam *command.CreateTeam
+	)
+
+	roleOf := func(user domain.User) domain.RoleType {
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, user.ID)
+		Ω(err).To(Succeed())
+		return membership.Role.Name
+	}
+
+	propose := func(keepOwnership bool) *command.ProposeOwnershipTransfer {
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(Succeed())
+
+		cmd := &command.ProposeOwnershipTransfer{
+			TeamID:        cmdTeam.TeamID,
+			MembershipID:  membership.ID,
+			KeepOwnership: keepOwnership,
+			User:          john,
+		}
+		err = handlers.ProposeOwnershipTransfer(ctx, cmd)
+		Ω(err).To(Succeed())
+		return cmd
+	}
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Member)
+	})
+	It("Transfers the ownership once accepted", func() {
+		cmd := propose(false)
+
+		err := handlers.RespondOwnershipTransfer(ctx, &command.RespondOwnershipTransfer{
+			TransferID: cmd.TransferID,
+			Accept:     true,
+			User:       john,
+		})
+		Ω(err).To(HaveOccurred())
+		Ω(roleOf(jane)).To(Equal(domain.Member))
+
+		err = handlers.RespondOwnershipTransfer(ctx, &command.RespondOwnershipTransfer{
+			TransferID: cmd.TransferID,
+			Accept:     true,
+			User:       jane,
+		})
+		Ω(err).To(Succeed())
+		Ω(roleOf(jane)).To(Equal(domain.Owner))
+		Ω(roleOf(john)).To(Equal(domain.Admin))
+	})
+	It("Keeps co-owners and at least one owner", func() {
+		err := handlers.RelinquishOwnership(ctx, &command.RelinquishOwnership{TeamID: cmdTeam.TeamID, User: john})
+		Ω(err).To(HaveOccurred())
+		Ω(roleOf(john)).To(Equal(domain.Owner))
+
+		cmd := propose(true)
+
+		err = handlers.RespondOwnershipTransfer(ctx, &command.RespondOwnershipTransfer{
+			TransferID: cmd.TransferID,
+			Accept:     true,
+			User:       jane,
+		})
+		Ω(err).To(Succeed())
+		Ω(roleOf(jane)).To(Equal(domain.Owner))
+		Ω(roleOf(john)).To(Equal(domain.Owner))
+
+		err = handlers.RelinquishOwnership(ctx, &command.RelinquishOwnership{TeamID: cmdTeam.TeamID, User: john})
+		Ω(err).To(Succeed())
+		Ω(roleOf(john)).To(Equal(domain.Admin))
+	})
+	It("Allows a single pending transfer", func() {
+		cmd := propose(false)
+
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(Succeed())
+		err = handlers.ProposeOwnershipTransfer(ctx, &command.ProposeOwnershipTransfer{
+			TeamID:       cmdTeam.TeamID,
+			MembershipID: membership.ID,
+			User:         john,
+		})
+		Ω(err).To(HaveOccurred())
+
+		err = handlers.CancelOwnershipTransfer(ctx, &command.CancelOwnershipTransfer{
+			TeamID:     cmdTeam.TeamID,
+			TransferID: cmd.TransferID,
+			User:       john,
+		})
+		Ω(err).To(Succeed())
+
+		err = handlers.RespondOwnershipTransfer(ctx, &command.RespondOwnershipTransfer{
+			TransferID: cmd.TransferID,
+			Accept:     true,
+			User:       jane,
+		})
+		Ω(err).To(HaveOccurred())
+		Ω(roleOf(jane)).To(Equal(domain.Member))
+	})
+	It("Makes a time-bound member a permanent owner", func() {
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(Succeed())
+
+		expiresAt := util.GetTimestampUTC().Add(24 * time.Hour)
+		err = handlers.SetMembershipExpiry(ctx, &command.SetMembershipExpiry{
+			TeamID:       cmdTeam.TeamID,
+			MembershipID: membership.ID,
+			ExpiresAt:    &expiresAt,
+			User:         john,
+		})
+		Ω(err).To(Succeed())
+
+		cmd := propose(false)
+		err = handlers.RespondOwnershipTransfer(ctx, &command.RespondOwnershipTransfer{
+			TransferID: cmd.TransferID,
+			Accept:     true,
+			User:       jane,
+		})
+		Ω(err).To(Succeed())
+
+		membership, err = repository.Membership.GetByUser(ctx, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(Succeed())
+		Ω(membership.Role.Name).To(Equal(domain.Owner))
+		Ω(membership.ExpiresAt).To(BeNil())
+	})
+	It("Replaces a pending transfer past its expiry", func() {
+		expired := domain.NewOwnershipTransfer(cmdTeam.TeamID, john.ID, jane.ID, false)
+		expired.ExpiresAt = util.GetTimestampUTC().Add(-time.Hour)
+
+		tx, err := persistence.Pool.Begin(ctx)
+		Ω(err).To(Succeed())
+		defer tx.Rollback(ctx)
+		_, err = repository.OwnershipTransfer.Add(ctx, expired, tx)
+		Ω(err).To(Succeed())
+		Ω(tx.Commit(ctx)).To(Succeed())
+
+		cmd := propose(false)
+
+		transfer, err := repository.OwnershipTransfer.Get(ctx, expired.ID)
+		Ω(err).To(Succeed())
+		Ω(transfer.Status).To(Equal(domain.OwnershipTransferStatusExpired))
+
+		transfer, err = repository.OwnershipTransfer.GetPendingByTeam(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(transfer.ID).To(Equal(cmd.TransferID))
+	})
+})
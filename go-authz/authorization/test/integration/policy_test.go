@@ -0,0 +1,127 @@
+package integration
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/service/handlers"
+	"authorization/view"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john      domain.User
+		cmdTeam   *command.CreateTeam
+		endpoints map[string]domain.Endpoint
+		request   domain.AccessRequest
+	)
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		err := createUser(ctx, john)
+		Ω(err).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+
+		endpoint := domain.NewEndpoint("update-team", "/auth/v1/teams/:id", "PUT", "team:update")
+		endpoints = map[string]domain.Endpoint{endpoint.Key(): endpoint}
+		request = domain.AccessRequest{
+			UserID: john.ID.String(),
+			Method: "PUT",
+			Path:   "/auth/v1/teams/" + cmdTeam.TeamID.String(),
+			IP:     "192.168.1.10",
+		}
+	})
+	It("Rejects an invalid expression", func() {
+		err := handlers.CreatePolicy(ctx, &command.CreatePolicy{
+			TeamID:     cmdTeam.TeamID,
+			Name:       "broken",
+			Permission: "team:update",
+			Expression: "request.ip ==",
+			User:       john,
+		})
+		Ω(err).To(HaveOccurred())
+	})
+	It("Rejects an unknown permission", func() {
+		err := handlers.CreatePolicy(ctx, &command.CreatePolicy{
+			TeamID:     cmdTeam.TeamID,
+			Name:       "unknown",
+			Permission: "team:unknown",
+			Expression: "true",
+			User:       john,
+		})
+		Ω(err).To(HaveOccurred())
+	})
+	It("Restricts access by IP range", func() {
+		allowed, err := view.Authorization(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+
+		cmd := &command.CreatePolicy{
+			TeamID:     cmdTeam.TeamID,
+			Name:       "office only",
+			Permission: "team:update",
+			Expression: `inCidr(request.ip, "10.0.0.0/8")`,
+			User:       john,
+		}
+		err = handlers.CreatePolicy(ctx, cmd)
+		Ω(err).To(Succeed())
+
+		allowed, err = view.Authorization(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeFalse())
+
+		request.IP = "10.1.2.3"
+		allowed, err = view.Authorization(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+
+		isActive := false
+		err = handlers.UpdatePolicy(ctx, &command.UpdatePolicy{
+			TeamID:   cmdTeam.TeamID,
+			PolicyID: cmd.PolicyID,
+			IsActive: &isActive,
+			User:     john,
+		})
+		Ω(err).To(Succeed())
+
+		policies, err := view.Policies(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(policies).To(HaveLen(1))
+		Ω(policies[0].IsActive).To(BeFalse())
+	})
+	It("Explains the decision", func() {
+		decision, err := view.Decide(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(decision.Allowed).To(BeTrue())
+		Ω(decision.Reason).To(Equal(domain.DecisionPermissionGranted))
+		Ω(decision.Role).To(Equal(string(domain.Owner)))
+		Ω(decision.TeamID).To(Equal(cmdTeam.TeamID.String()))
+
+		stranger := domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		err = createUser(ctx, stranger)
+		Ω(err).To(Succeed())
+
+		request.UserID = stranger.ID.String()
+		decision, err = view.Decide(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(decision.Allowed).To(BeFalse())
+		Ω(decision.Reason).To(Equal(domain.DecisionNoMembership))
+
+		request.Path = "/auth/v1/users/me"
+		decision, err = view.Decide(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(decision.Allowed).To(BeTrue())
+		Ω(decision.Reason).To(Equal(domain.DecisionEndpointNotMatched))
+	})
+})
@@ -0,0 +1,127 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/service/handlers"
+	"authorization/view"
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+)
+
+var _ = Describe("Relation Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john        domain.User
+		jane        domain.User
+		cmdTeam     *command.CreateTeam
+		application string
+	)
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		err := createUser(ctx, john)
+		Ω(err).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		err = createUser(ctx, jane)
+		Ω(err).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+
+		application = "application:" + cmdTeam.TeamID.String() + "/" + uuid.NewV4().String()
+	})
+	It("Mirrors team membership", func() {
+		team := "team:" + cmdTeam.TeamID.String()
+
+		allowed, err := view.Check(ctx, team, "owner", "user:"+john.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+
+		allowed, err = view.Check(ctx, team, "member", "user:"+john.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+
+		allowed, err = view.Check(ctx, team, "member", "user:"+jane.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeFalse())
+	})
+	It("Inherits application access from the parent team", func() {
+		err := handlers.WriteRelation(ctx, &command.WriteRelation{
+			Tuple: application + "#parent@team:" + cmdTeam.TeamID.String(),
+			User:  john,
+		})
+		Ω(err).To(Succeed())
+
+		allowed, err := view.Check(ctx, application, "editor", "user:"+john.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+
+		allowed, err = view.Check(ctx, application, "viewer", "user:"+jane.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeFalse())
+
+		err = handlers.WriteRelation(ctx, &command.WriteRelation{
+			Tuple: application + "#viewer@user:" + jane.ID.String(),
+			User:  john,
+		})
+		Ω(err).To(Succeed())
+
+		allowed, err = view.Check(ctx, application, "viewer", "user:"+jane.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+	})
+	It("Lists the objects inherited from the teams", func() {
+		err := handlers.WriteRelation(ctx, &command.WriteRelation{
+			Tuple: application + "#parent@team:" + cmdTeam.TeamID.String(),
+			User:  john,
+		})
+		Ω(err).To(Succeed())
+
+		objectIDs, err := view.ListObjects(ctx, "application", "viewer", "user:"+john.ID.String())
+		Ω(err).To(Succeed())
+		Ω(objectIDs).To(ConsistOf(strings.TrimPrefix(application, "application:")))
+
+		objectIDs, err = view.ListObjects(ctx, "application", "viewer", "user:"+jane.ID.String())
+		Ω(err).To(Succeed())
+		Ω(objectIDs).To(BeEmpty())
+	})
+	It("Only lets a team claim the objects it owns", func() {
+		cmdOther := &command.CreateTeam{
+			Name:        "Team B",
+			Description: "Team B Description",
+			User:        jane,
+		}
+		createTeam(ctx, cmdOther, jane)
+
+		err := handlers.WriteRelation(ctx, &command.WriteRelation{
+			Tuple: application + "#parent@team:" + cmdOther.TeamID.String(),
+			User:  jane,
+		})
+		Ω(err).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		err = handlers.WriteRelation(ctx, &command.WriteRelation{
+			Tuple: application + "#viewer@team:" + cmdTeam.TeamID.String() + "#member",
+			User:  john,
+		})
+		Ω(err).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+	})
+	It("Rejects writes on mirrored namespaces", func() {
+		err := handlers.WriteRelation(ctx, &command.WriteRelation{
+			Tuple: "team:" + cmdTeam.TeamID.String() + "#admin@user:" + jane.ID.String(),
+			User:  john,
+		})
+		Ω(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Role Hierarchy Testing", Ordered, func() {
+	ctx := context.Background()
+	client := worker.CreateMailerClientMock()
+	worker.CreateMailerMock(client)
+
+	var (
+		john    domain.User
+		jane    domain.User
+		bob     domain.User
+		cmdTeam *command.CreateTeam
+	)
+
+	membershipOf := func(user domain.User) domain.Membership {
+		var membership domain.Membership
+		row := Pool.QueryRow(ctx, `SELECT id FROM memberships WHERE team_id = $1 AND user_id = $2`, cmdTeam.TeamID, user.ID)
+		Ω(row.Scan(&membership.ID)).To(Succeed())
+		membership, err := repository.Membership.Get(ctx, membership.ID)
+		Ω(err).To(Succeed())
+		return membership
+	}
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		bob = domain.NewUser("Bob", "Doe", "bobdoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, bob)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Admin)
+		addMember(ctx, cmdTeam.TeamID, bob, domain.Member)
+	})
+	It("Admin cannot invite an owner or an admin", func() {
+		for _, role := range []domain.RoleType{domain.Owner, domain.Admin} {
+			err := handlers.SendInvitation(ctx, &command.SendInvitation{
+				TeamID:   cmdTeam.TeamID,
+				Invitees: []command.Invitee{{Email: "james@mail.com", Role: role}},
+				Sender:   jane,
+			})
+			Ω(err).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+		}
+
+		err := handlers.SendInvitation(ctx, &command.SendInvitation{
+			TeamID:   cmdTeam.TeamID,
+			Invitees: []command.Invitee{{Email: "james@mail.com", Role: domain.Member}},
+			Sender:   jane,
+		})
+		Ω(err).To(Succeed())
+	})
+	It("Admin cannot promote a member to admin nor touch the owner", func() {
+		err := handlers.ChangeMemberRole(ctx, &command.ChangeMemberRole{
+			TeamID:       cmdTeam.TeamID,
+			MembershipID: membershipOf(bob).ID,
+			Role:         domain.Admin,
+			User:         jane,
+		})
+		Ω(err).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		err = handlers.DeleteTeamMember(ctx, &command.DeleteTeamMember{
+			TeamID:       cmdTeam.TeamID,
+			MembershipID: membershipOf(john).ID,
+			User:         jane,
+		})
+		Ω(err).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		err = handlers.ChangeMemberRole(ctx, &command.ChangeMemberRole{
+			TeamID:       cmdTeam.TeamID,
+			MembershipID: membershipOf(bob).ID,
+			Role:         domain.Finance,
+			User:         jane,
+		})
+		Ω(err).To(Succeed())
+	})
+	It("Owner manages admins but cannot hand out ownership", func() {
+		err := handlers.ChangeMemberRole(ctx, &command.ChangeMemberRole{
+			TeamID:       cmdTeam.TeamID,
+			MembershipID: membershipOf(bob).ID,
+			Role:         domain.Owner,
+			User:         john,
+		})
+		Ω(err).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		err = handlers.DeleteTeamMember(ctx, &command.DeleteTeamMember{
+			TeamID:       cmdTeam.TeamID,
+			MembershipID: membershipOf(jane).ID,
+			User:         john,
+		})
+		Ω(err).To(Succeed())
+	})
+})
@@ -2,9 +2,11 @@ package integration
 
 import (
 	"authorization/config"
+	"authorization/domain"
 	"authorization/infrastructure/persistence"
 	"authorization/infrastructure/seeder"
 	"authorization/repository"
+	"authorization/view"
 	"context"
 	"fmt"
 	"os"
@@ -60,6 +62,9 @@ var _ = BeforeEach(func() {
 
 	persistence.Migration(Pool)
 	repository.CreateRepositories()
+	view.LoadNamespaces()
+	domain.LoadRoleHierarchy()
+	domain.LoadPlans()
 	seeder.Execute(Pool, "AccessSeed")
 })
 
@@ -0,0 +1,172 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/view"
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Team Archival Testing", Ordered, func() {
+	ctx := context.Background()
+	client := worker.CreateMailerClientMock()
+	worker.CreateMailerMock(client)
+
+	var (
+		john      domain.User
+		jane      domain.User
+		cmdTeam   *command.CreateTeam
+		scheduler *worker.SchedulerMock
+	)
+
+	archive := func() {
+		err := handlers.ArchiveTeam(ctx, &command.ArchiveTeam{TeamID: cmdTeam.TeamID, User: john})
+		Ω(err).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		scheduler = worker.CreateSchedulerMock()
+
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+
+		addMember(ctx, cmdTeam.TeamID, jane, domain.Member)
+	})
+	It("Hides an archived team and schedules its deletion", func() {
+		archive()
+
+		team, err := repository.Team.Get(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(team.IsArchived()).To(BeTrue())
+		Ω(scheduler.PurgeTeams).To(HaveKeyWithValue(team.ID, *team.PurgeAt))
+
+		teams, err := view.Teams(ctx, jane, "Team A", 10, nil)
+		Ω(err).To(Succeed())
+		Ω(teams.TotalData).To(BeZero())
+
+		archived, err := view.ArchivedTeams(ctx, jane, "Team A", 10, nil)
+		Ω(err).To(Succeed())
+		Ω(archived.TotalData).To(Equal(int64(1)))
+
+		err = handlers.ArchiveTeam(ctx, &command.ArchiveTeam{TeamID: cmdTeam.TeamID, User: john})
+		Ω(err).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+	})
+	It("Keeps an archived team read-only", func() {
+		archive()
+
+		update := domain.NewEndpoint("update-team", "/auth/v1/teams/:id", "PUT", "team:update")
+		get := domain.NewEndpoint("get-team", "/auth/v1/teams/:id", "GET", "team:read")
+		restore := domain.NewEndpoint("restore-team", "/auth/v1/teams/:id/restore", "POST", "team:delete")
+		restore.AllowArchived = true
+		endpoints := map[string]domain.Endpoint{update.Key(): update, get.Key(): get, restore.Key(): restore}
+
+		request := domain.AccessRequest{
+			UserID: john.ID.String(),
+			Method: "PUT",
+			Path:   "/auth/v1/teams/" + cmdTeam.TeamID.String(),
+		}
+		decision, err := view.Decide(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(decision.Allowed).To(BeFalse())
+		Ω(decision.Reason).To(Equal(domain.DecisionTeamArchived))
+
+		request.Method = "GET"
+		decision, err = view.Decide(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(decision.Allowed).To(BeTrue())
+
+		request.Method = "POST"
+		request.Path += "/restore"
+		decision, err = view.Decide(ctx, request, endpoints)
+		Ω(err).To(Succeed())
+		Ω(decision.Allowed).To(BeTrue())
+	})
+	It("Restores an archived team during the grace period", func() {
+		archive()
+
+		err := handlers.RestoreTeam(ctx, &command.RestoreTeam{TeamID: cmdTeam.TeamID, User: john})
+		Ω(err).To(Succeed())
+		Ω(scheduler.PurgeTeams).To(BeEmpty())
+
+		team, err := repository.Team.Get(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(team.IsArchived()).To(BeFalse())
+
+		// a deletion task left behind does not delete a restored team
+		err = handlers.PurgeTeam(ctx, &command.PurgeTeam{TeamID: cmdTeam.TeamID})
+		Ω(err).To(Succeed())
+		_, err = repository.Team.Get(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+	})
+	It("Deletes the team once the grace period is over", func() {
+		err := handlers.SendInvitation(ctx, &command.SendInvitation{
+			TeamID:   cmdTeam.TeamID,
+			Invitees: []command.Invitee{{Email: "james@mail.com", Role: domain.Member}},
+			Sender:   john,
+		})
+		Ω(err).To(Succeed())
+
+		invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID})
+		Ω(err).To(Succeed())
+		Ω(invitations).To(HaveLen(1))
+		reminder := fmt.Sprintf("invitation:remind:%s:0", invitations[0].ID)
+		Ω(client.Scheduled).To(HaveKey(reminder))
+
+		archive()
+
+		err = handlers.PurgeTeam(ctx, &command.PurgeTeam{TeamID: cmdTeam.TeamID})
+		Ω(err).To(Succeed())
+		_, err = repository.Team.Get(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+
+		_, err = persistence.Pool.Exec(ctx, `UPDATE teams SET purge_at = archived_at WHERE id = $1`, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+
+		err = handlers.RestoreTeam(ctx, &command.RestoreTeam{TeamID: cmdTeam.TeamID, User: john})
+		Ω(err).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		err = handlers.PurgeTeam(ctx, &command.PurgeTeam{TeamID: cmdTeam.TeamID})
+		Ω(err).To(Succeed())
+
+		_, err = repository.Team.Get(ctx, cmdTeam.TeamID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+		Ω(client.Scheduled).NotTo(HaveKey(reminder))
+
+		var remaining int
+		err = persistence.Pool.QueryRow(ctx, `
+			SELECT (SELECT COUNT(*) FROM memberships WHERE team_id = $1)
+				+ (SELECT COUNT(*) FROM invitations WHERE team_id = $1)
+				+ (SELECT COUNT(*) FROM relation_tuples WHERE object_type = 'team' AND object_id = $1::text)
+		`, cmdTeam.TeamID).Scan(&remaining)
+		Ω(err).To(Succeed())
+		Ω(remaining).To(BeZero())
+	})
+	It("Archives the teams left without owner when the owner is deleted", func() {
+		err := handlers.DeleteUser(ctx, &command.DeleteUser{User: john})
+		Ω(err).To(Succeed())
+
+		team, err := repository.Team.Get(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(team.IsArchived()).To(BeTrue())
+		Ω(scheduler.PurgeTeams).To(HaveKey(team.ID))
+	})
+})
@@ -0,0 +1,144 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/dns"
+	"authorization/infrastructure/persistence"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Team Domain Testing", Ordered, func() {
+	ctx := context.Background()
+	client := worker.CreateMailerClientMock()
+	worker.CreateMailerMock(client)
+
+	var (
+		john     domain.User
+		jane     domain.User
+		cmdTeam  *command.CreateTeam
+		resolver *dns.ResolverMock
+	)
+
+	claim := func(joinMode domain.DomainJoinMode) domain.TeamDomain {
+		cmd := &command.ClaimTeamDomain{TeamID: cmdTeam.TeamID, Domain: "Acme.com", Role: domain.Member, JoinMode: joinMode, User: john}
+		Ω(handlers.ClaimTeamDomain(ctx, cmd)).To(Succeed())
+
+		teamDomain, err := repository.TeamDomain.Get(ctx, cmd.DomainID)
+		Ω(err).To(Succeed())
+		return teamDomain
+	}
+
+	verify := func(teamDomain domain.TeamDomain) error {
+		return handlers.VerifyTeamDomain(ctx, &command.VerifyTeamDomain{TeamID: cmdTeam.TeamID, DomainID: teamDomain.ID, User: john})
+	}
+
+	signUp := func(user domain.User) {
+		Ω(createUser(ctx, user)).To(Succeed())
+
+		tx, err := persistence.Pool.Begin(ctx)
+		Ω(err).To(Succeed())
+		defer tx.Rollback(ctx)
+
+		send, err := handlers.JoinTeamsByEmailDomain(ctx, user, tx)
+		Ω(err).To(Succeed())
+		Ω(tx.Commit(ctx)).To(Succeed())
+		Ω(send()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		resolver = dns.CreateResolverMock()
+
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@acme.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Acme",
+			Description: "Acme Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+	})
+	It("Verifies the domain through its TXT record", func() {
+		teamDomain := claim(domain.DomainJoinAuto)
+		Ω(teamDomain.Domain).To(Equal("acme.com"))
+		Ω(teamDomain.IsVerified()).To(BeFalse())
+
+		Ω(verify(teamDomain)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		resolver.Records[teamDomain.VerificationName()] = []string{"v=spf1 -all", "authz-verification=wrong"}
+		Ω(verify(teamDomain)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		resolver.Records[teamDomain.VerificationName()] = []string{teamDomain.VerificationRecord()}
+		Ω(verify(teamDomain)).To(Succeed())
+
+		teamDomain, err := repository.TeamDomain.Get(ctx, teamDomain.ID)
+		Ω(err).To(Succeed())
+		Ω(teamDomain.IsVerified()).To(BeTrue())
+
+		// the users already signed up join once the domain is verified
+		membership, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(Succeed())
+		Ω(membership.Role.Name).To(Equal(domain.Member))
+	})
+	It("Lets the verified users of the domain join on signup", func() {
+		teamDomain := claim(domain.DomainJoinAuto)
+		resolver.Records[teamDomain.VerificationName()] = []string{teamDomain.VerificationRecord()}
+		Ω(verify(teamDomain)).To(Succeed())
+
+		bob := domain.NewUser("Bob", "Doe", "bobdoe@acme.com", "", "Google", true)
+		signUp(bob)
+		_, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, bob.ID)
+		Ω(err).To(Succeed())
+
+		unverified := domain.NewUser("Eve", "Doe", "evedoe@acme.com", "", "Google", false)
+		signUp(unverified)
+		_, err = repository.Membership.GetByUser(ctx, cmdTeam.TeamID, unverified.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+
+		other := domain.NewUser("Alice", "Doe", "alicedoe@example.com", "", "Google", true)
+		signUp(other)
+		_, err = repository.Membership.GetByUser(ctx, cmdTeam.TeamID, other.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+	})
+	It("Invites the users of the domain when joining is only proposed", func() {
+		teamDomain := claim(domain.DomainJoinPropose)
+		resolver.Records[teamDomain.VerificationName()] = []string{teamDomain.VerificationRecord()}
+		Ω(verify(teamDomain)).To(Succeed())
+
+		_, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, jane.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+
+		invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{Email: jane.Email, TeamID: cmdTeam.TeamID})
+		Ω(err).To(Succeed())
+		Ω(invitations).To(HaveLen(1))
+		Ω(invitations[0].Status).To(Equal(domain.InvitationStatusPending))
+	})
+	It("Ignores the claims before they are verified and after they are removed", func() {
+		teamDomain := claim(domain.DomainJoinAuto)
+
+		bob := domain.NewUser("Bob", "Doe", "bobdoe@acme.com", "", "Google", true)
+		signUp(bob)
+		_, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, bob.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+
+		err = handlers.ClaimTeamDomain(ctx, &command.ClaimTeamDomain{TeamID: cmdTeam.TeamID, Domain: "acme.com", Role: domain.Member, User: john})
+		Ω(err).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		err = handlers.RemoveTeamDomain(ctx, &command.RemoveTeamDomain{TeamID: cmdTeam.TeamID, DomainID: teamDomain.ID, User: john})
+		Ω(err).To(Succeed())
+
+		_, err = repository.TeamDomain.Get(ctx, teamDomain.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+	})
+})
@@ -0,0 +1,193 @@
+package integration
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/domain/dto"
+	"authorization/infrastructure/persistence"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/util"
+	"authorization/view"
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+)
+
+func addMember(ctx context.Context, teamID uuid.UUID, user domain.User, roleName domain.RoleType) {
+	tx, err := persistence.Pool.Begin(ctx)
+	Ω(err).To(Succeed())
+	defer tx.Rollback(ctx)
+
+	role, err := repository.Role.GetByName(ctx, roleName)
+	Ω(err).To(Succeed())
+
+	now := util.GetTimestampUTC()
+	_, err = repository.Membership.Add(ctx, domain.Membership{
+		ID:           uuid.NewV4(),
+		TeamID:       teamID,
+		UserID:       user.ID,
+		RoleID:       role.ID,
+		LastActiveAt: now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, tx)
+	Ω(err).To(Succeed())
+	Ω(tx.Commit(ctx)).To(Succeed())
+}
+
+var _ = Describe("Team Hierarchy Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john          domain.User
+		jane          domain.User
+		bob           domain.User
+		cmdDepartment *command.CreateTeam
+		cmdSquad      *command.CreateTeam
+	)
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		err := createUser(ctx, john)
+		Ω(err).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		err = createUser(ctx, jane)
+		Ω(err).To(Succeed())
+
+		bob = domain.NewUser("Bob", "Doe", "bobdoe@example.com", "", "Google", true)
+		err = createUser(ctx, bob)
+		Ω(err).To(Succeed())
+
+		cmdDepartment = &command.CreateTeam{
+			Name:        "Department",
+			Description: "Department Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdDepartment, john)
+
+		cmdSquad = &command.CreateTeam{
+			Name:        "Squad",
+			Description: "Squad Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdSquad, john)
+
+		addMember(ctx, cmdDepartment.TeamID, jane, domain.Admin)
+		addMember(ctx, cmdDepartment.TeamID, bob, domain.Member)
+
+		err = handlers.SetTeamParent(ctx, &command.SetTeamParent{
+			TeamID:   cmdSquad.TeamID,
+			ParentID: cmdDepartment.TeamID,
+			User:     john,
+		})
+		Ω(err).To(Succeed())
+	})
+	It("Department admin administers its squads", func() {
+		access, err := repository.Role.GetAccess(ctx, cmdSquad.TeamID, jane.ID, "member:invite")
+		Ω(err).To(Succeed())
+		Ω(access.IsAllowed).To(BeTrue())
+		Ω(access.RoleName).To(Equal(domain.Admin))
+		Ω(access.Source).To(Equal(domain.AccessSourceParentTeam))
+
+		allowed, err := view.Check(ctx, "team:"+cmdSquad.TeamID.String(), "admin", "user:"+jane.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+
+		teams, err := view.Teams(ctx, jane, "Squad", 10, nil)
+		Ω(err).To(Succeed())
+		Ω(teams.Data).To(HaveLen(1))
+		squad := teams.Data[0].(dto.TeamRetrievalSchema)
+		Ω(squad.Role).To(Equal(string(domain.Admin)))
+		Ω(squad.RoleSource).To(Equal(domain.AccessSourceParentTeam))
+	})
+	It("Department members are squad members only when the squad inherits members", func() {
+		access, err := repository.Role.GetAccess(ctx, cmdSquad.TeamID, bob.ID, "team:read")
+		Ω(err).To(Succeed())
+		Ω(access.IsAllowed).To(BeFalse())
+
+		err = handlers.SetTeamParent(ctx, &command.SetTeamParent{
+			TeamID:      cmdSquad.TeamID,
+			ParentID:    cmdDepartment.TeamID,
+			Inheritance: domain.InheritMembers,
+			User:        john,
+		})
+		Ω(err).To(Succeed())
+
+		access, err = repository.Role.GetAccess(ctx, cmdSquad.TeamID, bob.ID, "team:read")
+		Ω(err).To(Succeed())
+		Ω(access.IsAllowed).To(BeTrue())
+		Ω(access.RoleName).To(Equal(domain.Member))
+
+		allowed, err := view.Check(ctx, "team:"+cmdSquad.TeamID.String(), "member", "user:"+bob.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeTrue())
+	})
+	It("Rejects cycles", func() {
+		err := handlers.SetTeamParent(ctx, &command.SetTeamParent{
+			TeamID:   cmdDepartment.TeamID,
+			ParentID: cmdSquad.TeamID,
+			User:     john,
+		})
+		Ω(err).To(HaveOccurred())
+
+		err = handlers.SetTeamParent(ctx, &command.SetTeamParent{
+			TeamID:   cmdSquad.TeamID,
+			ParentID: cmdSquad.TeamID,
+			User:     john,
+		})
+		Ω(err).To(HaveOccurred())
+	})
+	It("Counts the sub-teams moving along with the team in the depth", func() {
+		var parentID uuid.UUID
+		for i := 0; i < domain.MaxTeamDepth-1; i++ {
+			cmd := &command.CreateTeam{Name: fmt.Sprintf("Level %d", i), Description: "Level Description", User: john}
+			createTeam(ctx, cmd, john)
+			if parentID != uuid.Nil {
+				Ω(handlers.SetTeamParent(ctx, &command.SetTeamParent{TeamID: cmd.TeamID, ParentID: parentID, User: john})).To(Succeed())
+			}
+			parentID = cmd.TeamID
+		}
+
+		// the department alone would fit at the last level but its squad would not
+		err := handlers.SetTeamParent(ctx, &command.SetTeamParent{
+			TeamID:   cmdDepartment.TeamID,
+			ParentID: parentID,
+			User:     john,
+		})
+		Ω(err).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		err = handlers.RemoveTeamParent(ctx, &command.RemoveTeamParent{
+			TeamID: cmdSquad.TeamID,
+			User:   john,
+		})
+		Ω(err).To(Succeed())
+
+		err = handlers.SetTeamParent(ctx, &command.SetTeamParent{
+			TeamID:   cmdDepartment.TeamID,
+			ParentID: parentID,
+			User:     john,
+		})
+		Ω(err).To(Succeed())
+	})
+	It("Remove parent", func() {
+		err := handlers.RemoveTeamParent(ctx, &command.RemoveTeamParent{
+			TeamID: cmdSquad.TeamID,
+			User:   john,
+		})
+		Ω(err).To(Succeed())
+
+		access, err := repository.Role.GetAccess(ctx, cmdSquad.TeamID, jane.ID, "member:invite")
+		Ω(err).To(Succeed())
+		Ω(access.IsAllowed).To(BeFalse())
+
+		allowed, err := view.Check(ctx, "team:"+cmdSquad.TeamID.String(), "admin", "user:"+jane.ID.String())
+		Ω(err).To(Succeed())
+		Ω(allowed).To(BeFalse())
+	})
+})
@@ -0,0 +1,128 @@
+package integration
+
+import (
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/domain/dto"
+	"authorization/repository"
+	"authorization/view"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gmeasure"
+)
+
+// queryCounter counts the queries run on the connections it traces.
+type queryCounter struct {
+	queries int64
+}
+
+func (c *queryCounter) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	atomic.AddInt64(&c.queries, 1)
+	return ctx
+}
+
+func (c *queryCounter) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+func (c *queryCounter) count(fn func()) int64 {
+	atomic.StoreInt64(&c.queries, 0)
+	fn()
+	return atomic.LoadInt64(&c.queries)
+}
+
+var _ = Describe("Team List Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john    domain.User
+		jane    domain.User
+		counter *queryCounter
+	)
+
+	listTeams := func(limit int) dto.CursorPagination {
+		teams, err := view.Teams(ctx, john, "", limit, nil)
+		Ω(err).To(Succeed())
+		return teams
+	}
+
+	BeforeEach(func() {
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		for i := 1; i <= 12; i++ {
+			cmdTeam := &command.CreateTeam{
+				Name:        fmt.Sprintf("Team %d", i),
+				Description: fmt.Sprintf("Team %d Description", i),
+				User:        john,
+			}
+			createTeam(ctx, cmdTeam, john)
+			addMember(ctx, cmdTeam.TeamID, jane, domain.Member)
+		}
+
+		// the repositories read through a pool counting the queries, they are created again before the next spec
+		counter = &queryCounter{}
+		config := Pool.Config().Copy()
+		config.ConnConfig.Tracer = counter
+		traced, err := pgxpool.NewWithConfig(ctx, config)
+		Ω(err).To(Succeed())
+		DeferCleanup(traced.Close)
+
+		repository.Team = repository.NewTeamRepository(traced)
+		repository.Membership = repository.NewMembershipRepository(traced)
+	})
+	It("Reads a page of teams with a constant number of queries", func() {
+		var small, large dto.CursorPagination
+		smallQueries := counter.count(func() { small = listTeams(2) })
+		largeQueries := counter.count(func() { large = listTeams(10) })
+
+		Ω(small.Data).To(HaveLen(2))
+		Ω(large.Data).To(HaveLen(10))
+		Ω(largeQueries).To(Equal(smallQueries))
+
+		for _, data := range large.Data {
+			team := data.(dto.TeamRetrievalSchema)
+			if team.IsPersonal {
+				continue
+			}
+			Ω(team.NumOfMembers).To(Equal(int64(2)))
+			Ω(team.Memberships).To(HaveLen(2))
+			Ω(team.Memberships[0].Role).To(Equal(string(domain.Owner)))
+		}
+	})
+	It("Matches the wildcards of the name searched literally", func() {
+		teams, err := view.Teams(ctx, jane, "Team 1", 20, nil)
+		Ω(err).To(Succeed())
+		Ω(teams.Data).To(HaveLen(4))
+
+		for _, name := range []string{"%", "Team_1"} {
+			teams, err := view.Teams(ctx, jane, name, 20, nil)
+			Ω(err).To(Succeed())
+			Ω(teams.Data).To(BeEmpty())
+		}
+	})
+	It("Benchmarks the team list", Label("benchmark"), func() {
+		experiment := gmeasure.NewExperiment("Team list")
+		AddReportEntry(experiment.Name, experiment)
+
+		for _, limit := range []int{1, 5, 10} {
+			name := fmt.Sprintf("page of %d", limit)
+			experiment.SampleDuration(name, func(int) {
+				queries := counter.count(func() { listTeams(limit) })
+				experiment.RecordValue("queries, "+name, float64(queries))
+			}, gmeasure.SamplingConfig{N: 10, Duration: 10 * time.Second})
+		}
+
+		one := experiment.GetStats("queries, page of 1")
+		ten := experiment.GetStats("queries, page of 10")
+		Ω(ten.FloatFor(gmeasure.StatMax)).To(Equal(one.FloatFor(gmeasure.StatMax)))
+	})
+})
@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"authorization/config"
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/command"
+	"authorization/infrastructure/worker"
+	"authorization/repository"
+	"authorization/service/handlers"
+	"authorization/view"
+	"context"
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Team Plan Testing", Ordered, func() {
+	ctx := context.Background()
+
+	var (
+		john    domain.User
+		jane    domain.User
+		bob     domain.User
+		cmdTeam *command.CreateTeam
+	)
+
+	changePlan := func(plan string) {
+		admins := config.AppConfig.AppAdminEmails
+		config.AppConfig.AppAdminEmails = []string{jane.Email}
+		DeferCleanup(func() { config.AppConfig.AppAdminEmails = admins })
+
+		Ω(handlers.ChangeTeamPlan(ctx, &command.ChangeTeamPlan{TeamID: cmdTeam.TeamID, Plan: plan, User: jane})).To(Succeed())
+	}
+
+	invite := func(emails ...string) []domain.InvitationRowResult {
+		cmd := &command.SendInvitation{TeamID: cmdTeam.TeamID, Sender: john}
+		for _, email := range emails {
+			cmd.Invitees = append(cmd.Invitees, command.Invitee{Email: email, Role: domain.Member})
+		}
+		Ω(handlers.SendInvitation(ctx, cmd)).To(Succeed())
+		return cmd.Results
+	}
+
+	invitationID := func(email string) ulid.ULID {
+		invitations, err := repository.Invitation.List(ctx, domain.InvitationOptions{TeamID: cmdTeam.TeamID, Email: email})
+		Ω(err).To(Succeed())
+		Ω(invitations).To(HaveLen(1))
+		return invitations[0].ID
+	}
+
+	accept := func(user domain.User) error {
+		return handlers.UpdateInvitationStatus(ctx, &command.UpdateInvitationStatus{InvitationID: invitationID(user.Email), Status: "accepted", User: user})
+	}
+
+	BeforeEach(func() {
+		client := worker.CreateMailerClientMock()
+		worker.CreateMailerMock(client)
+		worker.CreateSchedulerMock()
+
+		john = domain.NewUser("John", "Doe", "johndoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, john)).To(Succeed())
+
+		jane = domain.NewUser("Jane", "Doe", "janedoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, jane)).To(Succeed())
+
+		bob = domain.NewUser("Bob", "Doe", "bobdoe@example.com", "", "Google", true)
+		Ω(createUser(ctx, bob)).To(Succeed())
+
+		cmdTeam = &command.CreateTeam{
+			Name:        "Team A",
+			Description: "Team A Description",
+			User:        john,
+		}
+		createTeam(ctx, cmdTeam, john)
+	})
+	It("Lets only the administrators change the plan of the team", func() {
+		usage, err := view.TeamUsage(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(usage.Plan).To(Equal(config.AppConfig.TeamDefaultPlan))
+		Ω(usage.Members.Used).To(BeEquivalentTo(1))
+		Ω(usage.Members.Limit).To(BeZero())
+
+		cmd := &command.ChangeTeamPlan{TeamID: cmdTeam.TeamID, Plan: "free", User: john}
+		Ω(handlers.ChangeTeamPlan(ctx, cmd)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		changePlan("free")
+		cmd.User = jane
+		cmd.Plan = "platinum"
+		Ω(handlers.ChangeTeamPlan(ctx, cmd)).To(BeAssignableToTypeOf(exception.BadRequestException{}))
+
+		usage, err = view.TeamUsage(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(usage.Plan).To(Equal("free"))
+		Ω(usage.Members.Limit).To(Equal(5))
+		Ω(usage.PendingInvitations.Limit).To(Equal(10))
+	})
+	It("Skips the invitations beyond the pending invitations of the plan", func() {
+		changePlan("free")
+
+		emails := make([]string, 0, 12)
+		for i := 0; i < 12; i++ {
+			emails = append(emails, fmt.Sprintf("invitee%d@example.com", i))
+		}
+
+		results := invite(emails...)
+		statuses := make(map[domain.InvitationRowStatus]int)
+		for _, result := range results {
+			statuses[result.Status]++
+		}
+		Ω(statuses).To(Equal(map[domain.InvitationRowStatus]int{
+			domain.InvitationRowCreated:      10,
+			domain.InvitationRowSkippedQuota: 2,
+		}))
+
+		usage, err := view.TeamUsage(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(usage.PendingInvitations.Used).To(BeEquivalentTo(10))
+
+		// deleting an invitation frees its place
+		Ω(handlers.DeleteInvitation(ctx, &command.DeleteInvitation{InvitationID: invitationID(emails[0]), User: john})).To(Succeed())
+		results = invite(emails[10])
+		Ω(results[0].Status).To(Equal(domain.InvitationRowCreated))
+	})
+	It("Keeps the members of the team within the seats of the plan", func() {
+		changePlan("free")
+		for i := 0; i < 3; i++ {
+			member := domain.NewUser("Member", fmt.Sprint(i), fmt.Sprintf("member%d@example.com", i), "", "Google", true)
+			Ω(createUser(ctx, member)).To(Succeed())
+			addMember(ctx, cmdTeam.TeamID, member, domain.Member)
+		}
+
+		invite(jane.Email, bob.Email)
+		Ω(accept(jane)).To(Succeed())
+		Ω(accept(bob)).To(BeAssignableToTypeOf(exception.ForbiddenException{}))
+
+		_, err := repository.Membership.GetByUser(ctx, cmdTeam.TeamID, bob.ID)
+		Ω(err).To(BeAssignableToTypeOf(exception.NotFoundException{}))
+
+		usage, err := view.TeamUsage(ctx, cmdTeam.TeamID)
+		Ω(err).To(Succeed())
+		Ω(usage.Members.Used).To(BeEquivalentTo(5))
+		Ω(usage.PendingInvitations.Used).To(BeEquivalentTo(1))
+
+		// the invitation can be accepted once the team moves to a larger plan
+		changePlan("pro")
+		Ω(accept(bob)).To(Succeed())
+	})
+})
@@ -92,12 +92,11 @@ var _ = Describe("Team Testing", Ordered, func() {
 	})
 	Context("Get list of teams", func() {
 		It("List", func() {
-			respPaginated, err := view.Teams(ctx, john, "", 1, 10)
+			respPaginated, err := view.Teams(ctx, john, "", 10, nil)
 			Ω(err).To(Succeed())
 			Ω(respPaginated.Data).To(HaveLen(3))
-			Ω(respPaginated.Page).To(Equal(1))
-			Ω(respPaginated.PageSize).To(Equal(10))
-			Ω(respPaginated.TotalPage).To(Equal(1))
+			Ω(respPaginated.Limit).To(Equal(10))
+			Ω(respPaginated.Next).To(BeEmpty())
 			Ω(int(respPaginated.TotalData)).To(Equal(3))
 			Ω(respPaginated.HasNext).To(BeFalse())
 			Ω(respPaginated.HasPrev).To(BeFalse())
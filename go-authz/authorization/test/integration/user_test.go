@@ -81,12 +81,11 @@ var _ = Describe("User Testing", func() {
 		})
 	})
 	It("List", func() {
-		respPaginated, err := view.Users(ctx, 1, 10)
+		respPaginated, err := view.Users(ctx, domain.UserOptions{Limit: 10})
 		Ω(err).To(Succeed())
 		Ω(respPaginated.Data).To(HaveLen(1))
-		Ω(respPaginated.Page).To(Equal(1))
-		Ω(respPaginated.PageSize).To(Equal(10))
-		Ω(respPaginated.TotalPage).To(Equal(1))
+		Ω(respPaginated.Limit).To(Equal(10))
+		Ω(respPaginated.Next).To(BeEmpty())
 		Ω(int(respPaginated.TotalData)).To(Equal(1))
 		Ω(respPaginated.HasNext).To(BeFalse())
 		Ω(respPaginated.HasPrev).To(BeFalse())
@@ -98,7 +97,7 @@ var _ = Describe("User Testing", func() {
 			err := createUser(ctx, jane)
 			Ω(err).To(Succeed())
 
-			respPaginated, err := view.Users(ctx, 1, 10)
+			respPaginated, err := view.Users(ctx, domain.UserOptions{Limit: 10})
 			Ω(err).To(Succeed())
 			Ω(respPaginated.Data).To(HaveLen(2))
 		})
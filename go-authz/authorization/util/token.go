@@ -101,3 +101,61 @@ func ValidateToken(token string, publicKey string) (*TokenDetails, error) {
 
 	return td, nil
 }
+
+// invitationTokenPurpose tells an invitation token apart from the access tokens signed with the same key.
+const invitationTokenPurpose = "invitation"
+
+// CreateInvitationToken signs the link of an invitation, the token expires with the invitation.
+func CreateInvitationToken(invitationID ulid.ULID, expiresAt time.Time, privateKey string) (string, error) {
+	decodedPrivateKey, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("could not decode token private key: %w", err)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(decodedPrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("create: parse token private key: %w", err)
+	}
+
+	now := GetTimestampUTC()
+	claims := jwt.MapClaims{
+		"sub":     invitationID.String(),
+		"purpose": invitationTokenPurpose,
+		"exp":     expiresAt.Unix(),
+		"iat":     now.Unix(),
+	}
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	jwtToken.Header["kid"] = config.AppConfig.AccessTokenKID
+
+	token, err := jwtToken.SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("create: sign token: %w", err)
+	}
+	return token, nil
+}
+
+// ValidateInvitationToken returns the ID of the invitation the token was signed for.
+func ValidateInvitationToken(token string, publicKey string) (ulid.ULID, error) {
+	decodedPublicKey, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return ulid.ULID{}, fmt.Errorf("could not decode: %w", err)
+	}
+	key, err := jwt.ParseRSAPublicKeyFromPEM(decodedPublicKey)
+	if err != nil {
+		return ulid.ULID{}, fmt.Errorf("validate: parse key: %w", err)
+	}
+
+	parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		return key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}))
+	if err != nil {
+		return ulid.ULID{}, fmt.Errorf("validate: %w", err)
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid || claims["purpose"] != invitationTokenPurpose {
+		return ulid.ULID{}, fmt.Errorf("validate: invalid token")
+	}
+
+	return ulid.Parse(fmt.Sprint(claims["sub"]))
+}
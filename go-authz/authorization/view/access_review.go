@@ -0,0 +1,100 @@
+package view
+
+import (
+	"authorization/config"
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/dto"
+	"authorization/repository"
+	"context"
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AccessReviews lists the access reviews without their items, every status by default.
+func AccessReviews(ctx context.Context, opts domain.AccessReviewOptions) (dto.CursorPagination, error) {
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	opts.Limit = page.Limit + 1
+
+	reviews, err := repository.AccessReview.List(ctx, opts)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	totalReviews, err := repository.AccessReview.Count(ctx, opts)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	cursor := func(r domain.AccessReview) domain.Cursor { return r.Cursor() }
+	parse := func(r domain.AccessReview) (interface{}, error) { return r.Parse(), nil }
+	return cursorPage(reviews, page, totalReviews, cursor, parse)
+}
+
+// AccessReview returns the access review along with the items the user can see.
+func AccessReview(ctx context.Context, reviewID ulid.ULID, user domain.User) (*dto.AccessReviewSchema, error) {
+	review, err := visibleAccessReview(ctx, reviewID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	result := review.Parse()
+	return &result, nil
+}
+
+// AccessReviewCSV renders the items of the access review the user can see as CSV.
+func AccessReviewCSV(ctx context.Context, reviewID ulid.ULID, user domain.User) ([]byte, error) {
+	review, err := visibleAccessReview(ctx, reviewID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return review.CSV()
+}
+
+// visibleAccessReview returns the review with the items the user can see, the administrators and the creator of the
+// review see every item while the reviewers only see the items of the teams they can review.
+func visibleAccessReview(ctx context.Context, reviewID ulid.ULID, user domain.User) (domain.AccessReview, error) {
+	review, err := repository.AccessReview.Get(ctx, reviewID)
+	if err != nil {
+		return domain.AccessReview{}, err
+	}
+
+	if config.AppConfig.IsAdmin(user.Email) || (review.CreatorID.Valid && review.CreatorID.UUID == user.ID) {
+		return review, nil
+	}
+
+	allowed := make(map[uuid.UUID]bool)
+	items := make([]domain.AccessReviewItem, 0, len(review.Items))
+	for _, item := range review.Items {
+		isAllowed, ok := allowed[item.TeamID]
+		if !ok {
+			access, err := repository.Role.GetAccess(ctx, item.TeamID, user.ID, "member:access-review")
+			if err != nil {
+				return domain.AccessReview{}, err
+			}
+			isAllowed = access.IsAllowed
+			allowed[item.TeamID] = isAllowed
+		}
+
+		if isAllowed {
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		return domain.AccessReview{}, exception.NewNotFoundException(fmt.Sprintf("access review with ID %s is not found", reviewID))
+	}
+
+	review.Items = items
+	review.TotalItems = len(items)
+	review.PendingItems = 0
+	for _, item := range items {
+		if item.Decision == domain.AccessReviewPending {
+			review.PendingItems++
+		}
+	}
+	return review, nil
+}
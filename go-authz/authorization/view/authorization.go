@@ -2,30 +2,196 @@ package view
 
 import (
 	"authorization/domain"
+	"authorization/domain/dto"
 	"authorization/repository"
+	"authorization/util"
 	"context"
 	"regexp"
 	"strings"
 
+	"github.com/rs/zerolog/log"
 	uuid "github.com/satori/go.uuid"
+	"gopkg.in/yaml.v2"
 )
 
 var uuidPattern = regexp.MustCompile(`\b[0-9a-f]{8}\b-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-\b[0-9a-f]{12}\b`)
 
-func Authorization(ctx context.Context, userID string, method string, path string, endpoints map[string]domain.Endpoint) (bool, error) {
-	rePath := uuidPattern.ReplaceAllString(path, ":id")
-
-	if endpoint, ok := endpoints[rePath+"_"+method]; ok {
-		if strings.Contains(path, "/v1/team") {
-			teamID := strings.Split(path, "/")[4]
-			access, err := repository.Role.GetAccess(ctx, uuid.FromStringOrNil(teamID), uuid.FromStringOrNil(userID), endpoint)
-			if err != nil {
-				return false, err
-			}
-			return access.IsAllowed, nil
+// ulidPattern matches the ULID ids of invitations and ownership transfers
+var ulidPattern = regexp.MustCompile(`\b[0-9A-HJKMNP-TV-Z]{26}\b`)
+
+var loadedEndpoints = make(map[string]domain.Endpoint)
+
+// LoadEndpoints reads the guarded endpoints from endpoints.yml, it must be called on startup.
+func LoadEndpoints() map[string]domain.Endpoint {
+	endpointDatas := util.ReadYAML("endpoints.yml")
+	var endpointYAML domain.EndpointYAML
+	err := yaml.Unmarshal(endpointDatas, &endpointYAML)
+	if err != nil {
+		log.Fatal().Caller().Err(err).Msg("Failed to unmarshal endpoint data")
+	}
+
+	for _, endpoint := range endpointYAML.Endpoints {
+		endpointData := domain.NewEndpoint(endpoint.Name, endpoint.Path, endpoint.Method, endpoint.Permission)
+		if endpoint.Relation != "" {
+			endpointData = domain.NewRelationEndpoint(endpoint.Name, endpoint.Path, endpoint.Method, endpoint.Object, endpoint.Relation)
+		}
+		endpointData.AllowArchived = endpoint.AllowArchived
+		endpointData.AllowGuests = endpoint.AllowGuests
+		loadedEndpoints[endpointData.Key()] = endpointData
+	}
+
+	return loadedEndpoints
+}
+
+func Authorization(ctx context.Context, request domain.AccessRequest, endpoints map[string]domain.Endpoint) (bool, error) {
+	decision, err := Decide(ctx, request, endpoints)
+	if err != nil {
+		return false, err
+	}
+	return decision.Allowed, nil
+}
+
+// Decide authorizes the request and records every step that led to the decision.
+func Decide(ctx context.Context, request domain.AccessRequest, endpoints map[string]domain.Endpoint) (*dto.DecisionSchema, error) {
+	rePath := uuidPattern.ReplaceAllString(request.Path, ":id")
+	rePath = ulidPattern.ReplaceAllString(rePath, ":id")
+
+	decision := &dto.DecisionSchema{
+		UserID:      request.UserID,
+		Method:      request.Method,
+		Path:        request.Path,
+		MatchedPath: rePath,
+	}
+
+	endpoint, ok := endpoints[domain.EndpointKey(rePath, request.Method)]
+	if !ok {
+		// endpoints that are not listed in endpoints.yml are not guarded
+		decision.Allowed = true
+		decision.Reason = domain.DecisionEndpointNotMatched
+		return decision, nil
+	}
+
+	endpointSchema := endpoint.Parse()
+	decision.Endpoint = &endpointSchema
+
+	if endpoint.Relation != "" {
+		object := endpoint.Object + ":" + uuidPattern.FindString(request.Path)
+		subject := domain.UserNamespace + ":" + request.UserID
+		allowed, err := Check(ctx, object, endpoint.Relation, subject)
+		if err != nil {
+			return nil, err
+		}
+
+		decision.Relation = &dto.RelationCheckSchema{
+			Object:   object,
+			Relation: endpoint.Relation,
+			Subject:  subject,
+			Allowed:  allowed,
+		}
+		decision.Allowed = allowed
+		decision.Reason = domain.DecisionRelationMissing
+		if allowed {
+			decision.Reason = domain.DecisionRelationGranted
+		}
+		return decision, nil
+	}
+
+	scope, scopeID := resourceScope(request.Path)
+	userID := uuid.FromStringOrNil(request.UserID)
+
+	var access domain.Access
+	var err error
+	switch scope {
+	case domain.TeamNamespace:
+		decision.TeamID = scopeID.String()
+		access, err = repository.Role.GetAccess(ctx, scopeID, userID, endpoint.Permission)
+	case domain.OrganizationNamespace:
+		decision.OrganizationID = scopeID.String()
+		access, err = repository.Role.GetOrganizationAccess(ctx, scopeID, userID, endpoint.Permission)
+	default:
+		decision.Allowed = true
+		decision.Reason = domain.DecisionEndpointNotScoped
+		return decision, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decision.Permission = access.Permission
+	decision.Role = string(access.RoleName)
+	decision.RoleSource = access.Source
+	decision.IsMember = access.RoleName != ""
+
+	if !decision.IsMember {
+		decision.Reason = domain.DecisionNoMembership
+		return decision, nil
+	}
+
+	if !access.IsAllowed {
+		decision.Reason = domain.DecisionPermissionMissing
+		return decision, nil
+	}
+
+	// a guest only gets the endpoints explicitly opened to guests, even those its role has the permission for
+	if scope == domain.TeamNamespace && domain.CurrentRoleHierarchy().IsGuest(access.RoleName) && !endpoint.AllowGuests {
+		decision.Reason = domain.DecisionGuestRestricted
+		return decision, nil
+	}
+
+	if scope == domain.TeamNamespace && !endpoint.IsReadOnly() {
+		team, err := repository.Team.Get(ctx, scopeID)
+		if err != nil {
+			return nil, err
+		}
+
+		if team.IsArchived() {
+			decision.Reason = domain.DecisionTeamArchived
+			return decision, nil
 		}
-		return true, nil
 	}
 
-	return true, nil
+	if scope == domain.OrganizationNamespace {
+		decision.Allowed = true
+		decision.Reason = domain.DecisionPermissionGranted
+		return decision, nil
+	}
+
+	policies, allowed, err := evaluatePolicies(ctx, scopeID, userID, access, request)
+	if err != nil {
+		return nil, err
+	}
+
+	decision.Policies = policies
+	decision.Allowed = allowed
+	decision.Reason = domain.DecisionPolicyDenied
+	if allowed {
+		decision.Reason = domain.DecisionPermissionGranted
+	}
+
+	return decision, nil
+}
+
+// resourceScope extracts the team or organization a gateway path such as /auth/v1/teams/:id/... refers to.
+func resourceScope(path string) (string, uuid.UUID) {
+	paths := strings.Split(path, "/")
+	if len(paths) < 5 {
+		return "", uuid.Nil
+	}
+
+	switch {
+	case strings.HasPrefix(paths[3], "team"):
+		return domain.TeamNamespace, uuid.FromStringOrNil(paths[4])
+	case paths[3] == "organizations":
+		return domain.OrganizationNamespace, uuid.FromStringOrNil(paths[4])
+	}
+
+	return "", uuid.Nil
+}
+
+// Explain replays the authorization of request against the endpoints loaded on startup.
+func Explain(ctx context.Context, request domain.AccessRequest) (*dto.DecisionSchema, error) {
+	if request.Time.IsZero() {
+		request.Time = util.GetTimestampUTC()
+	}
+	return Decide(ctx, request, loadedEndpoints)
 }
@@ -0,0 +1,63 @@
+package view
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/dto"
+	"authorization/repository"
+	"context"
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// TeamElevations lists the elevation requests of the team, the pending and active ones by default.
+func TeamElevations(ctx context.Context, opts domain.ElevationOptions) (dto.CursorPagination, error) {
+	if len(opts.Statuses) == 0 {
+		opts.Statuses = []domain.ElevationStatus{domain.ElevationPending, domain.ElevationActive}
+	}
+
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	opts.Limit = page.Limit + 1
+
+	requests, err := repository.Elevation.List(ctx, opts)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	totalRequests, err := repository.Elevation.Count(ctx, opts)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	cursor := func(r domain.ElevationRequest) domain.Cursor { return r.Cursor() }
+	parse := func(r domain.ElevationRequest) (interface{}, error) { return r.Parse(), nil }
+	return cursorPage(requests, page, totalRequests, cursor, parse)
+}
+
+// Elevation returns the elevation request along with its events,
+// only the member who made it and the members who can review the requests of the team can see it.
+func Elevation(ctx context.Context, teamID uuid.UUID, requestID ulid.ULID, user domain.User) (*dto.ElevationRequestSchema, error) {
+	request, err := repository.Elevation.Get(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	notFound := exception.NewNotFoundException(fmt.Sprintf("elevation request with ID %s is not found in team with ID %s", requestID, teamID))
+	if request.TeamID != teamID {
+		return nil, notFound
+	}
+
+	if request.UserID != user.ID {
+		access, err := repository.Role.GetAccess(ctx, teamID, user.ID, "member:elevation-review")
+		if err != nil {
+			return nil, err
+		} else if !access.IsAllowed {
+			return nil, notFound
+		}
+	}
+
+	result := request.Parse()
+	return &result, nil
+}
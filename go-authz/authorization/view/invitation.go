@@ -5,11 +5,13 @@ import (
 	"authorization/domain"
 	"authorization/domain/dto"
 	"authorization/repository"
+	"authorization/util"
 	"context"
 	"errors"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
 )
 
 func Invitation(ctx context.Context, id ulid.ULID) (*dto.InvitationRetreivalSchema, error) {
@@ -70,3 +72,48 @@ func Invitation(ctx context.Context, id ulid.ULID) (*dto.InvitationRetreivalSche
 		},
 	}, nil
 }
+
+// TeamInvitations lists the invitations of a team page by page, the newest first unless asked otherwise.
+func TeamInvitations(ctx context.Context, opts domain.InvitationOptions) (dto.CursorPagination, error) {
+	return invitations(ctx, opts)
+}
+
+// UserInvitations lists the invitations waiting for an answer of the user, it is the inbox of the invitee.
+func UserInvitations(ctx context.Context, user domain.User, limit int, cursor *domain.Cursor) (dto.CursorPagination, error) {
+	return invitations(ctx, domain.InvitationOptions{
+		Email:        user.Email,
+		Statuses:     domain.InvitationStatusesOpen,
+		ExpiresAfter: util.GetTimestampUTC(),
+		Limit:        limit,
+		Descending:   true,
+		Cursor:       cursor,
+	})
+}
+
+func invitations(ctx context.Context, opts domain.InvitationOptions) (dto.CursorPagination, error) {
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	opts.Limit = page.Limit + 1
+
+	invitations, err := repository.Invitation.List(ctx, opts)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	totalInvitations, err := repository.Invitation.Count(ctx, opts)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	cursor := func(i domain.Invitation) domain.Cursor { return i.Cursor() }
+	parse := func(i domain.Invitation) (interface{}, error) { return i.Parse(), nil }
+	return cursorPage(invitations, page, totalInvitations, cursor, parse)
+}
+
+// InvitationSettings returns the invitation settings of the team, the default ones when the team has none of its own.
+func InvitationSettings(ctx context.Context, teamID uuid.UUID) (dto.InvitationSettingsRetrievalSchema, error) {
+	settings, err := repository.InvitationSettings.Get(ctx, teamID)
+	if err != nil {
+		return dto.InvitationSettingsRetrievalSchema{}, err
+	}
+	return settings.Parse(), nil
+}
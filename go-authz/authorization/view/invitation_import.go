@@ -0,0 +1,26 @@
+package view
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain/dto"
+	"authorization/repository"
+	"context"
+
+	"github.com/oklog/ulid/v2"
+	uuid "github.com/satori/go.uuid"
+)
+
+// InvitationImport returns the report of a bulk invitation of the team, its results are empty while it is pending.
+func InvitationImport(ctx context.Context, teamID uuid.UUID, id ulid.ULID) (*dto.InvitationImportRetrievalSchema, error) {
+	invitationImport, err := repository.InvitationImport.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if invitationImport.TeamID != teamID {
+		return nil, exception.NewNotFoundException("invitation import not found")
+	}
+
+	result := invitationImport.Parse()
+	return &result, nil
+}
@@ -0,0 +1,24 @@
+package view
+
+import (
+	"authorization/domain/dto"
+	"authorization/repository"
+	"context"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// JoinLinks returns the join links of the team, the newest first.
+func JoinLinks(ctx context.Context, teamID uuid.UUID) ([]dto.JoinLinkRetrievalSchema, error) {
+	links, err := repository.JoinLink.ListByTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.JoinLinkRetrievalSchema, 0, len(links))
+	for _, link := range links {
+		result = append(result, link.Parse())
+	}
+
+	return result, nil
+}
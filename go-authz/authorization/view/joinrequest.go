@@ -0,0 +1,32 @@
+package view
+
+import (
+	"authorization/domain"
+	"authorization/domain/dto"
+	"authorization/repository"
+	"context"
+)
+
+// TeamJoinRequests lists the requests to join the team, the pending ones by default.
+func TeamJoinRequests(ctx context.Context, opts domain.JoinRequestOptions) (dto.CursorPagination, error) {
+	if len(opts.Statuses) == 0 {
+		opts.Statuses = []domain.JoinRequestStatus{domain.JoinRequestPending}
+	}
+
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	opts.Limit = page.Limit + 1
+
+	requests, err := repository.JoinRequest.List(ctx, opts)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	totalRequests, err := repository.JoinRequest.Count(ctx, opts)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	cursor := func(r domain.JoinRequest) domain.Cursor { return r.Cursor() }
+	parse := func(r domain.JoinRequest) (interface{}, error) { return r.Parse(), nil }
+	return cursorPage(requests, page, totalRequests, cursor, parse)
+}
@@ -0,0 +1,62 @@
+package view
+
+import (
+	"authorization/domain"
+	"authorization/domain/dto"
+	"authorization/repository"
+	"context"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func Organization(ctx context.Context, id uuid.UUID) (*dto.OrganizationRetrievalSchema, error) {
+	organization, err := repository.Organization.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := repository.Organization.ListMemberships(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := repository.Organization.ListTeams(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := organization.Parse()
+	for _, membership := range memberships {
+		result.Memberships = append(result.Memberships, membership.Parse())
+	}
+	for _, team := range teams {
+		result.Teams = append(result.Teams, dto.TeamRetrievalSchema{
+			ID:          team.ID,
+			Name:        team.Name,
+			Description: team.Description,
+			AvatarURL:   team.AvatarURL,
+			IsPersonal:  team.IsPersonal,
+			CreatedAt:   team.CreatedAt,
+			UpdatedAt:   team.UpdatedAt,
+		})
+	}
+
+	return &result, nil
+}
+
+func Organizations(ctx context.Context, user domain.User) ([]dto.OrganizationRetrievalSchema, error) {
+	organizations, err := repository.Organization.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.OrganizationRetrievalSchema, 0, len(organizations))
+	for _, organization := range organizations {
+		data := organization.Parse()
+		data.Creator = nil
+		data.Role = string(organization.Memberships[0].Role.Name)
+		result = append(result, data)
+	}
+
+	return result, nil
+}
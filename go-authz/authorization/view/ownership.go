@@ -0,0 +1,26 @@
+package view
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/dto"
+	"authorization/repository"
+	"context"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// OwnershipTransfer returns the transfer to the member who proposed it or to the member it is addressed to.
+func OwnershipTransfer(ctx context.Context, id ulid.ULID, user domain.User) (*dto.OwnershipTransferRetrievalSchema, error) {
+	transfer, err := repository.OwnershipTransfer.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.FromUserID != user.ID && transfer.ToUserID != user.ID {
+		return nil, exception.NewNotFoundException("ownership transfer not found")
+	}
+
+	result := transfer.Parse()
+	return &result, nil
+}
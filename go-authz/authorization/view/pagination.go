@@ -0,0 +1,43 @@
+package view
+
+import (
+	"authorization/domain"
+	"authorization/domain/dto"
+)
+
+// cursorPage builds the envelope of a page read with one row more than the limit, the extra row tells that
+// another page follows. The next cursor points at the last row of the page and the prev cursor at the first one.
+func cursorPage[T any](rows []T, page domain.Page, total int64, cursor func(T) domain.Cursor, parse func(T) (interface{}, error)) (dto.CursorPagination, error) {
+	more := len(rows) > page.Limit
+	if more && page.IsBackward() {
+		rows = rows[len(rows)-page.Limit:]
+	} else if more {
+		rows = rows[:page.Limit]
+	}
+
+	hasNext, hasPrev := more, page.Cursor != nil
+	if page.IsBackward() {
+		hasNext, hasPrev = true, more
+	}
+
+	var next, prev string
+	if len(rows) > 0 && hasNext {
+		next = cursor(rows[len(rows)-1]).Encode()
+	}
+	if len(rows) > 0 && hasPrev {
+		first := cursor(rows[0])
+		first.Backward = true
+		prev = first.Encode()
+	}
+
+	data := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		item, err := parse(row)
+		if err != nil {
+			return dto.CursorPagination{}, err
+		}
+		data = append(data, item)
+	}
+
+	return dto.CursorPaginate(page.Limit, total, next, prev, data), nil
+}
@@ -0,0 +1,25 @@
+package view
+
+import (
+	"authorization/domain"
+	"authorization/domain/dto"
+	"authorization/repository"
+	"context"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// TeamUsage returns the plan of the team along with what the team holds against its quotas.
+func TeamUsage(ctx context.Context, teamID uuid.UUID) (dto.TeamUsageSchema, error) {
+	team, err := repository.Team.Get(ctx, teamID)
+	if err != nil {
+		return dto.TeamUsageSchema{}, err
+	}
+
+	usage, err := repository.Team.Usage(ctx, team.ID)
+	if err != nil {
+		return dto.TeamUsageSchema{}, err
+	}
+
+	return usage.Parse(domain.CurrentPlans().Of(team)), nil
+}
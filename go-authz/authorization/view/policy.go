@@ -0,0 +1,85 @@
+package view
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/dto"
+	"authorization/repository"
+	"context"
+
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+func Policies(ctx context.Context, teamID uuid.UUID) ([]dto.PolicyRetrievalSchema, error) {
+	policies, err := repository.Policy.List(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.PolicyRetrievalSchema, 0, len(policies))
+	for _, policy := range policies {
+		result = append(result, policy.Parse())
+	}
+
+	return result, nil
+}
+
+func Policy(ctx context.Context, teamID, policyID uuid.UUID) (*dto.PolicyRetrievalSchema, error) {
+	policy, err := repository.Policy.Get(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.TeamID != teamID {
+		return nil, exception.NewNotFoundException("policy not found")
+	}
+
+	result := policy.Parse()
+	return &result, nil
+}
+
+// evaluatePolicies checks the team policies that apply to the permission granted by access.
+// Every policy is evaluated so the decision trace is complete, a policy that fails to evaluate denies the request.
+func evaluatePolicies(ctx context.Context, teamID, userID uuid.UUID, access domain.Access, request domain.AccessRequest) ([]dto.PolicyDecisionSchema, bool, error) {
+	policies, err := repository.Policy.ListActiveByPermission(ctx, teamID, access.Permission)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(policies) == 0 {
+		return nil, true, nil
+	}
+
+	user, err := repository.User.Get(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	team, err := repository.Team.Get(ctx, teamID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	attributes := domain.NewPolicyAttributes(request, user, team, access)
+	decisions := make([]dto.PolicyDecisionSchema, 0, len(policies))
+	allowed := true
+	for _, policy := range policies {
+		decision := dto.PolicyDecisionSchema{
+			ID:         policy.ID,
+			Name:       policy.Name,
+			Expression: policy.Expression,
+		}
+
+		decision.Allowed, err = policy.Evaluate(attributes)
+		if err != nil {
+			log.Warn().Caller().Err(err).Str("policy_id", policy.ID.String()).Msg("Failed to evaluate policy")
+			decision.Error = err.Error()
+		}
+
+		allowed = allowed && decision.Allowed
+		decisions = append(decisions, decision)
+	}
+
+	return decisions, allowed, nil
+}
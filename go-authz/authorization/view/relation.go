@@ -0,0 +1,311 @@
+package view
+
+import (
+	"authorization/controller/exception"
+	"authorization/domain"
+	"authorization/domain/dto"
+	"authorization/repository"
+	"authorization/util"
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v2"
+)
+
+// maxRelationDepth bounds the graph traversal so a cyclic configuration cannot loop forever
+const maxRelationDepth = 16
+
+var namespaces = make(map[string]domain.Namespace)
+
+// LoadNamespaces reads the relation configuration from relations.yml, it must be called on startup.
+func LoadNamespaces() {
+	relationDatas := util.ReadYAML("relations.yml")
+	var namespaceYAML domain.NamespaceYAML
+	err := yaml.Unmarshal(relationDatas, &namespaceYAML)
+	if err != nil {
+		log.Fatal().Caller().Err(err).Msg("Failed to unmarshal relation data")
+	}
+
+	for _, namespace := range namespaceYAML.Namespaces {
+		namespaces[namespace.Name] = namespace
+	}
+}
+
+func Namespace(name string) (domain.Namespace, error) {
+	namespace, ok := namespaces[name]
+	if !ok {
+		return domain.Namespace{}, exception.NewBadRequestException(fmt.Sprintf("namespace %s is not defined", name))
+	}
+	return namespace, nil
+}
+
+// Check reports whether subject has relation on object, following usersets and rewrite rules.
+func Check(ctx context.Context, object, relation, subject string) (bool, error) {
+	objectType, objectID, err := domain.ParseObject(object)
+	if err != nil {
+		return false, err
+	}
+	subjectType, subjectID, subjectRelation, err := domain.ParseSubject(subject)
+	if err != nil {
+		return false, err
+	}
+	target := domain.NewRelationTuple(objectType, objectID, relation, subjectType, subjectID, subjectRelation)
+	return check(ctx, target, 0)
+}
+
+func check(ctx context.Context, target domain.RelationTuple, depth int) (bool, error) {
+	if depth > maxRelationDepth {
+		log.Warn().Caller().Str("tuple", target.String()).Msg("relation check reached max depth")
+		return false, nil
+	}
+
+	namespace, err := Namespace(target.ObjectType)
+	if err != nil {
+		return false, err
+	}
+	namespaceRelation, ok := namespace.Relation(target.Relation)
+	if !ok {
+		return false, exception.NewBadRequestException(fmt.Sprintf("relation %s is not defined on %s", target.Relation, target.ObjectType))
+	}
+
+	// a userset subject trivially has its own relation
+	if target.SubjectType == target.ObjectType && target.SubjectID == target.ObjectID && target.SubjectRelation == target.Relation {
+		return true, nil
+	}
+
+	tuples, err := repository.Relation.List(ctx, domain.RelationTupleOptions{
+		ObjectType: target.ObjectType,
+		ObjectID:   target.ObjectID,
+		Relation:   target.Relation,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, tuple := range tuples {
+		if tuple.SubjectType == target.SubjectType && tuple.SubjectID == target.SubjectID && tuple.SubjectRelation == target.SubjectRelation {
+			return true, nil
+		}
+		if tuple.IsUserset() {
+			next := domain.NewRelationTuple(tuple.SubjectType, tuple.SubjectID, tuple.SubjectRelation, target.SubjectType, target.SubjectID, target.SubjectRelation)
+			allowed, err := check(ctx, next, depth+1)
+			if err != nil || allowed {
+				return allowed, err
+			}
+		}
+	}
+
+	for _, rewrite := range namespaceRelation.Union {
+		if rewrite.TupleToUserset == "" {
+			next := domain.NewRelationTuple(target.ObjectType, target.ObjectID, rewrite.Computed, target.SubjectType, target.SubjectID, target.SubjectRelation)
+			allowed, err := check(ctx, next, depth+1)
+			if err != nil || allowed {
+				return allowed, err
+			}
+			continue
+		}
+
+		parents, err := repository.Relation.List(ctx, domain.RelationTupleOptions{
+			ObjectType: target.ObjectType,
+			ObjectID:   target.ObjectID,
+			Relation:   rewrite.TupleToUserset,
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, parent := range parents {
+			next := domain.NewRelationTuple(parent.SubjectType, parent.SubjectID, rewrite.Computed, target.SubjectType, target.SubjectID, target.SubjectRelation)
+			allowed, err := check(ctx, next, depth+1)
+			if err != nil || allowed {
+				return allowed, err
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// Expand returns the tree of subjects that have relation on object.
+func Expand(ctx context.Context, object, relation string) (*dto.RelationTreeSchema, error) {
+	objectType, objectID, err := domain.ParseObject(object)
+	if err != nil {
+		return nil, err
+	}
+	return expand(ctx, objectType, objectID, relation, 0)
+}
+
+func expand(ctx context.Context, objectType, objectID, relation string, depth int) (*dto.RelationTreeSchema, error) {
+	tree := &dto.RelationTreeSchema{
+		Object:   objectType + ":" + objectID,
+		Relation: relation,
+		Subjects: []string{},
+	}
+
+	if depth > maxRelationDepth {
+		return tree, nil
+	}
+
+	namespace, err := Namespace(objectType)
+	if err != nil {
+		return nil, err
+	}
+	namespaceRelation, ok := namespace.Relation(relation)
+	if !ok {
+		return nil, exception.NewBadRequestException(fmt.Sprintf("relation %s is not defined on %s", relation, objectType))
+	}
+
+	tuples, err := repository.Relation.List(ctx, domain.RelationTupleOptions{
+		ObjectType: objectType,
+		ObjectID:   objectID,
+		Relation:   relation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tuple := range tuples {
+		tree.Subjects = append(tree.Subjects, tuple.Subject())
+		if tuple.IsUserset() {
+			child, err := expand(ctx, tuple.SubjectType, tuple.SubjectID, tuple.SubjectRelation, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			tree.Children = append(tree.Children, *child)
+		}
+	}
+
+	for _, rewrite := range namespaceRelation.Union {
+		if rewrite.TupleToUserset == "" {
+			child, err := expand(ctx, objectType, objectID, rewrite.Computed, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			tree.Children = append(tree.Children, *child)
+			continue
+		}
+
+		parents, err := repository.Relation.List(ctx, domain.RelationTupleOptions{
+			ObjectType: objectType,
+			ObjectID:   objectID,
+			Relation:   rewrite.TupleToUserset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			child, err := expand(ctx, parent.SubjectType, parent.SubjectID, rewrite.Computed, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			tree.Children = append(tree.Children, *child)
+		}
+	}
+
+	return tree, nil
+}
+
+// ListObjects returns the IDs of objects of objectType on which subject has relation.
+func ListObjects(ctx context.Context, objectType, relation, subject string) ([]string, error) {
+	namespace, err := Namespace(objectType)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := namespace.Relation(relation); !ok {
+		return nil, exception.NewBadRequestException(fmt.Sprintf("relation %s is not defined on %s", relation, objectType))
+	}
+
+	subjectType, subjectID, subjectRelation, err := domain.ParseSubject(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	rewrites := make([]domain.RelationRewriteRule, 0)
+	for _, namespace := range namespaces {
+		rewrites = append(rewrites, namespace.Rewrites()...)
+	}
+
+	return repository.Relation.ListObjectIDs(ctx, domain.RelationObjectOptions{
+		ObjectType:      objectType,
+		Relation:        relation,
+		SubjectType:     subjectType,
+		SubjectID:       subjectID,
+		SubjectRelation: subjectRelation,
+		Rewrites:        rewrites,
+	})
+}
+
+// CheckRelation checks relation on object for subject on behalf of user.
+// The subject defaults to the user, checking another subject requires managing the object.
+func CheckRelation(ctx context.Context, user domain.User, object, relation, subject string) (*dto.RelationCheckSchema, error) {
+	self := domain.UserNamespace + ":" + user.ID.String()
+	if subject == "" {
+		subject = self
+	}
+
+	if subject != self {
+		if err := authorizeManage(ctx, user, object); err != nil {
+			return nil, err
+		}
+	}
+
+	allowed, err := Check(ctx, object, relation, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RelationCheckSchema{
+		Object:   object,
+		Relation: relation,
+		Subject:  subject,
+		Allowed:  allowed,
+	}, nil
+}
+
+// ExpandRelation expands relation on object on behalf of user, who must manage the object.
+func ExpandRelation(ctx context.Context, user domain.User, object, relation string) (*dto.RelationTreeSchema, error) {
+	if err := authorizeManage(ctx, user, object); err != nil {
+		return nil, err
+	}
+	return Expand(ctx, object, relation)
+}
+
+// RelationObjects lists the objects of objectType on which user has relation.
+func RelationObjects(ctx context.Context, user domain.User, objectType, relation string) (*dto.RelationObjectsSchema, error) {
+	objectIDs, err := ListObjects(ctx, objectType, relation, domain.UserNamespace+":"+user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RelationObjectsSchema{
+		ObjectType: objectType,
+		Relation:   relation,
+		ObjectIDs:  objectIDs,
+	}, nil
+}
+
+func authorizeManage(ctx context.Context, user domain.User, object string) error {
+	objectType, _, err := domain.ParseObject(object)
+	if err != nil {
+		return err
+	}
+
+	namespace, err := Namespace(objectType)
+	if err != nil {
+		return err
+	}
+
+	allowed := false
+	if namespace.Manage != "" {
+		allowed, err = Check(ctx, object, namespace.Manage, domain.UserNamespace+":"+user.ID.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	if !allowed {
+		return exception.NewForbiddenException(fmt.Sprintf("you are not allowed to inspect relations of %s", object))
+	}
+
+	return nil
+}
@@ -7,12 +7,15 @@ import (
 	"authorization/repository"
 	"context"
 	"errors"
-	"time"
 
 	"github.com/jackc/pgx/v5"
 	uuid "github.com/satori/go.uuid"
 )
 
+// memberPreviewSize is the number of members shown along with a team, the whole list is served by Members
+const memberPreviewSize = 3
+
+// Team returns the team with a summary of its members.
 func Team(ctx context.Context, id uuid.UUID, user domain.User) (*dto.TeamRetrievalSchema, error) {
 	team, err := repository.Team.Get(ctx, id)
 	if err != nil {
@@ -23,6 +26,8 @@ func Team(ctx context.Context, id uuid.UUID, user domain.User) (*dto.TeamRetriev
 	}
 	opts := domain.MembershipOptions{
 		TeamID:       id,
+		Limit:        memberPreviewSize,
+		SortBy:       domain.SortByRole,
 		IsSelectUser: true,
 		IsSelectRole: true,
 	}
@@ -30,93 +35,146 @@ func Team(ctx context.Context, id uuid.UUID, user domain.User) (*dto.TeamRetriev
 	if err != nil {
 		return nil, err
 	}
-	totalMemberships, err := repository.Membership.Count(ctx, opts)
+	roleCounts, err := repository.Membership.CountByRoles(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	var lastActiveAt time.Time
-	var membershipsList []dto.MembershipRetrievalSchema
+	membership, err := repository.Membership.GetByUser(ctx, id, user.ID)
+	var notFound exception.NotFoundException
+	if err != nil && !errors.As(err, &notFound) {
+		return nil, err
+	}
+	lastActiveAt := membership.LastActiveAt
 
+	var totalMemberships int64
+	numOfRoles := make(map[string]int64, len(roleCounts))
+	for role, count := range roleCounts {
+		numOfRoles[string(role)] = count
+		totalMemberships += count
+	}
+
+	var membershipsList []dto.MembershipRetrievalSchema
 	for _, membership := range memberships {
-		if membership.User.ID == user.ID {
-			lastActiveAt = membership.LastActiveAt
-		}
 		membershipsList = append(membershipsList, membership.Parse())
 	}
 
 	return &dto.TeamRetrievalSchema{
-		ID:           team.ID,
-		Name:         team.Name,
-		Description:  team.Description,
-		AvatarURL:    team.AvatarURL,
-		IsPersonal:   team.IsPersonal,
-		Creator:      team.Creator.PublicUser(),
-		LastActiveAt: lastActiveAt,
-		NumOfMembers: totalMemberships,
-		Memberships:  membershipsList,
-		CreatedAt:    team.CreatedAt,
-		UpdatedAt:    team.UpdatedAt,
+		ID:             team.ID,
+		Name:           team.Name,
+		Description:    team.Description,
+		AvatarURL:      team.AvatarURL,
+		IsPersonal:     team.IsPersonal,
+		IsDiscoverable: team.IsDiscoverable,
+		ParentID:       parentID(team),
+		Inheritance:    string(team.Inheritance),
+		ArchivedAt:     team.ArchivedAt,
+		PurgeAt:        team.PurgeAt,
+		Creator:        team.Creator.PublicUser(),
+		LastActiveAt:   lastActiveAt,
+		NumOfMembers:   totalMemberships,
+		NumOfRoles:     numOfRoles,
+		Memberships:    membershipsList,
+		CreatedAt:      team.CreatedAt,
+		UpdatedAt:      team.UpdatedAt,
 	}, nil
 }
 
-func Teams(ctx context.Context, user domain.User, name string, page, pageSize int) (dto.Pagination, error) {
-	var teams []interface{}
-	var totalMemberships int64
+// Teams lists the teams the user can access, including the teams reached through an organization or a parent team.
+// Archived teams are left out, see ArchivedTeams.
+func Teams(ctx context.Context, user domain.User, name string, limit int, cursor *domain.Cursor) (dto.CursorPagination, error) {
+	return teams(ctx, domain.TeamOptions{UserID: user.ID, Name: name, Limit: limit, Cursor: cursor})
+}
 
-	membershipOpts := domain.MembershipOptions{
-		UserID:       user.ID,
-		IsSelectTeam: true,
-		Limit:        pageSize,
-		Skip:         page,
-		Name:         name,
-	}
-	memberships, err := repository.Membership.List(ctx, membershipOpts)
+// ArchivedTeams lists the archived teams the user can access and still restore.
+func ArchivedTeams(ctx context.Context, user domain.User, name string, limit int, cursor *domain.Cursor) (dto.CursorPagination, error) {
+	return teams(ctx, domain.TeamOptions{UserID: user.ID, Name: name, Archived: true, Limit: limit, Cursor: cursor})
+}
+
+func teams(ctx context.Context, teamOpts domain.TeamOptions) (dto.CursorPagination, error) {
+	page := domain.Page{Limit: teamOpts.Limit, Descending: true, Cursor: teamOpts.Cursor}
+	teamOpts.Limit = page.Limit + 1
+
+	memberships, err := repository.Team.ListByUser(ctx, teamOpts)
 	if err != nil {
-		return dto.Pagination{}, err
+		return dto.CursorPagination{}, err
 	}
 
-	totalMemberships, err = repository.Membership.Count(ctx, membershipOpts)
+	totalMemberships, err := repository.Team.CountByUser(ctx, teamOpts)
 	if err != nil {
-		return dto.Pagination{}, err
+		return dto.CursorPagination{}, err
 	}
+
+	// the previews and the member counts of the whole page are read at once, the number of queries does not
+	// grow with the number of teams
+	teamIDs := make([]uuid.UUID, 0, len(memberships))
 	for _, membership := range memberships {
-		opts := domain.MembershipOptions{
-			TeamID:       membership.TeamID,
-			Limit:        3,
-			IsSelectUser: true,
-			IsSelectRole: true,
-		}
-		members, err := repository.Membership.List(ctx, opts)
-		if err != nil {
-			return dto.Pagination{}, err
-		}
-		totalMembers, err := repository.Membership.Count(ctx, opts)
-		if err != nil {
-			return dto.Pagination{}, err
-		}
+		teamIDs = append(teamIDs, membership.TeamID)
+	}
 
+	previews, err := repository.Membership.ListPreviews(ctx, teamIDs, memberPreviewSize)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	memberCounts, err := repository.Membership.CountByTeams(ctx, teamIDs)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	cursor := func(m domain.Membership) domain.Cursor { return m.TeamCursor() }
+	return cursorPage(memberships, page, totalMemberships, cursor, func(membership domain.Membership) (interface{}, error) {
 		var memberList []dto.MembershipRetrievalSchema
-		for _, member := range members {
-			data := dto.MembershipRetrievalSchema{
-				ID:   member.ID,
-				Role: string(member.Role.Name),
-				User: member.User.PublicUser(),
-			}
-			memberList = append(memberList, data)
+		for _, member := range previews[membership.TeamID] {
+			memberList = append(memberList, member.Parse())
 		}
-		teams = append(teams, dto.TeamRetrievalSchema{
+
+		return dto.TeamRetrievalSchema{
 			ID:           membership.Team.ID,
 			Name:         membership.Team.Name,
 			Description:  membership.Team.Description,
 			AvatarURL:    membership.Team.AvatarURL,
 			IsPersonal:   membership.Team.IsPersonal,
+			ParentID:     parentID(membership.Team),
+			Inheritance:  string(membership.Team.Inheritance),
+			Role:         string(membership.Role.Name),
+			RoleSource:   membership.Source,
+			ArchivedAt:   membership.Team.ArchivedAt,
+			PurgeAt:      membership.Team.PurgeAt,
 			Creator:      membership.Team.Creator.PublicUser(),
 			LastActiveAt: membership.LastActiveAt,
-			NumOfMembers: totalMembers,
+			NumOfMembers: memberCounts[membership.TeamID],
 			Memberships:  memberList,
 			CreatedAt:    membership.Team.CreatedAt,
 			UpdatedAt:    membership.Team.UpdatedAt,
-		})
+		}, nil
+	})
+}
+
+// Members lists the members of the team page by page, the next page starts after the cursor of the last member.
+func Members(ctx context.Context, opts domain.MembershipOptions) (dto.CursorPagination, error) {
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	opts.Limit = page.Limit + 1
+	opts.IsSelectUser = true
+	opts.IsSelectRole = true
+
+	memberships, err := repository.Membership.List(ctx, opts)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	totalMemberships, err := repository.Membership.Count(ctx, opts)
+	if err != nil {
+		return dto.CursorPagination{}, err
+	}
+
+	cursor := func(m domain.Membership) domain.Cursor { return m.Cursor(opts.SortBy) }
+	parse := func(m domain.Membership) (interface{}, error) { return m.Parse(), nil }
+	return cursorPage(memberships, page, totalMemberships, cursor, parse)
+}
+
+func parentID(team domain.Team) *uuid.UUID {
+	if !team.ParentID.Valid {
+		return nil
 	}
-	return dto.Paginate(page, pageSize, totalMemberships, teams), nil
+	return &team.ParentID.UUID
 }
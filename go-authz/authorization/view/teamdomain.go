@@ -0,0 +1,24 @@
+package view
+
+import (
+	"authorization/domain/dto"
+	"authorization/repository"
+	"context"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// TeamDomains returns the email domains claimed by the team along with the record verifying them.
+func TeamDomains(ctx context.Context, teamID uuid.UUID) ([]dto.TeamDomainRetrievalSchema, error) {
+	teamDomains, err := repository.TeamDomain.ListByTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.TeamDomainRetrievalSchema, 0, len(teamDomains))
+	for _, teamDomain := range teamDomains {
+		result = append(result, teamDomain.Parse())
+	}
+
+	return result, nil
+}
@@ -3,6 +3,7 @@ package view
 import (
 	"authorization/config"
 	"authorization/controller/exception"
+	"authorization/domain"
 	"authorization/domain/dto"
 	"authorization/infrastructure/persistence"
 	"authorization/repository"
@@ -62,16 +63,22 @@ func User(ctx context.Context, id uuid.UUID) (*dto.PublicUser, error) {
 	return user.PublicUser(), nil
 }
 
-func Users(ctx context.Context, page, pageSize int) (dto.Pagination, error) {
-	users, err := repository.User.List(ctx, page, pageSize)
+// Users lists the active users page by page, ordered by creation.
+func Users(ctx context.Context, opts domain.UserOptions) (dto.CursorPagination, error) {
+	page := domain.Page{Limit: opts.Limit, Descending: opts.Descending, Cursor: opts.Cursor}
+	opts.Limit = page.Limit + 1
+
+	users, err := repository.User.List(ctx, opts)
 	if err != nil {
-		return dto.Pagination{}, err
+		return dto.CursorPagination{}, err
 	}
 
 	totalData, err := repository.User.Count(ctx)
 	if err != nil {
-		return dto.Pagination{}, err
+		return dto.CursorPagination{}, err
 	}
 
-	return dto.Paginate(page, pageSize, totalData, users.PublicUsers()), nil
+	cursor := func(u domain.User) domain.Cursor { return u.Cursor() }
+	parse := func(u domain.User) (interface{}, error) { return u.PublicUser(), nil }
+	return cursorPage(users, page, totalData, cursor, parse)
 }
@@ -5,6 +5,7 @@ go 1.20
 require (
 	github.com/badoux/checkmail v1.2.1
 	github.com/hibiken/asynq v0.24.1
+	github.com/oklog/ulid/v2 v2.1.0
 	github.com/satori/go.uuid v1.2.0
 	github.com/sirupsen/logrus v1.9.0
 	github.com/spf13/viper v1.15.0
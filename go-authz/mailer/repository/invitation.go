@@ -2,6 +2,9 @@ package repository
 
 import (
 	"mailer/domain/model"
+	"time"
+
+	"github.com/oklog/ulid/v2"
 
 	uuid "github.com/satori/go.uuid"
 	"gorm.io/gorm"
@@ -19,6 +22,7 @@ type InvitationRepository interface {
 	Get(string) (*model.Invitation, error)
 	List(opts *model.InvitationOptions) ([]model.Invitation, error)
 	Delete(string, *gorm.DB) error
+	MarkSent(string) (bool, error)
 }
 
 // invitationRepository implements the InvitationRepository interface
@@ -97,3 +101,17 @@ func (repo *invitationRepository) Delete(id string, tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// MarkSent marks the invitation sent once its email is delivered, an invitation already answered or expired is left alone.
+// It reports whether the invitation was marked. The authorization service stores the ULID of the invitation in its binary form.
+func (repo *invitationRepository) MarkSent(id string) (bool, error) {
+	invitationID, err := ulid.Parse(id)
+	if err != nil {
+		return false, err
+	}
+
+	result := repo.db.Model(&model.Invitation{}).
+		Where("id = ? AND status = ?", invitationID[:], model.InvitationStatusPending).
+		Updates(map[string]interface{}{"status": model.InvitationStatusSent, "updated_at": time.Now().UTC()})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"mailer/domain/model"
+	"strings"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds the statements without a database, the last update is kept in statement.
+func dryRunDB(t *testing.T, statement **gorm.Statement) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Callback().Update().After("gorm:update").Register("test:statement", func(db *gorm.DB) {
+		*statement = db.Statement
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestMarkSent(t *testing.T) {
+	var statement *gorm.Statement
+	repo := NewInvitationRepository(dryRunDB(t, &statement))
+
+	invitationID := ulid.Make()
+	if _, err := repo.MarkSent(invitationID.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	if statement == nil {
+		t.Fatal("no update was built")
+	}
+
+	sql := statement.SQL.String()
+	if !strings.HasPrefix(sql, `UPDATE "invitations" SET`) || !strings.Contains(sql, "WHERE id = $") || !strings.Contains(sql, "AND status = $") {
+		t.Errorf("unexpected statement %s", sql)
+	}
+
+	var matchesID, matchesPending, setsSent bool
+	for _, v := range statement.Vars {
+		switch value := v.(type) {
+		case []byte:
+			matchesID = string(value) == string(invitationID[:])
+		case model.InvitationStatus:
+			matchesPending = matchesPending || value == model.InvitationStatusPending
+			setsSent = setsSent || value == model.InvitationStatusSent
+		}
+	}
+	if !matchesID {
+		t.Errorf("the invitation is not matched by its binary ULID: %v", statement.Vars)
+	}
+	if !matchesPending || !setsSent {
+		t.Errorf("only a pending invitation should be marked sent: %v", statement.Vars)
+	}
+}
+
+func TestMarkSentInvalidID(t *testing.T) {
+	var statement *gorm.Statement
+	repo := NewInvitationRepository(dryRunDB(t, &statement))
+
+	marked, err := repo.MarkSent("not-an-invitation")
+	if err == nil || marked {
+		t.Errorf("an invalid invitation ID should be rejected, got marked=%v err=%v", marked, err)
+	}
+	if statement != nil {
+		t.Errorf("no update should be built for an invalid invitation ID, got %s", statement.SQL.String())
+	}
+}
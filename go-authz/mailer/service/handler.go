@@ -51,12 +51,22 @@ func SendEmail(to string, subject string, data interface{}, templateName string)
 	return err
 }
 
-func SendEmailTask(to, subject, templateName string, data interface{}) {
+func SendEmailTask(uow *UnitOfWork, to, subject, templateName string, data map[string]interface{}) {
 	err := SendEmail(to, subject, data, templateName)
-	if err == nil {
-		log.Info(fmt.Sprintf("Email sent to %s", to))
-	} else {
+	if err != nil {
 		log.Error(err)
+		return
+	}
+	log.Info(fmt.Sprintf("Email sent to %s", to))
+
+	// an invitation email delivered marks the invitation sent
+	if invitationID, ok := data["InvitationID"].(string); ok {
+		marked, err := uow.Invitation.MarkSent(invitationID)
+		if err != nil {
+			log.Error(err)
+		} else if !marked {
+			log.Warn(fmt.Sprintf("Invitation %s was not marked sent, it is no longer pending", invitationID))
+		}
 	}
 }
 
@@ -87,7 +97,7 @@ func HandleEmailTask(ctx context.Context, t *asynq.Task) error {
 	templateName := payload.TemplateName
 
 	log.Info(fmt.Sprintf("Sending Email to %s\n", payload.To))
-	go SendEmailTask(to, subject, templateName, data)
+	go SendEmailTask(uow, to, subject, templateName, data)
 
 	return nil
 }
@@ -119,7 +129,7 @@ func HandleDelayedEmailTask(ctx context.Context, t *asynq.Task) error {
 	templateName := payload.TemplateName
 
 	log.Info(fmt.Sprintf("Sending Email to %s\n", payload.To))
-	go SendEmailTask(to, subject, templateName, data)
+	go SendEmailTask(uow, to, subject, templateName, data)
 
 	return nil
 }